@@ -21,6 +21,8 @@ such as 'apiserver', 'authzserver', and 'migrate'.
 func Execute() {
 	// 注册所有子命令
 	rootCmd.AddCommand(NewAPIServerCommand())
+	rootCmd.AddCommand(NewRotateMasterKeyCommand())
+	rootCmd.AddCommand(NewSignerCommand())
 	// 未来可以在这里添加其他子命令，例如：
 	// rootCmd.AddCommand(NewAuthzServerCommand())
 
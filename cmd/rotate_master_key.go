@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver"
+)
+
+// NewRotateMasterKeyCommand 创建 rotate-master-key 子命令
+// 该命令将所有助记词、TOTP 密钥与 API Key 签名密钥的数据密钥重新包装到当前主密钥下，不解密助记词本身
+func NewRotateMasterKeyCommand() *cobra.Command {
+	var (
+		configPath string
+		batchSize  int
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rotate-master-key",
+		Short: "Re-wrap all mnemonic, TOTP and API key signing data keys under the active master key",
+		Long: `Re-wrap the per-record data keys of all mnemonic seeds, TOTP secrets and API key
+signing secrets with the master key
+configured as key_management.active_key_id. Keep the previous master keys in
+key_management.master_keys until the rotation has completed.
+
+Only the wrapped data keys are replaced; seeds are never decrypted. Records written
+before envelope encryption was enabled are sealed as-is. The command is idempotent
+and can be re-run after an interruption.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return apiserver.RotateMasterKey(configPath, batchSize, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "config file (default is ./config.yaml)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 100, "number of records processed per batch")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only report the records that would be re-wrapped")
+
+	return cmd
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/signer"
+)
+
+// NewSignerCommand 创建 signer 子命令
+// 该命令启动独立的远程签名进程，私钥只保存在该进程中
+func NewSignerCommand() *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "signer",
+		Short: "Start the remote signer process",
+		Long: `Start a standalone signer that holds private keys as keystore files and
+signs transactions and hashes over a simple HTTP/JSON protocol.
+Wallets with signer_type 'remote' are signed by this process instead of the API server.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return signer.Run(configPath)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "config file (default is ./config.yaml)")
+
+	return cmd
+}
@@ -13,14 +13,44 @@ type Config struct {
 	Chains   []BlockchainConfig `mapstructure:"chains"   yaml:"chains"`
 	CORS     CORSConfig         `mapstructure:"cors"     yaml:"cors"`
 	Limit    LimitConfig        `mapstructure:"limit"    yaml:"limit"`
+	Tracker  TrackerConfig      `mapstructure:"tracker"  yaml:"tracker"`
+
+	KeyExport     KeyExportConfig     `mapstructure:"key_export"     yaml:"key_export"`
+	KeyManagement KeyManagementConfig `mapstructure:"key_management" yaml:"key_management"`
+	Signer        SignerConfig        `mapstructure:"signer"         yaml:"signer"`
+	SIWE          SIWEConfig          `mapstructure:"siwe"           yaml:"siwe"`
+
+	TokenRevocation TokenRevocationConfig `mapstructure:"token_revocation" yaml:"token_revocation"`
+	Admin           AdminConfig           `mapstructure:"admin"            yaml:"admin"`
+	TOTP            TOTPConfig            `mapstructure:"totp"             yaml:"totp"`
+	APIKey          APIKeyConfig          `mapstructure:"api_key"          yaml:"api_key"`
+	Lockout         LockoutConfig         `mapstructure:"lockout"          yaml:"lockout"`
+	PasswordReset   PasswordResetConfig   `mapstructure:"password_reset"   yaml:"password_reset"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
 	Port        int    `mapstructure:"port"`
 	Environment string `mapstructure:"environment"`
-	JWTSecret   string `mapstructure:"jwt_secret"`
-	JWTDuration string `mapstructure:"jwt_duration"`
+	JWTSecret   string `mapstructure:"jwt_secret"`   // HS256 共享密钥，配置 jwt_keys 后不再使用
+	JWTDuration string `mapstructure:"jwt_duration"` // 访问令牌有效期
+
+	// 非对称签名 (RS256 / EdDSA)：使用 JWTSigningKeyID 对应的私钥签名，JWTKeys 中的全部密钥均可用于校验
+	JWTSigningKeyID string         `mapstructure:"jwt_signing_key_id"`
+	JWTKeys         []JWTKeyConfig `mapstructure:"jwt_keys"`
+
+	RefreshTokenDuration string `mapstructure:"refresh_token_duration"` // 会话自登录起的有效期，刷新令牌轮换时不会延长
+
+	// TrustedProxies 是受信任的反向代理 IP / CIDR，仅来自这些地址的 X-Forwarded-For / X-Real-IP 会被采信；
+	// 为空时不信任任何代理，客户端 IP 取 TCP 连接的对端地址
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// JWTKeyConfig 单把 JWT 密钥的 PEM 文件，算法由密钥类型决定：RSA 为 RS256，Ed25519 为 EdDSA
+type JWTKeyConfig struct {
+	ID             string `yaml:"id"               mapstructure:"id"`               // 写入令牌头部的 kid
+	PrivateKeyFile string `yaml:"private_key_file" mapstructure:"private_key_file"` // PKCS#8（或 PKCS#1 RSA）私钥，签名密钥必填
+	PublicKeyFile  string `yaml:"public_key_file"  mapstructure:"public_key_file"`  // PKIX 公钥，未配置私钥时使用，仅用于校验
 }
 
 // DatabaseConfig 数据库配置
@@ -44,9 +74,40 @@ type BlockchainConfig struct {
 	RPCUrl      string `yaml:"rpc_url"      mapstructure:"rpc_url"`
 	ExplorerUrl string `yaml:"explorer_url" mapstructure:"explorer_url"`
 
+	// NativeSymbol 原生币符号，如 ETH、POL，为空时默认为 ETH
+	NativeSymbol string `yaml:"native_symbol" mapstructure:"native_symbol"`
+
 	// 假设 YAML 中有更复杂的结构，例如 contract_addresses:
 	ContractAddresses map[string]string `yaml:"contract_addresses" mapstructure:"contract_addresses"`
 	IsTestnet         bool              `yaml:"is_testnet"         mapstructure:"is_testnet"` // 对应可选字段
+
+	// Confirmations 交易被视为最终确认所需的区块确认数，0 表示使用默认值
+	Confirmations uint64 `yaml:"confirmations" mapstructure:"confirmations"`
+
+	// Gas 手续费估算策略，未配置的字段使用 web3client 中的默认值
+	Gas GasConfig `yaml:"gas" mapstructure:"gas"`
+
+	// Tokens 该链上支持的 ERC-20 代币
+	Tokens []TokenConfig `yaml:"tokens" mapstructure:"tokens"`
+}
+
+// TokenConfig ERC-20 代币配置
+type TokenConfig struct {
+	Symbol   string `yaml:"symbol"   mapstructure:"symbol"`
+	Address  string `yaml:"address"  mapstructure:"address"`
+	Decimals *uint8 `yaml:"decimals" mapstructure:"decimals"` // 为空时首次使用时从合约 decimals() 读取
+}
+
+// GasConfig 链级手续费估算配置 (EIP-1559 / legacy)
+type GasConfig struct {
+	Legacy             bool    `yaml:"legacy"                mapstructure:"legacy"`                // 强制使用 legacy gasPrice
+	HistoryBlocks      uint64  `yaml:"history_blocks"        mapstructure:"history_blocks"`        // eth_feeHistory 采样区块数
+	SlowPercentile     float64 `yaml:"slow_percentile"       mapstructure:"slow_percentile"`       // 慢速档小费百分位
+	StandardPercentile float64 `yaml:"standard_percentile"   mapstructure:"standard_percentile"`   // 标准档小费百分位
+	FastPercentile     float64 `yaml:"fast_percentile"       mapstructure:"fast_percentile"`       // 快速档小费百分位
+	BaseFeeMultiplier  float64 `yaml:"base_fee_multiplier"   mapstructure:"base_fee_multiplier"`   // maxFee = baseFee * multiplier + tip
+	MinPriorityFeeGwei float64 `yaml:"min_priority_fee_gwei" mapstructure:"min_priority_fee_gwei"` // 小费下限 (Gwei)
+	MaxFeeGwei         float64 `yaml:"max_fee_gwei"          mapstructure:"max_fee_gwei"`          // maxFee 上限 (Gwei)，0 表示不限制
 }
 
 // CORSConfig 是 CORS 相关的配置
@@ -66,6 +127,107 @@ type LimitConfig struct {
 	Bucket int     `yaml:"bucket" mapstructure:"bucket"` // 令牌桶的容量 (b)
 }
 
+// TrackerConfig 交易回执追踪 worker 配置
+type TrackerConfig struct {
+	Enable       bool   `yaml:"enable"        mapstructure:"enable"`        // 是否启动后台追踪
+	PollInterval string `yaml:"poll_interval" mapstructure:"poll_interval"` // 轮询间隔，如 "15s"
+	BatchSize    int    `yaml:"batch_size"    mapstructure:"batch_size"`    // 每轮最多处理的交易数
+	DropAfter    string `yaml:"drop_after"    mapstructure:"drop_after"`    // 交易从节点消失超过该时长后标记为 dropped
+}
+
+// KeyExportConfig Keystore 导出 / 助记词查看的安全配置
+type KeyExportConfig struct {
+	MaxAttempts         int    `yaml:"max_attempts"          mapstructure:"max_attempts"`          // 窗口期内每个用户允许的导出尝试次数（含失败）
+	Window              string `yaml:"window"                mapstructure:"window"`                // 频率限制窗口，如 "1h"
+	RequireSecondFactor bool   `yaml:"require_second_factor" mapstructure:"require_second_factor"` // 是否要求所有用户必须通过第二因子
+}
+
+// KeyManagementConfig 助记词信封加密的主密钥配置，provider 为空时不启用信封加密
+type KeyManagementConfig struct {
+	Provider    string            `yaml:"provider"      mapstructure:"provider"`      // local | kms
+	ActiveKeyID string            `yaml:"active_key_id" mapstructure:"active_key_id"` // 用于包装新数据密钥的主密钥 ID
+	MasterKeys  []MasterKeyConfig `yaml:"master_keys"   mapstructure:"master_keys"`   // local：全部可用主密钥（含轮换前的旧密钥）
+}
+
+// MasterKeyConfig 单把本地主密钥的来源，密钥内容为 base64 编码的 32 字节
+type MasterKeyConfig struct {
+	ID   string `yaml:"id"   mapstructure:"id"`
+	Env  string `yaml:"env"  mapstructure:"env"`  // 从环境变量读取（优先）
+	File string `yaml:"file" mapstructure:"file"` // 从文件读取
+}
+
+// SignerConfig 远程签名配置：apiserver 使用 RemoteURL 连接签名进程，signer 子命令使用 Server 启动签名进程
+type SignerConfig struct {
+	RemoteURL string `yaml:"remote_url" mapstructure:"remote_url"` // 为空时不支持 signer_type 为 remote 的钱包
+	AuthToken string `yaml:"auth_token" mapstructure:"auth_token"` // 双方共享的 Bearer Token
+	Timeout   string `yaml:"timeout"    mapstructure:"timeout"`    // 单次签名请求超时，如 "10s"
+
+	// Accounts 将远程签名进程持有的账户分配给用户，只有被分配的用户可登记并使用对应地址
+	Accounts []SignerAccountConfig `yaml:"accounts" mapstructure:"accounts"`
+
+	Server SignerServerConfig `yaml:"server" mapstructure:"server"`
+}
+
+// SignerAccountConfig 远程签名账户与用户的对应关系
+type SignerAccountConfig struct {
+	Address string `yaml:"address" mapstructure:"address"` // 远程签名进程持有的账户地址
+	UserID  uint   `yaml:"user_id" mapstructure:"user_id"` // 允许登记该地址的用户 ID
+}
+
+// SignerServerConfig 远程签名进程配置
+type SignerServerConfig struct {
+	Listen      string `yaml:"listen"       mapstructure:"listen"`       // 监听地址，默认 127.0.0.1:9100
+	KeystoreDir string `yaml:"keystore_dir" mapstructure:"keystore_dir"` // geth 格式的 Keystore 文件目录
+	PasswordEnv string `yaml:"password_env" mapstructure:"password_env"` // 解锁 Keystore 的密码所在环境变量
+}
+
+// SIWEConfig Sign-In with Ethereum (EIP-4361) 登录配置，链 ID 限定为 chains 中已配置的链
+type SIWEConfig struct {
+	Domains  []string `yaml:"domains"   mapstructure:"domains"`   // 允许出现在签名消息中的 domain (host[:port])，为空时禁用 SIWE
+	NonceTTL string   `yaml:"nonce_ttl" mapstructure:"nonce_ttl"` // nonce 有效期，如 "10m"
+}
+
+// TokenRevocationConfig 访问令牌吊销列表配置：吊销记录保存在数据库，查询结果缓存在进程内 LRU 中
+type TokenRevocationConfig struct {
+	CacheSize int    `yaml:"cache_size" mapstructure:"cache_size"` // LRU 缓存的最大条目数
+	CacheTTL  string `yaml:"cache_ttl"  mapstructure:"cache_ttl"`  // 未吊销结果的缓存时长，即其他实例上的吊销最长延迟生效时间，如 "30s"
+}
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	UserIDs []uint `yaml:"user_ids" mapstructure:"user_ids"` // 启动时授予 admin 角色的用户 ID，用于引导首个管理员
+}
+
+// TOTPConfig TOTP 第二因子配置
+type TOTPConfig struct {
+	Issuer      string `yaml:"issuer"        mapstructure:"issuer"`        // 身份验证器应用中显示的服务名称
+	MFATokenTTL string `yaml:"mfa_token_ttl" mapstructure:"mfa_token_ttl"` // 登录第二步 mfa_pending 令牌的有效期，如 "5m"
+}
+
+// APIKeyConfig 服务端集成 API Key 配置
+type APIKeyConfig struct {
+	MaxPerUser int    `yaml:"max_per_user" mapstructure:"max_per_user"` // 每个用户可持有的有效 API Key 数量上限
+	MaxTTL     string `yaml:"max_ttl"      mapstructure:"max_ttl"`      // API Key 的最长有效期，如 "8760h"，为空表示允许永不过期
+
+	SignatureSkew string `yaml:"signature_skew" mapstructure:"signature_skew"` // 签名请求的时间戳与服务器时间允许的最大偏差，如 "5m"
+}
+
+// LockoutConfig 登录密码与钱包密码的失败尝试限制，按用户名 / 钱包地址分别计数
+type LockoutConfig struct {
+	BackoffAfter    int    `yaml:"backoff_after"    mapstructure:"backoff_after"`    // 连续失败达到该次数后开始指数退避
+	BackoffBase     string `yaml:"backoff_base"     mapstructure:"backoff_base"`     // 首次退避时长，此后每次失败翻倍，如 "1s"
+	MaxFailures     int    `yaml:"max_failures"     mapstructure:"max_failures"`     // 连续失败达到该次数后锁定
+	LockoutDuration string `yaml:"lockout_duration" mapstructure:"lockout_duration"` // 锁定时长，如 "15m"
+	FailureWindow   string `yaml:"failure_window"   mapstructure:"failure_window"`   // 距上次失败超过该时长（且未锁定）后重新计数，如 "15m"
+}
+
+// PasswordResetConfig 找回密码的令牌配置
+type PasswordResetConfig struct {
+	TokenTTL    string `yaml:"token_ttl"    mapstructure:"token_ttl"`    // 重置令牌有效期，如 "30m"
+	MaxRequests int    `yaml:"max_requests" mapstructure:"max_requests"` // 每个用户在一个 token_ttl 内可申请的令牌数量
+	Delivery    string `yaml:"delivery"     mapstructure:"delivery"`     // 令牌投递方式：为空时不启用找回密码；log 写入服务日志，仅用于开发环境，生产环境拒绝启动
+}
+
 // LoadConfigFromFile 加载并解析配置文件
 func LoadConfigFromFile(configPath string) (*Config, error) {
 	// 设置配置文件的名称和类型
@@ -1,7 +1,10 @@
 package apiserver
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -12,6 +15,7 @@ import (
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/store"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/web3client"
 )
 
@@ -22,21 +26,59 @@ type App struct {
 
 	// 驱动层/工具层 (Drivers)
 	keyManager    crypto.KeyManager
+	seedCipher    crypto.EnvelopeCipher
+	remoteSigner  crypto.AccountSigner
 	clientManager web3client.ClientManager
+	feeOracle     web3client.FeeOracle
+	tokenRegistry web3client.TokenRegistry
+	balanceReader web3client.BalanceReader
 
 	// 存储层 (Stores)
-	userStore   service.UserStore
-	walletStore service.WalletStore
+	userStore    service.UserStore
+	walletStore  service.WalletStore
+	nonceStore   service.NonceStore
+	txStore      service.TransactionStore
+	exportStore  service.KeyExportLogStore
+	siweStore    service.SIWENonceStore
+	sessionStore service.SessionStore
+	revokeStore  service.TokenRevocationStore
+	totpStore    service.TOTPStore
+	apiKeyStore  service.APIKeyStore
+	attemptStore service.FailedAttemptStore
+	resetStore   service.PasswordResetStore
 
 	// 业务层 (Services)
-	jwtService    service.JWTService
-	userService   service.UserService
-	walletService service.WalletService
+	jwtService     service.JWTService
+	userService    service.UserService
+	walletService  service.WalletService
+	chainService   service.ChainService
+	siweService    service.SIWEService
+	sessionService service.SessionService
+
+	revocationService service.TokenRevocationService
+	totpService       service.TOTPService
+	adminService      service.AdminService
+	apiKeyService     service.APIKeyService
+	attemptLimiter    service.AttemptLimiter
+	passwordService   service.PasswordService
+
+	secondFactor     service.SecondFactorVerifier
+	keyExportService service.KeyExportService
 
 	// 控制器层 (Controllers)
 	authController   *controller.AuthController
 	userController   *controller.UserController
 	walletController *controller.WalletController
+	chainController  *controller.ChainController
+	siweController   *controller.SIWEController
+	totpController   *controller.TOTPController
+
+	keyExportController *controller.KeyExportController
+	adminController     *controller.AdminController
+	apiKeyController    *controller.APIKeyController
+
+	// 后台任务 (Workers)
+	receiptTracker *service.ReceiptTracker
 }
 
 // NewApp 创建并初始化应用容器
@@ -51,7 +93,16 @@ func NewApp(cfg *config.Config, db *gorm.DB) (*App, error) {
 	}
 
 	app.initStores()
-	app.initServices()
+
+	// workers 先于 services 初始化，维护任务复用已创建的后台追踪器
+	if err := app.initWorkers(); err != nil {
+		return nil, fmt.Errorf("failed to init workers: %w", err)
+	}
+
+	if err := app.initServices(); err != nil {
+		return nil, fmt.Errorf("failed to init services: %w", err)
+	}
+
 	app.initControllers()
 
 	return app, nil
@@ -61,54 +112,328 @@ func NewApp(cfg *config.Config, db *gorm.DB) (*App, error) {
 func (a *App) initDrivers() error {
 	a.keyManager = crypto.NewKeyManager()
 
+	seedCipher, err := newSeedCipher(a.cfg.KeyManagement)
+	if err != nil {
+		return fmt.Errorf("failed to init key management: %w", err)
+	}
+	a.seedCipher = seedCipher
+
+	remoteSigner, err := newRemoteSigner(a.cfg.Signer)
+	if err != nil {
+		return fmt.Errorf("failed to init remote signer: %w", err)
+	}
+	a.remoteSigner = remoteSigner
+
 	clientManager, err := web3client.NewClientManager(a.cfg.Chains)
 	if err != nil {
 		return fmt.Errorf("failed to create web3 client manager: %w", err)
 	}
 	a.clientManager = clientManager
+	a.feeOracle = web3client.NewFeeOracle(clientManager, a.cfg.Chains)
+
+	tokenRegistry, err := web3client.NewTokenRegistry(clientManager, a.cfg.Chains)
+	if err != nil {
+		return fmt.Errorf("failed to load token registry: %w", err)
+	}
+	a.tokenRegistry = tokenRegistry
+
+	balanceReader, err := web3client.NewBalanceReader(clientManager, a.cfg.Chains)
+	if err != nil {
+		return fmt.Errorf("failed to create balance reader: %w", err)
+	}
+	a.balanceReader = balanceReader
 
 	return nil
 }
 
+// newSeedCipher 根据 key_management 配置创建助记词信封加密器，未配置时返回 nil（不启用）
+func newSeedCipher(cfg config.KeyManagementConfig) (crypto.EnvelopeCipher, error) {
+	provider, err := crypto.NewMasterKeyProvider(cfg)
+	if errors.Is(err, crypto.ErrEnvelopeDisabled) {
+		logger.Logger.Warn("Key management is not configured, mnemonic seeds will not be envelope-encrypted")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.NewEnvelopeCipher(provider), nil
+}
+
+// newRemoteSigner 根据 signer 配置创建远程签名客户端，未配置 remote_url 时返回 nil（不启用）
+func newRemoteSigner(cfg config.SignerConfig) (crypto.AccountSigner, error) {
+	if cfg.RemoteURL == "" {
+		return nil, nil
+	}
+	if cfg.AuthToken == "" {
+		return nil, errors.New("signer.auth_token must be set when signer.remote_url is configured")
+	}
+
+	var timeout time.Duration
+	if cfg.Timeout != "" {
+		parsed, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signer timeout: %w", err)
+		}
+		timeout = parsed
+	}
+
+	return crypto.NewRemoteSigner(cfg.RemoteURL, cfg.AuthToken, timeout), nil
+}
+
 func (a *App) initStores() {
 	a.userStore = store.NewUsers(a.db)
 	a.walletStore = store.NewWallets(a.db)
+	a.nonceStore = store.NewNonces(a.db)
+	a.txStore = store.NewTransactions(a.db)
+	a.exportStore = store.NewKeyExportLogs(a.db)
+	a.siweStore = store.NewSIWENonces(a.db)
+	a.sessionStore = store.NewSessions(a.db)
+	a.revokeStore = store.NewTokenRevocations(a.db)
+	a.totpStore = store.NewTOTPs(a.db)
+	a.apiKeyStore = store.NewAPIKeys(a.db)
+	a.attemptStore = store.NewFailedAttempts(a.db)
+	a.resetStore = store.NewPasswordResets(a.db)
+}
+
+func (a *App) initServices() error {
+	jwtService, err := service.NewJWTService(a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create jwt service: %w", err)
+	}
+	a.jwtService = jwtService
+
+	attemptLimiter, err := service.NewAttemptLimiter(a.attemptStore, a.cfg.Lockout)
+	if err != nil {
+		return fmt.Errorf("failed to create attempt limiter: %w", err)
+	}
+	a.attemptLimiter = attemptLimiter
+	a.userService = service.NewUserService(a.userStore, a.attemptLimiter)
+
+	revocationService, err := service.NewTokenRevocationService(a.revokeStore, a.sessionStore, a.userStore, a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create token revocation service: %w", err)
+	}
+	a.revocationService = revocationService
+
+	sessionService, err := service.NewSessionService(a.sessionStore, a.userStore, a.jwtService, a.revocationService, a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create session service: %w", err)
+	}
+	a.sessionService = sessionService
+
+	siweService, err := service.NewSIWEService(a.userStore, a.siweStore, a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create siwe service: %w", err)
+	}
+	a.siweService = siweService
+
+	totpService, err := service.NewTOTPService(
+		a.totpStore,
+		a.userStore,
+		a.jwtService,
+		a.revocationService,
+		a.seedCipher,
+		a.attemptLimiter,
+		a.cfg,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create totp service: %w", err)
+	}
+	a.totpService = totpService
+	a.secondFactor = totpService
+
+	a.walletService = service.NewWalletService(
+		a.walletStore,
+		a.nonceStore,
+		a.txStore,
+		a.keyManager,
+		a.seedCipher,
+		a.remoteSigner,
+		a.clientManager,
+		a.feeOracle,
+		a.tokenRegistry,
+		a.balanceReader,
+		a.secondFactor,
+		a.attemptLimiter,
+		a.cfg,
+	)
+	a.chainService = service.NewChainService(a.clientManager, a.feeOracle)
+
+	keyExportService, err := service.NewKeyExportService(
+		a.userStore,
+		a.walletStore,
+		a.exportStore,
+		a.keyManager,
+		a.seedCipher,
+		a.secondFactor,
+		a.cfg.KeyExport,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create key export service: %w", err)
+	}
+	a.keyExportService = keyExportService
+
+	apiKeyService, err := service.NewAPIKeyService(a.apiKeyStore, a.userStore, a.secondFactor, a.seedCipher, a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create api key service: %w", err)
+	}
+	a.apiKeyService = apiKeyService
+
+	notifier, err := service.NewPasswordResetNotifier(a.cfg.PasswordReset, a.cfg.Server.Environment)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset notifier: %w", err)
+	}
+	if notifier == nil {
+		logger.Logger.Warn("Password reset delivery is not configured, password reset requests will be rejected")
+	}
+	passwordService, err := service.NewPasswordService(
+		a.userStore,
+		a.resetStore,
+		notifier,
+		a.secondFactor,
+		a.revocationService,
+		a.attemptLimiter,
+		a.cfg.PasswordReset,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create password service: %w", err)
+	}
+	a.passwordService = passwordService
+
+	jobs, err := a.maintenanceJobs()
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance jobs: %w", err)
+	}
+	a.adminService = service.NewAdminService(
+		a.userStore,
+		a.walletStore,
+		a.revocationService,
+		a.attemptLimiter,
+		jobs,
+	)
+
+	// admin.user_ids 中的用户在启动时被授予 admin 角色，用于引导首个管理员
+	if err := a.adminService.BootstrapAdmins(context.Background(), a.cfg.Admin.UserIDs); err != nil {
+		return fmt.Errorf("failed to bootstrap admins: %w", err)
+	}
+
+	return nil
 }
 
-func (a *App) initServices() {
-	a.jwtService = service.NewJWTService(a.cfg)
-	a.userService = service.NewUserService(a.userStore, a.jwtService)
+// maintenanceJobs 创建可由管理接口触发的维护任务，依赖未启用的任务不注册
+func (a *App) maintenanceJobs() (map[string]service.MaintenanceJob, error) {
+	jobs := map[string]service.MaintenanceJob{
+		service.JobPurgeExpired: service.NewPurgeExpiredJob(
+			a.revokeStore,
+			a.siweStore,
+			a.sessionStore,
+			a.apiKeyStore,
+			a.attemptStore,
+			a.resetStore,
+		),
+	}
+
+	// 启用后台追踪时复用同一实例（Poll 互斥且共享游标）；未启用时单独创建，仍可手动更新交易状态
+	tracker := a.receiptTracker
+	if tracker == nil {
+		var err error
+		tracker, err = service.NewReceiptTracker(a.txStore, a.clientManager, a.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create receipt tracker: %w", err)
+		}
+	}
+	jobs[service.JobPollReceipts] = service.NewPollReceiptsJob(tracker)
+
+	if a.seedCipher != nil {
+		rotator := service.NewMasterKeyRotator(a.walletStore, a.totpStore, a.apiKeyStore, a.seedCipher, 0)
+		jobs[service.JobRotateMasterKey] = service.NewRotateMasterKeyJob(rotator)
+	}
 
-	a.walletService = service.NewWalletService(a.walletStore, a.keyManager, a.clientManager, a.cfg)
+	return jobs, nil
 }
 
 func (a *App) initControllers() {
-	a.authController = controller.NewAuthController(a.userService, a.jwtService)
-	a.userController = controller.NewUserController(a.userService)
+	a.authController = controller.NewAuthController(
+		a.userService,
+		a.sessionService,
+		a.jwtService,
+		a.revocationService,
+		a.totpService,
+	)
+	a.userController = controller.NewUserController(a.userService, a.passwordService)
 	a.walletController = controller.NewWalletController(a.walletService)
+	a.chainController = controller.NewChainController(a.chainService)
+	a.siweController = controller.NewSIWEController(a.siweService, a.sessionService, a.totpService)
+	a.totpController = controller.NewTOTPController(a.totpService)
+	a.keyExportController = controller.NewKeyExportController(a.keyExportService)
+	a.adminController = controller.NewAdminController(a.adminService, a.revocationService)
+	a.apiKeyController = controller.NewAPIKeyController(a.apiKeyService)
+}
+
+// initWorkers 初始化后台任务，未启用的任务保持为 nil
+func (a *App) initWorkers() error {
+	if !a.cfg.Tracker.Enable {
+		return nil
+	}
+
+	tracker, err := service.NewReceiptTracker(a.txStore, a.clientManager, a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create receipt tracker: %w", err)
+	}
+	a.receiptTracker = tracker
+
+	return nil
+}
+
+// StartWorkers 启动所有已启用的后台任务
+func (a *App) StartWorkers() {
+	if a.receiptTracker != nil {
+		a.receiptTracker.Start()
+	}
+}
+
+// StopWorkers 停止所有后台任务，并等待其在 ctx 超时前退出
+func (a *App) StopWorkers(ctx context.Context) error {
+	if a.receiptTracker != nil {
+		return a.receiptTracker.Stop(ctx)
+	}
+	return nil
 }
 
-// InitRouter 初始化并返回配置好的 Gin Engine
-func (a *App) InitRouter() *gin.Engine {
+// InitRouter 初始化并返回配置好的 Gin Engine，路由配置无效时返回错误
+func (a *App) InitRouter() (*gin.Engine, error) {
 	if a.cfg == nil {
 		panic("Application config (a.cfg) is nil!")
 	}
 	// 创建配置对象，将所有控制器和服务注入
 	routerCfg := &router.RouterConfig{
-		ServerCfg:        &a.cfg.Server,
-		CORSConfig:       &a.cfg.CORS,
-		LimitConfig:      &a.cfg.Limit,
-		JWTService:       a.jwtService,
+		ServerCfg:     &a.cfg.Server,
+		CORSConfig:    &a.cfg.CORS,
+		LimitConfig:   &a.cfg.Limit,
+		JWTService:    a.jwtService,
+		TokenDenylist: a.revocationService,
+
+		APIKeyAuthenticator: a.apiKeyService,
+		SignatureVerifier:   a.apiKeyService,
+
 		AuthController:   a.authController,
 		UserController:   a.userController,
 		WalletController: a.walletController,
+		ChainController:  a.chainController,
+		SIWEController:   a.siweController,
+		TOTPController:   a.totpController,
+
+		KeyExportController: a.keyExportController,
+		AdminController:     a.adminController,
+		APIKeyController:    a.apiKeyController,
 	}
 	// 调用 router 包中的函数来构建 Engine
 	return router.NewRouter(routerCfg)
 }
 
 // Router 返回配置好的 Gin Engine
-func (a *App) Router() *gin.Engine {
+func (a *App) Router() (*gin.Engine, error) {
 	return a.InitRouter()
 }
 
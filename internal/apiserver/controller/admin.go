@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// AdminController 封装了管理接口的控制器，各接口所需角色见 router
+type AdminController struct {
+	adminService      service.AdminService
+	revocationService service.TokenRevocationService
+}
+
+// NewAdminController 创建并返回新的 AdminController 实例
+func NewAdminController(
+	adminService service.AdminService,
+	revocationService service.TokenRevocationService,
+) *AdminController {
+	return &AdminController{
+		adminService:      adminService,
+		revocationService: revocationService,
+	}
+}
+
+// SetRoleRequest 定义变更用户角色的请求体
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user operator admin auditor"`
+}
+
+// ListUsers 处理分页查询用户的请求 (GET /admin/users)
+// 查询参数：role、disabled (true/false)、cursor、limit
+func (ctrl *AdminController) ListUsers(c *gin.Context) {
+	query := &service.UserQuery{
+		Role:   c.Query("role"),
+		Cursor: c.Query("cursor"),
+	}
+
+	if v := c.Query("disabled"); v != "" {
+		disabled, err := strconv.ParseBool(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "查询参数格式错误")
+			return
+		}
+		query.Disabled = &disabled
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "查询参数格式错误")
+			return
+		}
+		query.Limit = limit
+	}
+
+	page, err := ctrl.adminService.ListUsers(c.Request.Context(), query)
+	if err != nil {
+		ctrl.handleError(c, 0, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, page, "用户列表查询成功")
+}
+
+// GetUser 处理查询单个用户的请求 (GET /admin/users/:id)
+func (ctrl *AdminController) GetUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.adminService.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, user, "用户查询成功")
+}
+
+// ListUserWallets 处理查询用户钱包的请求 (GET /admin/users/:id/wallets)
+func (ctrl *AdminController) ListUserWallets(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	wallets, err := ctrl.adminService.ListUserWallets(c.Request.Context(), userID)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, wallets, "钱包列表查询成功")
+}
+
+// SetUserRole 处理变更用户角色的请求 (PUT /admin/users/:id/role)
+func (ctrl *AdminController) SetUserRole(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	user, err := ctrl.adminService.SetUserRole(c.Request.Context(), actorID, userID, req.Role)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, user, "用户角色已变更，用户需重新登录")
+}
+
+// DisableUser 处理停用账户的请求 (POST /admin/users/:id/disable)
+func (ctrl *AdminController) DisableUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	user, err := ctrl.adminService.DisableUser(c.Request.Context(), actorID, userID)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, user, "账户已停用")
+}
+
+// EnableUser 处理重新启用账户的请求 (POST /admin/users/:id/enable)
+func (ctrl *AdminController) EnableUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	user, err := ctrl.adminService.EnableUser(c.Request.Context(), actorID, userID)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, user, "账户已启用")
+}
+
+// UnlockUser 处理解除用户登录与钱包密码锁定的请求 (POST /admin/users/:id/unlock)
+func (ctrl *AdminController) UnlockUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	result, err := ctrl.adminService.UnlockUser(c.Request.Context(), actorID, userID)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, result, "已解除锁定")
+}
+
+// RevokeUserTokens 处理吊销指定用户全部令牌的请求 (POST /admin/users/:id/tokens/revoke)
+func (ctrl *AdminController) RevokeUserTokens(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	before, ok := bindRevokeBefore(c)
+	if !ok {
+		return
+	}
+
+	revocation, err := ctrl.revocationService.RevokeUserTokens(c.Request.Context(), userID, before)
+	if err != nil {
+		handleRevokeTokensError(c, userID, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, revocation, "用户令牌已吊销")
+}
+
+// ListJobs 处理查询可触发维护任务的请求 (GET /admin/jobs)
+func (ctrl *AdminController) ListJobs(c *gin.Context) {
+	response.Success(c, http.StatusOK, gin.H{"jobs": ctrl.adminService.ListJobs()}, "维护任务查询成功")
+}
+
+// RunJob 处理触发维护任务的请求 (POST /admin/jobs/:name)，任务同步执行，完成后返回结果
+func (ctrl *AdminController) RunJob(c *gin.Context) {
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	name := c.Param("name")
+	result, err := ctrl.adminService.RunJob(c.Request.Context(), actorID, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrJobNotFound):
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "维护任务不存在或未启用")
+		case errors.Is(err, service.ErrJobRunning):
+			response.Error(c, http.StatusConflict, response.CodeResourceExists, "维护任务正在执行中")
+		default:
+			// 任务失败已在 service 层记录日志
+			response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "维护任务执行失败")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, result, "维护任务执行完成")
+}
+
+// handleError 将管理接口的业务错误映射为 HTTP 响应
+func (ctrl *AdminController) handleError(c *gin.Context, userID uint, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "用户不存在")
+	case errors.Is(err, service.ErrInvalidRole), errors.Is(err, service.ErrInvalidCursor):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的角色或分页游标")
+	case errors.Is(err, service.ErrCannotModifySelf):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不能变更当前登录用户自己的角色或状态")
+	default:
+		logger.Logger.Error("Admin operation failed due to internal error", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "操作失败，请稍后重试")
+	}
+}
+
+// parseUserIDParam 解析路径参数 :id，失败时写入错误响应并返回 false
+func parseUserIDParam(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || userID == 0 {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的用户 ID")
+		return 0, false
+	}
+	return uint(userID), true
+}
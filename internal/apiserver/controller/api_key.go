@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// APIKeyController 封装了 API Key 管理的控制器，仅接受 JWT 认证
+type APIKeyController struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyController 创建并返回新的 APIKeyController 实例
+func NewAPIKeyController(apiKeyService service.APIKeyService) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKeyRequest 定义创建 API Key 的请求体
+type CreateAPIKeyRequest struct {
+	Name             string     `json:"name"   binding:"required,max=100"`
+	Scopes           []string   `json:"scopes" binding:"required,min=1,dive,oneof=wallet:read wallet:transfer tx:read"`
+	AllowedIPs       []string   `json:"allowed_ips"`        // IP 或 CIDR，为空表示不限
+	ExpiresAt        *time.Time `json:"expires_at"`         // RFC3339，为空表示永不过期
+	RequireSignature bool       `json:"require_signature"`  // 为 true 时该密钥的请求必须携带 X-Signature 签名
+	SecondFactorCode string     `json:"second_factor_code"` // 已绑定第二因子时必填
+}
+
+// Create 处理创建 API Key 的请求 (POST /api-keys)，完整密钥与签名密钥只在响应中出现一次
+func (ctrl *APIKeyController) Create(c *gin.Context) {
+	var req CreateAPIKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	key, err := ctrl.apiKeyService.Create(c.Request.Context(), &service.CreateAPIKeyParams{
+		UserID:           userID,
+		Name:             req.Name,
+		Scopes:           req.Scopes,
+		AllowedIPs:       req.AllowedIPs,
+		ExpiresAt:        req.ExpiresAt,
+		RequireSignature: req.RequireSignature,
+		SecondFactorCode: req.SecondFactorCode,
+	})
+	if err != nil {
+		// 第二因子验证码连续错误导致的退避或锁定
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, service.ErrAPIKeyParamInvalid):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的权限范围、IP 白名单或过期时间")
+		case errors.Is(err, service.ErrAPIKeyLimitExceeded):
+			response.Error(c, http.StatusConflict, response.CodeResourceExists, "有效的 API Key 数量已达上限，请先吊销不再使用的密钥")
+		case errors.Is(err, service.ErrSecondFactorRequired):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该操作需要第二因子验证码")
+		case errors.Is(err, service.ErrSecondFactorInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+		default:
+			logger.Logger.Error("Failed to create api key", zap.Uint("user_id", userID), zap.Error(err))
+			response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "创建 API Key 失败，请稍后重试")
+		}
+		return
+	}
+
+	// 响应包含完整密钥，禁止缓存
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusCreated, key, "API Key 创建成功，请立即妥善保存，完整密钥不会再次显示")
+}
+
+// List 处理查询 API Key 列表的请求 (GET /api-keys)
+func (ctrl *APIKeyController) List(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	keys, err := ctrl.apiKeyService.List(c.Request.Context(), userID)
+	if err != nil {
+		logger.Logger.Error("Failed to list api keys", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "查询 API Key 失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, keys, "API Key 查询成功")
+}
+
+// Revoke 处理吊销 API Key 的请求 (DELETE /api-keys/:id)
+func (ctrl *APIKeyController) Revoke(c *gin.Context) {
+	keyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || keyID == 0 {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的 API Key ID")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	if err := ctrl.apiKeyService.Revoke(c.Request.Context(), userID, uint(keyID)); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "API Key 不存在或已被吊销")
+			return
+		}
+
+		logger.Logger.Error("Failed to revoke api key", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "吊销 API Key 失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "API Key 已吊销")
+}
@@ -1,27 +1,44 @@
 package controller
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
 )
 
 // AuthController 封装了认证相关的控制器
 type AuthController struct {
-	userService service.UserService
-	jwtService  service.JWTService
+	userService       service.UserService
+	sessionService    service.SessionService
+	jwtService        service.JWTService
+	revocationService service.TokenRevocationService
+	totpService       service.TOTPService
 }
 
 // NewAuthController 创建并返回新的 AuthController 实例
-func NewAuthController(userService service.UserService, jwtService service.JWTService) *AuthController {
+func NewAuthController(
+	userService service.UserService,
+	sessionService service.SessionService,
+	jwtService service.JWTService,
+	revocationService service.TokenRevocationService,
+	totpService service.TOTPService,
+) *AuthController {
 	return &AuthController{
-		userService: userService,
-		jwtService:  jwtService,
+		userService:       userService,
+		sessionService:    sessionService,
+		jwtService:        jwtService,
+		revocationService: revocationService,
+		totpService:       totpService,
 	}
 }
 
@@ -31,6 +48,34 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required,min=8"`
 }
 
+// RefreshRequest 定义刷新令牌 / 登出请求结构
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+}
+
+// RevokeTokensRequest 定义吊销令牌的请求结构，请求体可为空
+type RevokeTokensRequest struct {
+	Before *time.Time `json:"before"` // RFC 3339 时间，吊销该时间及之前签发的令牌，默认当前时间
+}
+
+// MFAVerifyRequest 定义登录第二步的请求结构
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code"      binding:"required,max=32"` // 6 位 TOTP 验证码或恢复码
+}
+
+// LoginResponse 定义登录成功的响应体。
+// 用户已绑定 TOTP 时不返回令牌，mfa_required 为 true，需使用 mfa_token 调用 /auth/mfa/verify。
+type LoginResponse struct {
+	*service.TokenPair
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+
+	MFARequired  bool   `json:"mfa_required"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	MFAExpiresIn int64  `json:"mfa_expires_in,omitempty"` // mfa_token 有效期（秒）
+}
+
 // Login 处理用户登录请求 (POST /login)
 func (ctrl *AuthController) Login(c *gin.Context) {
 	var req LoginRequest
@@ -41,64 +86,286 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	}
 
 	// 验证用户名和密码
-	token, err := ctrl.userService.Login(req.Username, req.Password)
+	user, err := ctrl.userService.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
+		if respondAttemptLocked(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrUserDisabled) {
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户已被停用")
+			return
+		}
+		if !errors.Is(err, service.ErrInvalidCredentials) {
+			logger.Logger.Error("Login failed due to internal error",
+				zap.String("username", req.Username), zap.Error(err))
+		}
 		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户名或密码无效")
 		return
 	}
 
-	// 获取用户信息
-	user, err := ctrl.userService.FindUserByUsername(req.Username)
+	// 已绑定 TOTP 时返回 mfa_pending 令牌，否则创建会话并签发令牌
+	resp, err := completeLogin(c, ctrl.totpService, ctrl.sessionService, user)
 	if err != nil {
-		logger.Logger.Error("Login successful but failed to retrieve user data",
+		logger.Logger.Error("Login successful but failed to create session",
 			zap.String("username", req.Username), zap.Error(err))
 		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登录成功，但无法生成响应")
 		return
 	}
 
-	// 返回 Token 和用户信息
-	response.Success(c, http.StatusOK, gin.H{
-		"token":    token,
-		"user_id":  user.ID,
-		"username": user.Username,
+	// 返回令牌和用户信息
+	response.Success(c, http.StatusOK, resp, loginMessage(resp))
+}
+
+// VerifyMFA 处理登录第二步请求 (POST /auth/mfa/verify)，使用 mfa_token 与验证码换取令牌
+func (ctrl *AuthController) VerifyMFA(c *gin.Context) {
+	var req MFAVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	user, err := ctrl.totpService.CompleteLogin(c.Request.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		// 验证码连续错误导致的退避或锁定，mfa_token 已被消费，需重新登录
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, service.ErrMFATokenInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "mfa_token 无效、已过期或已使用，请重新登录")
+			return
+		case errors.Is(err, service.ErrSecondFactorInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误，请重新登录")
+			return
+		case errors.Is(err, service.ErrUserDisabled):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户已被停用")
+			return
+		}
+
+		logger.Logger.Error("MFA verification failed due to internal error", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登录失败，请稍后重试")
+		return
+	}
+
+	tokens, err := ctrl.sessionService.CreateSession(c.Request.Context(), user, sessionClient(c))
+	if err != nil {
+		logger.Logger.Error("MFA verification successful but failed to create session",
+			zap.Uint("user_id", user.ID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登录成功，但无法生成响应")
+		return
+	}
+
+	response.Success(c, http.StatusOK, LoginResponse{
+		TokenPair: tokens,
+		UserID:    user.ID,
+		Username:  user.Username,
 	}, "登录成功")
 }
 
-// Logout 处理用户登出请求 (POST /logout)
+// Logout 处理用户登出请求 (POST /logout)，注销刷新令牌所属的会话；
+// 携带 Authorization 访问令牌时一并吊销该令牌
 func (ctrl *AuthController) Logout(c *gin.Context) {
-	// JWT 是无状态的，登出主要由客户端处理（删除 Token）
-	// 服务端可以记录登出日志或将 Token 加入黑名单（需要 Redis）
+	var req RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	if err := ctrl.sessionService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		logger.Logger.Error("Failed to revoke session on logout", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登出失败，请稍后重试")
+		return
+	}
+
+	if accessToken, ok := middleware.BearerToken(c); ok {
+		if claims, err := ctrl.jwtService.ValidateToken(accessToken); err == nil {
+			if err := ctrl.revocationService.RevokeToken(c.Request.Context(), claims); err != nil {
+				logger.Logger.Error("Failed to revoke access token on logout",
+					zap.Uint("user_id", claims.UserID), zap.Error(err))
+				response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登出失败，请稍后重试")
+				return
+			}
+		}
+	}
+
 	response.Success(c, http.StatusOK, nil, "登出成功")
 }
 
-// Refresh 处理 Token 刷新请求 (POST /refresh)
+// Refresh 处理 Token 刷新请求 (POST /refresh)，刷新令牌使用后即轮换
 func (ctrl *AuthController) Refresh(c *gin.Context) {
-	// 从请求头或 Body 获取旧 Token
-	oldToken := c.GetHeader("Authorization")
-	if oldToken == "" {
-		var req struct {
-			Token string `json:"token" binding:"required"`
-		}
-		if err := c.ShouldBindJSON(&req); err != nil {
-			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+	var req RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	tokens, err := ctrl.sessionService.Refresh(c.Request.Context(), req.RefreshToken, sessionClient(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "刷新令牌已被使用，会话已注销，请重新登录")
+			return
+		case errors.Is(err, service.ErrRefreshTokenInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "刷新令牌无效或已过期")
 			return
 		}
-		oldToken = req.Token
-	} else {
-		// 移除 "Bearer " 前缀
-		if len(oldToken) > 7 && oldToken[:7] == "Bearer " {
-			oldToken = oldToken[7:]
+
+		logger.Logger.Error("Failed to refresh token due to internal error", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "Token 刷新失败")
+		return
+	}
+
+	response.Success(c, http.StatusOK, tokens, "Token 刷新成功")
+}
+
+// ListSessions 处理查询当前用户有效会话（设备）的请求 (GET /auth/sessions)
+func (ctrl *AuthController) ListSessions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	sessions, err := ctrl.sessionService.ListSessions(c.Request.Context(), userID, middleware.GetSessionID(c))
+	if err != nil {
+		logger.Logger.Error("Failed to list sessions", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "查询会话失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, sessions, "会话查询成功")
+}
+
+// RevokeSession 处理注销指定会话（设备）的请求 (DELETE /auth/sessions/:id)
+func (ctrl *AuthController) RevokeSession(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	if err := ctrl.sessionService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "会话不存在或已注销")
+			return
 		}
+
+		logger.Logger.Error("Failed to revoke session", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "注销会话失败，请稍后重试")
+		return
 	}
 
-	// 刷新 Token
-	newToken, err := ctrl.jwtService.RefreshToken(oldToken)
+	response.Success(c, http.StatusOK, nil, "会话已注销")
+}
+
+// JWKS 处理公钥集合请求 (GET /.well-known/jwks.json)，供其他服务校验访问令牌。
+// 按 RFC 7517 直接返回 JWK Set，不使用统一响应结构。
+func (ctrl *AuthController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ctrl.jwtService.JWKS())
+}
+
+// RevokeTokens 处理吊销当前用户全部令牌的请求 (POST /auth/tokens/revoke)，
+// 用于怀疑令牌泄露时让所有设备（含当前设备）重新登录
+func (ctrl *AuthController) RevokeTokens(c *gin.Context) {
+	before, ok := bindRevokeBefore(c)
+	if !ok {
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	revocation, err := ctrl.revocationService.RevokeUserTokens(c.Request.Context(), userID, before)
 	if err != nil {
-		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "Token 刷新失败")
+		handleRevokeTokensError(c, userID, err)
 		return
 	}
 
-	response.Success(c, http.StatusOK, gin.H{
-		"token": newToken,
-	}, "Token 刷新成功")
+	response.Success(c, http.StatusOK, revocation, "令牌已吊销，请重新登录")
+}
+
+// bindRevokeBefore 解析吊销令牌请求中的时间点，请求体为空时返回零值；解析失败时已写入响应
+func bindRevokeBefore(c *gin.Context) (time.Time, bool) {
+	var req RevokeTokensRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效，before 应为 RFC 3339 时间")
+		return time.Time{}, false
+	}
+
+	if req.Before == nil {
+		return time.Time{}, true
+	}
+	return *req.Before, true
+}
+
+// handleRevokeTokensError 将吊销令牌的业务错误映射为 HTTP 响应
+func handleRevokeTokensError(c *gin.Context, userID uint, err error) {
+	switch {
+	case errors.Is(err, service.ErrRevocationTimeInFuture):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "吊销时间点不能晚于当前时间")
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "用户不存在")
+	default:
+		logger.Logger.Error("Failed to revoke user tokens", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "吊销令牌失败，请稍后重试")
+	}
+}
+
+// completeLogin 为已通过第一因子（密码 / SIWE 签名）的用户生成登录响应：
+// 已绑定 TOTP 时只返回 mfa_pending 令牌，否则直接创建会话
+func completeLogin(
+	c *gin.Context,
+	totpService service.TOTPService,
+	sessionService service.SessionService,
+	user *model.User,
+) (*LoginResponse, error) {
+	challenge, err := totpService.BeginLogin(c.Request.Context(), user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &LoginResponse{
+			UserID:       user.ID,
+			Username:     user.Username,
+			MFARequired:  true,
+			MFAToken:     challenge.MFAToken,
+			MFAExpiresIn: challenge.ExpiresIn,
+		}, nil
+	}
+
+	tokens, err := sessionService.CreateSession(c.Request.Context(), user, sessionClient(c))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		TokenPair: tokens,
+		UserID:    user.ID,
+		Username:  user.Username,
+	}, nil
+}
+
+// loginMessage 返回登录响应的提示信息
+func loginMessage(resp *LoginResponse) string {
+	if resp.MFARequired {
+		return "请提交第二因子验证码以完成登录"
+	}
+	return "登录成功"
+}
+
+// sessionClient 提取创建 / 刷新会话的请求来源
+func sessionClient(c *gin.Context) service.SessionClient {
+	return service.SessionClient{
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
 }
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/conversion"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/web3client"
+)
+
+// ChainController 封装了链级公共信息相关的控制器方法
+type ChainController struct {
+	chainService service.ChainService
+}
+
+// NewChainController 创建并返回新的 ChainController 实例（依赖注入）
+func NewChainController(chainService service.ChainService) *ChainController {
+	return &ChainController{
+		chainService: chainService,
+	}
+}
+
+// FeeTierData 定义单个手续费档位的响应体（单位：Gwei）
+type FeeTierData struct {
+	MaxFeePerGasGwei         string `json:"max_fee_per_gas_gwei,omitempty"`
+	MaxPriorityFeePerGasGwei string `json:"max_priority_fee_per_gas_gwei,omitempty"`
+	GasPriceGwei             string `json:"gas_price_gwei,omitempty"` // 仅 legacy 链
+}
+
+// FeesResponse 定义手续费建议的响应体
+type FeesResponse struct {
+	ChainID     uint                               `json:"chain_id"`
+	Legacy      bool                               `json:"legacy"`
+	BaseFeeGwei string                             `json:"base_fee_gwei,omitempty"`
+	Tiers       map[web3client.FeeTier]FeeTierData `json:"tiers"`
+}
+
+// GetFees 处理查询链上手续费建议请求 (GET /v1/chains/:chain_id/fees)
+func (h *ChainController) GetFees(c *gin.Context) {
+	chainID, err := strconv.ParseUint(c.Param("chain_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "链 ID 格式错误")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	estimate, err := h.chainService.SuggestFees(ctx, uint(chainID))
+	if err != nil {
+		if errors.Is(err, service.ErrChainNotSupported) {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
+			return
+		}
+
+		logger.Logger.Error("Failed to estimate fees",
+			zap.Uint64("chain_id", chainID),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "手续费估算失败，请稍后重试")
+		return
+	}
+
+	resp := FeesResponse{
+		ChainID: estimate.ChainID,
+		Legacy:  estimate.Legacy,
+		Tiers:   make(map[web3client.FeeTier]FeeTierData, len(estimate.Tiers)),
+	}
+	if estimate.BaseFee != nil {
+		resp.BaseFeeGwei = gweiString(estimate.BaseFee)
+	}
+	for tier, suggestion := range estimate.Tiers {
+		resp.Tiers[tier] = FeeTierData{
+			MaxFeePerGasGwei:         gweiString(suggestion.MaxFeePerGas),
+			MaxPriorityFeePerGasGwei: gweiString(suggestion.MaxPriorityFeePerGas),
+			GasPriceGwei:             gweiString(suggestion.GasPrice),
+		}
+	}
+
+	response.Success(c, http.StatusOK, resp, "手续费查询成功")
+}
+
+// gweiString 将 Wei 转换为 Gwei 字符串，nil 返回空字符串
+func gweiString(wei *big.Int) string {
+	if wei == nil {
+		return ""
+	}
+	return conversion.WeiToGwei(wei).String()
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// KeyExportController 封装了 Keystore 导出与助记词查看的控制器方法
+type KeyExportController struct {
+	keyExportService service.KeyExportService
+}
+
+// NewKeyExportController 创建并返回新的 KeyExportController 实例（依赖注入）
+func NewKeyExportController(keyExportService service.KeyExportService) *KeyExportController {
+	return &KeyExportController{
+		keyExportService: keyExportService,
+	}
+}
+
+// ExportKeystoreRequest 定义导出 Keystore 的请求体
+type ExportKeystoreRequest struct {
+	AccountPassword  string `json:"account_password"   binding:"required"`
+	SecondFactorCode string `json:"second_factor_code"`
+
+	// 可选：指定 export_password 时使用 wallet_password 解密后以新密码重新加密
+	WalletPassword string `json:"wallet_password"`
+	ExportPassword string `json:"export_password"    binding:"omitempty,min=8"`
+}
+
+// RevealMnemonicRequest 定义查看助记词的请求体
+type RevealMnemonicRequest struct {
+	AccountPassword  string `json:"account_password"   binding:"required"`
+	SecondFactorCode string `json:"second_factor_code"`
+	WalletPassword   string `json:"wallet_password"    binding:"required"`
+}
+
+// ExportKeystore 处理导出 Keystore 请求 (POST /v1/wallet/:address/export/keystore)
+func (h *KeyExportController) ExportKeystore(c *gin.Context) {
+	var req ExportKeystoreRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+	if req.ExportPassword != "" && req.WalletPassword == "" {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "重新加密 Keystore 需要提供钱包密码")
+		return
+	}
+
+	params, ok := keyExportParams(c, req.AccountPassword, req.SecondFactorCode, req.WalletPassword)
+	if !ok {
+		return
+	}
+	params.ExportPassword = req.ExportPassword
+
+	// 重新加密时涉及两次 Scrypt 运算
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := h.keyExportService.ExportKeystore(ctx, params)
+	if err != nil {
+		handleKeyExportError(c, err, params)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, result, "Keystore 导出成功")
+}
+
+// RevealMnemonic 处理查看助记词请求 (POST /v1/wallet/:address/export/mnemonic)
+func (h *KeyExportController) RevealMnemonic(c *gin.Context) {
+	var req RevealMnemonicRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	params, ok := keyExportParams(c, req.AccountPassword, req.SecondFactorCode, req.WalletPassword)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := h.keyExportService.RevealMnemonic(ctx, params)
+	if err != nil {
+		handleKeyExportError(c, err, params)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, result, "助记词获取成功，请妥善保管")
+}
+
+// keyExportParams 组装导出请求的公共参数，获取用户身份失败时已写入响应并返回 false
+func keyExportParams(c *gin.Context, accountPassword, secondFactorCode, walletPassword string) (*service.KeyExportParams, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return nil, false
+	}
+
+	return &service.KeyExportParams{
+		UserID:           userID,
+		Address:          c.Param("address"),
+		AccountPassword:  accountPassword,
+		SecondFactorCode: secondFactorCode,
+		WalletPassword:   walletPassword,
+		ClientIP:         c.ClientIP(),
+		UserAgent:        c.Request.UserAgent(),
+	}, true
+}
+
+// handleKeyExportError 将导出业务错误映射为 HTTP 响应
+func handleKeyExportError(c *gin.Context, err error, params *service.KeyExportParams) {
+	// 第二因子验证码连续错误导致的退避或锁定
+	if respondAttemptLocked(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrKeyExportRateLimited):
+		response.Error(c, http.StatusTooManyRequests, response.CodeTooManyRequests, "导出尝试过于频繁，请稍后再试")
+	case errors.Is(err, service.ErrInvalidCredentials):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "账户密码错误")
+	case errors.Is(err, service.ErrPasswordNotSet):
+		response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户尚未设置密码，请先通过以太坊签名设置账户密码")
+	case errors.Is(err, service.ErrSecondFactorRequired):
+		response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该操作需要第二因子验证码")
+	case errors.Is(err, service.ErrSecondFactorInvalid):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+	case errors.Is(err, service.ErrInvalidAddress):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的钱包地址")
+	case errors.Is(err, service.ErrWalletNotFound):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "钱包不存在或无权访问")
+	case errors.Is(err, service.ErrMnemonicNotAvailable):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "该钱包没有可查看的助记词")
+	case errors.Is(err, service.ErrKeyNotCustodied):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "该钱包私钥由远程签名服务托管，无法导出")
+	case errors.Is(err, service.ErrPasswordIncorrect):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "钱包密码错误")
+	default:
+		logger.Logger.Error("Key export failed due to internal error",
+			zap.Uint("user_id", params.UserID),
+			zap.String("address", params.Address),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "导出失败，请稍后重试")
+	}
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+)
+
+// respondAttemptLocked 在 err 为退避或锁定错误时写入响应（含 Retry-After）并返回 true：
+// 指数退避返回 429 / CodeTooManyRequests，达到失败上限的锁定返回 423 / CodeAccountLocked
+func respondAttemptLocked(c *gin.Context, err error) bool {
+	var locked *service.AttemptLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	if locked.Locked {
+		response.Error(c, http.StatusLocked, response.CodeAccountLocked, "连续失败次数过多，已被临时锁定，请稍后重试或联系管理员解锁")
+	} else {
+		response.Error(c, http.StatusTooManyRequests, response.CodeTooManyRequests, "密码或验证码错误次数过多，请稍后重试")
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// messageEncodingHex 表示 personal 消息以 0x 前缀十六进制传输，默认 utf8 原文
+const messageEncodingHex = "hex"
+
+// MessagePayload 定义待签名 / 待校验的消息，按 type 填写对应字段
+type MessagePayload struct {
+	Type string `json:"type" binding:"required,oneof=personal typed_data"`
+
+	// type = personal：message 为原文，encoding 为 hex 时按 0x 前缀十六进制解码为原始字节
+	Message  string `json:"message"  binding:"max=65536"`
+	Encoding string `json:"encoding" binding:"omitempty,oneof=utf8 hex"`
+
+	// type = typed_data：与 eth_signTypedData_v4 相同的 {types, primaryType, domain, message} 对象
+	TypedData *apitypes.TypedData `json:"typed_data"`
+}
+
+// SignMessageRequest 定义消息签名的请求体
+type SignMessageRequest struct {
+	MessagePayload
+	Password         string `json:"password"`           // 钱包密码，远程签名的钱包无需填写
+	SecondFactorCode string `json:"second_factor_code"` // 已绑定第二因子时必填
+}
+
+// VerifyMessageRequest 定义签名校验的请求体
+type VerifyMessageRequest struct {
+	MessagePayload
+	Signature string `json:"signature" binding:"required"`
+	Address   string `json:"address"` // 可选，期望的签名者地址
+}
+
+// SignMessage 处理消息签名请求 (POST /v1/wallet/:address/sign)
+func (h *WalletController) SignMessage(c *gin.Context) {
+	var req SignMessageRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	message, err := req.message()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "消息不是合法的十六进制数据")
+		return
+	}
+
+	// Keystore 解密使用 Scrypt，耗时较长
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	signature, err := h.walletService.SignMessage(ctx, &service.SignMessageParams{
+		UserID:    userID,
+		Address:   c.Param("address"),
+		Password:  req.Password,
+		Type:      req.Type,
+		Message:   message,
+		TypedData: req.TypedData,
+
+		SecondFactorCode: req.SecondFactorCode,
+	})
+	if err != nil {
+		// 钱包密码连续错误导致的退避或锁定
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
+		// 1. 业务错误映射
+		switch {
+		case errors.Is(err, service.ErrWalletNotFound):
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "钱包不存在或无权访问")
+			return
+		case errors.Is(err, service.ErrSecondFactorRequired):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该操作需要第二因子验证码")
+			return
+		case errors.Is(err, service.ErrSecondFactorInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+			return
+		case errors.Is(err, service.ErrPasswordIncorrect):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "钱包密码错误")
+			return
+		case errors.Is(err, service.ErrRemoteAccountNotAssigned):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该远程签名账户未分配给当前用户")
+			return
+		case errors.Is(err, service.ErrSignerUnavailable):
+			response.Error(c, http.StatusServiceUnavailable, response.CodeInternalError, "远程签名服务不可用")
+			return
+		case handleMessageError(c, err):
+			return
+		}
+
+		// 2. 内部系统错误（注意：不记录消息内容）
+		logger.Logger.Error("Failed to sign message due to internal error",
+			zap.Uint("user_id", userID),
+			zap.String("address", c.Param("address")),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "消息签名失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, signature, "消息签名成功")
+}
+
+// VerifyMessage 处理签名校验请求 (POST /v1/wallet/verify)，返回从签名恢复的地址
+func (h *WalletController) VerifyMessage(c *gin.Context) {
+	var req VerifyMessageRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	message, err := req.message()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "消息不是合法的十六进制数据")
+		return
+	}
+
+	verification, err := h.walletService.VerifyMessage(c.Request.Context(), &service.VerifyMessageParams{
+		Type:      req.Type,
+		Message:   message,
+		TypedData: req.TypedData,
+		Signature: req.Signature,
+		Address:   req.Address,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSignature):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "签名格式无效")
+			return
+		case errors.Is(err, service.ErrInvalidAddress):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的钱包地址")
+			return
+		case handleMessageError(c, err):
+			return
+		}
+
+		logger.Logger.Error("Failed to verify message signature", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "签名校验失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, verification, "签名校验完成")
+}
+
+// message 返回 personal 消息的原始字节
+func (p *MessagePayload) message() ([]byte, error) {
+	if p.Encoding == messageEncodingHex {
+		return hexutil.Decode(p.Message)
+	}
+	return []byte(p.Message), nil
+}
+
+// handleMessageError 处理消息格式相关的错误，已写入响应时返回 true
+func handleMessageError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidTypedData):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "EIP-712 结构化数据无效")
+		return true
+	case errors.Is(err, service.ErrInvalidMessageType):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的消息类型")
+		return true
+	}
+	return false
+}
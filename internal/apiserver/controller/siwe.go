@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// SIWEController 封装了 Sign-In with Ethereum (EIP-4361) 相关的控制器
+type SIWEController struct {
+	siweService    service.SIWEService
+	sessionService service.SessionService
+	totpService    service.TOTPService
+}
+
+// NewSIWEController 创建并返回新的 SIWEController 实例
+func NewSIWEController(
+	siweService service.SIWEService,
+	sessionService service.SessionService,
+	totpService service.TOTPService,
+) *SIWEController {
+	return &SIWEController{
+		siweService:    siweService,
+		sessionService: sessionService,
+		totpService:    totpService,
+	}
+}
+
+// SIWEVerifyRequest 定义 SIWE 校验请求体
+type SIWEVerifyRequest struct {
+	Message   string `json:"message"   binding:"required,max=4096"` // 钱包签名的 EIP-4361 消息原文
+	Signature string `json:"signature" binding:"required"`          // personal_sign 签名，0x 前缀的 65 字节十六进制
+}
+
+// SIWELoginResponse 定义 SIWE 登录成功的响应体
+type SIWELoginResponse struct {
+	LoginResponse
+	EthereumAddress *string `json:"ethereum_address"`
+	Created         bool    `json:"created"` // 是否为本次登录新创建的用户
+}
+
+// Nonce 处理签发 SIWE nonce 请求 (GET /api/v1/auth/siwe/nonce)
+func (ctrl *SIWEController) Nonce(c *gin.Context) {
+	nonce, err := ctrl.siweService.IssueNonce(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrSIWEDisabled) {
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "未启用以太坊账户登录")
+			return
+		}
+
+		logger.Logger.Error("Failed to issue SIWE nonce", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "获取 nonce 失败，请稍后重试")
+		return
+	}
+
+	// nonce 只能使用一次，禁止缓存
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, nonce, "获取 nonce 成功")
+}
+
+// Verify 处理 SIWE 登录请求 (POST /api/v1/auth/siwe/verify)
+func (ctrl *SIWEController) Verify(c *gin.Context) {
+	var req SIWEVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	login, err := ctrl.siweService.Login(c.Request.Context(), req.Message, req.Signature)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	// 已绑定 TOTP 时同样需要第二步验证
+	resp, err := completeLogin(c, ctrl.totpService, ctrl.sessionService, login.User)
+	if err != nil {
+		logger.Logger.Error("SIWE login successful but failed to create session",
+			zap.Uint("user_id", login.User.ID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登录成功，但无法生成响应")
+		return
+	}
+
+	response.Success(c, http.StatusOK, SIWELoginResponse{
+		LoginResponse:   *resp,
+		EthereumAddress: login.User.EthereumAddress,
+		Created:         login.Created,
+	}, loginMessage(resp))
+}
+
+// Link 处理将以太坊地址关联到当前用户的请求 (POST /api/v1/auth/siwe/link)
+func (ctrl *SIWEController) Link(c *gin.Context) {
+	var req SIWEVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	user, err := ctrl.siweService.Link(c.Request.Context(), userID, req.Message, req.Signature)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"user_id":          user.ID,
+		"username":         user.Username,
+		"ethereum_address": user.EthereumAddress,
+	}, "以太坊地址关联成功")
+}
+
+// SIWESetPasswordRequest 定义 SIWE 用户设置初始账户密码的请求体
+type SIWESetPasswordRequest struct {
+	Message     string `json:"message"      binding:"required,max=4096"` // 关联地址新签名的 EIP-4361 消息，作为重新认证
+	Signature   string `json:"signature"    binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// SetPassword 处理 SIWE 用户设置初始账户密码的请求 (POST /auth/siwe/password)，
+// 仅适用于尚无账户密码的用户，已有密码的用户请使用修改密码接口
+func (ctrl *SIWEController) SetPassword(c *gin.Context) {
+	var req SIWESetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	err = ctrl.siweService.SetInitialPassword(c.Request.Context(), userID, req.Message, req.Signature, req.NewPassword)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "账户密码设置成功")
+}
+
+// handleError 将 SIWE 业务错误映射为 HTTP 响应
+func (ctrl *SIWEController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSIWEDisabled):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "未启用以太坊账户登录")
+	case errors.Is(err, service.ErrSIWEInvalidMessage):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "签名消息格式无效")
+	case errors.Is(err, service.ErrChainNotSupported):
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
+	case errors.Is(err, service.ErrSIWEDomainMismatch):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "签名消息的域名不受信任")
+	case errors.Is(err, service.ErrSIWEExpired):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "签名消息已过期或尚未生效")
+	case errors.Is(err, service.ErrSIWEInvalidSignature):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "签名无效")
+	case errors.Is(err, service.ErrSIWEInvalidNonce):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "nonce 无效、已过期或已被使用")
+	case errors.Is(err, service.ErrSIWEAddressConflict):
+		response.Error(c, http.StatusConflict, response.CodeResourceExists, "该地址已关联其他账户，或当前账户已关联其他地址")
+	case errors.Is(err, service.ErrSIWEAddressMismatch):
+		response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "签名地址不是当前账户关联的以太坊地址")
+	case errors.Is(err, service.ErrPasswordAlreadySet):
+		response.Error(c, http.StatusConflict, response.CodeResourceExists, "账户已设置密码，请使用修改密码接口")
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "用户不存在")
+	case errors.Is(err, service.ErrUserDisabled):
+		response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户已被停用")
+	default:
+		logger.Logger.Error("SIWE verification failed due to internal error", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "登录失败，请稍后重试")
+	}
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// TOTPController 封装了 TOTP 第二因子绑定与恢复码管理的控制器
+type TOTPController struct {
+	totpService service.TOTPService
+}
+
+// NewTOTPController 创建并返回新的 TOTPController 实例
+func NewTOTPController(totpService service.TOTPService) *TOTPController {
+	return &TOTPController{
+		totpService: totpService,
+	}
+}
+
+// TOTPCodeRequest 定义需要提交验证码的请求体
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"` // 6 位 TOTP 验证码
+}
+
+// TOTPProtectedRequest 定义解绑与重新生成恢复码的请求体，需同时提交账户密码与验证码
+type TOTPProtectedRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"     binding:"required,max=32"` // 6 位 TOTP 验证码或恢复码
+}
+
+// RecoveryCodesResponse 定义恢复码响应体，恢复码只展示一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Enroll 处理生成 TOTP 密钥的请求 (POST /auth/totp/enroll)
+func (ctrl *TOTPController) Enroll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	enrollment, err := ctrl.totpService.Enroll(c.Request.Context(), userID)
+	if err != nil {
+		ctrl.handleError(c, userID, err)
+		return
+	}
+
+	// 响应包含密钥明文，禁止缓存
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, enrollment, "请使用身份验证器扫描二维码，并提交验证码完成绑定")
+}
+
+// Confirm 处理确认 TOTP 绑定的请求 (POST /auth/totp/confirm)
+func (ctrl *TOTPController) Confirm(c *gin.Context) {
+	ctrl.withCode(c, func(userID uint, code string) {
+		codes, err := ctrl.totpService.Confirm(c.Request.Context(), userID, code)
+		if err != nil {
+			ctrl.handleError(c, userID, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		response.Success(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes}, "TOTP 绑定成功，请妥善保存恢复码")
+	})
+}
+
+// Disable 处理解除 TOTP 绑定的请求 (POST /auth/totp/disable)
+func (ctrl *TOTPController) Disable(c *gin.Context) {
+	ctrl.withPasswordAndCode(c, func(userID uint, req *TOTPProtectedRequest) {
+		if err := ctrl.totpService.Disable(c.Request.Context(), userID, req.Password, req.Code); err != nil {
+			ctrl.handleError(c, userID, err)
+			return
+		}
+
+		response.Success(c, http.StatusOK, nil, "TOTP 已解除绑定")
+	})
+}
+
+// RegenerateRecoveryCodes 处理重新生成恢复码的请求 (POST /auth/totp/recovery-codes)
+func (ctrl *TOTPController) RegenerateRecoveryCodes(c *gin.Context) {
+	ctrl.withPasswordAndCode(c, func(userID uint, req *TOTPProtectedRequest) {
+		codes, err := ctrl.totpService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Password, req.Code)
+		if err != nil {
+			ctrl.handleError(c, userID, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		response.Success(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes}, "恢复码已重新生成，旧恢复码已失效")
+	})
+}
+
+// withCode 解析验证码请求体与当前用户，成功后调用 handle
+func (ctrl *TOTPController) withCode(c *gin.Context, handle func(userID uint, code string)) {
+	var req TOTPCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	handle(userID, req.Code)
+}
+
+// withPasswordAndCode 解析账户密码与验证码请求体及当前用户，成功后调用 handle
+func (ctrl *TOTPController) withPasswordAndCode(c *gin.Context, handle func(userID uint, req *TOTPProtectedRequest)) {
+	var req TOTPProtectedRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	handle(userID, &req)
+}
+
+// handleError 将 TOTP 业务错误映射为 HTTP 响应
+func (ctrl *TOTPController) handleError(c *gin.Context, userID uint, err error) {
+	// 验证码或账户密码连续错误导致的退避或锁定
+	if respondAttemptLocked(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrTOTPAlreadyEnrolled):
+		response.Error(c, http.StatusConflict, response.CodeResourceExists, "已绑定 TOTP，如需更换请先解除绑定")
+	case errors.Is(err, service.ErrTOTPNotEnrolled):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "尚未绑定 TOTP")
+	case errors.Is(err, service.ErrSecondFactorInvalid):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+	case errors.Is(err, service.ErrInvalidCredentials):
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "账户密码错误")
+	case errors.Is(err, service.ErrPasswordNotSet):
+		response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户尚未设置密码，请先通过以太坊签名设置账户密码")
+	case errors.Is(err, service.ErrUserNotFound):
+		response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "用户不存在")
+	default:
+		logger.Logger.Error("TOTP operation failed due to internal error", zap.Uint("user_id", userID), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "操作失败，请稍后重试")
+	}
+}
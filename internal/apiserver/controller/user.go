@@ -15,13 +15,15 @@ import (
 
 // UserController 封装了用户身份认证和管理相关的控制器
 type UserController struct {
-	userService service.UserService
+	userService     service.UserService
+	passwordService service.PasswordService
 }
 
 // NewUserController 创建并返回新的 UserController 实例（依赖注入）
-func NewUserController(userService service.UserService) *UserController {
+func NewUserController(userService service.UserService, passwordService service.PasswordService) *UserController {
 	return &UserController{
-		userService: userService,
+		userService:     userService,
+		passwordService: passwordService,
 	}
 }
 
@@ -54,6 +56,10 @@ func (ctrl *UserController) Register(c *gin.Context) {
 			response.Error(c, http.StatusConflict, response.CodeResourceExists, "用户名已存在，请更换")
 			return
 		}
+		if errors.Is(err, service.ErrUsernameReserved) {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不能使用以太坊地址作为用户名")
+			return
+		}
 
 		// 2. 捕获系统内部错误 (http.StatusInternalServerError / 500)
 		// 捕获 PasswordHashFailed 或 StoreOperationFailed 这种无法修复的错误
@@ -103,3 +109,115 @@ func (ctrl *UserController) GetProfile(c *gin.Context) {
 		"user_id": userID,
 	}, "成功访问用户资料")
 }
+
+// ChangePasswordRequest 定义修改账户密码的请求体
+type ChangePasswordRequest struct {
+	OldPassword      string `json:"old_password"       binding:"required"`
+	NewPassword      string `json:"new_password"       binding:"required,min=8"`
+	SecondFactorCode string `json:"second_factor_code"` // 已绑定第二因子时必填
+}
+
+// ForgotPasswordRequest 定义申请找回密码的请求体
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ResetPasswordRequest 定义使用重置令牌设置新密码的请求体
+type ResetPasswordRequest struct {
+	Token       string `json:"token"        binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// ChangePassword 处理修改账户密码请求 (PUT /v1/users/password)，成功后全部令牌失效，需重新登录
+func (ctrl *UserController) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	err = ctrl.passwordService.ChangePassword(c.Request.Context(), &service.ChangePasswordParams{
+		UserID:           userID,
+		OldPassword:      req.OldPassword,
+		NewPassword:      req.NewPassword,
+		SecondFactorCode: req.SecondFactorCode,
+	})
+	if err != nil {
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "原密码错误")
+		case errors.Is(err, service.ErrPasswordNotSet):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户尚未设置密码，请先通过以太坊签名设置账户密码")
+		case errors.Is(err, service.ErrSecondFactorRequired):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该操作需要第二因子验证码")
+		case errors.Is(err, service.ErrSecondFactorInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+		case errors.Is(err, service.ErrPasswordUnchanged):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "新密码不能与原密码相同")
+		case errors.Is(err, service.ErrUserNotFound):
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "用户不存在")
+		default:
+			logger.Logger.Error("Failed to change password", zap.Uint("user_id", userID), zap.Error(err))
+			response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "修改密码失败，请稍后重试")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "密码已修改，请重新登录")
+}
+
+// ForgotPassword 处理申请找回密码请求 (POST /v1/users/password/forgot)。
+// 无论用户是否存在都返回相同响应，重置令牌通过配置的投递渠道发送
+func (ctrl *UserController) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	if err := ctrl.passwordService.RequestPasswordReset(c.Request.Context(), req.Username); err != nil {
+		if errors.Is(err, service.ErrPasswordResetDisabled) {
+			response.Error(c, http.StatusNotImplemented, response.CodeInternalError, "找回密码功能未启用，请联系管理员")
+			return
+		}
+		logger.Logger.Error("Failed to request password reset", zap.String("username", req.Username), zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "申请找回密码失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, nil, "如果该用户存在，重置令牌已发送")
+}
+
+// ResetPassword 处理使用重置令牌设置新密码请求 (POST /v1/users/password/reset)
+func (ctrl *UserController) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	if err := ctrl.passwordService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, service.ErrPasswordResetTokenInvalid) {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "重置令牌无效、已过期或已被使用")
+			return
+		}
+		logger.Logger.Error("Failed to reset password", zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "重置密码失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil, "密码已重置，请重新登录")
+}
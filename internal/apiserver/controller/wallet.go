@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
@@ -30,15 +31,65 @@ func NewWalletController(walletService service.WalletService) *WalletController
 
 // CreateWalletRequest 定义创建 HD 钱包的请求体
 type CreateWalletRequest struct {
-	Password string `json:"password" binding:"required,min=8"`
-	ChainID  uint   `json:"chain_id" binding:"required"`
+	Password string         `json:"password" binding:"required,min=8"`
+	ChainID  uint           `json:"chain_id" binding:"required"`
+	Shamir   *ShamirRequest `json:"shamir"` // 可选，以 Shamir 份额代替助记词明文备份
+}
+
+// ShamirRequest 定义 Shamir 份额备份参数：拆分为 shares 份，任意 threshold 份可恢复
+type ShamirRequest struct {
+	Shares    int `json:"shares"    binding:"required,min=2,max=16"`
+	Threshold int `json:"threshold" binding:"required,min=2,ltefield=Shares"`
 }
 
 // CreateWalletResponse 定义创建钱包的成功响应体
 type CreateWalletResponse struct {
-	Address  string `json:"address"`
-	ChainID  uint   `json:"chain_id"`
-	Mnemonic string `json:"mnemonic,omitempty"` // 助记词只在创建时返回
+	Address   string   `json:"address"`
+	ChainID   uint     `json:"chain_id"`
+	Mnemonic  string   `json:"mnemonic,omitempty"`  // 助记词只在创建时返回
+	Shares    []string `json:"shares,omitempty"`    // 请求 Shamir 备份时代替助记词返回
+	Threshold int      `json:"threshold,omitempty"` // 恢复所需的最少份额数
+}
+
+// RecoverWalletRequest 定义使用 Shamir 份额恢复钱包的请求体
+type RecoverWalletRequest struct {
+	Shares         []string `json:"shares"          binding:"required,min=2,max=16,dive,required"`
+	ChainID        uint     `json:"chain_id"        binding:"required"`
+	Password       string   `json:"password"        binding:"required,min=8"`
+	Name           string   `json:"name"            binding:"omitempty,max=100"`
+	DerivationPath string   `json:"derivation_path"` // 可选，默认 m/44'/60'/0'/0/0
+}
+
+// ImportWalletRequest 定义导入钱包的请求体，按 type 填写对应字段
+type ImportWalletRequest struct {
+	Type     string `json:"type"     binding:"required,oneof=mnemonic private_key keystore remote"`
+	ChainID  uint   `json:"chain_id" binding:"required"`
+	Password string `json:"password" binding:"omitempty,min=8"` // 钱包密码，用于加密导入的私钥；remote 类型无需填写
+	Name     string `json:"name"     binding:"omitempty,max=100"`
+
+	// type = mnemonic
+	Mnemonic       string `json:"mnemonic"`
+	Passphrase     string `json:"passphrase"`      // 可选，BIP-39 passphrase
+	DerivationPath string `json:"derivation_path"` // 可选，默认 m/44'/60'/0'/0/0
+
+	// type = private_key
+	PrivateKey string `json:"private_key"`
+
+	// type = keystore：可直接传入 JSON 对象，或 JSON 字符串
+	Keystore         json.RawMessage `json:"keystore"`
+	KeystorePassword string          `json:"keystore_password"` // 可选，Keystore 原密码，默认同 password
+
+	// type = remote：远程签名进程持有的地址
+	Address string `json:"address"`
+}
+
+// ImportWalletResponse 定义导入钱包的成功响应体
+type ImportWalletResponse struct {
+	Address        string `json:"address"`
+	ChainID        uint   `json:"chain_id"`
+	Name           string `json:"name"`
+	Source         string `json:"source"`
+	DerivationPath string `json:"derivation_path,omitempty"`
 }
 
 // TransferRequest 定义转账交易的请求体
@@ -46,8 +97,16 @@ type TransferRequest struct {
 	FromAddress string `json:"from_address" binding:"required"`
 	ToAddress   string `json:"to_address"   binding:"required"`
 	Amount      string `json:"amount"       binding:"required"` // 字符串格式以避免精度问题
-	Password    string `json:"password"     binding:"required"`
+	Password    string `json:"password"`                        // 钱包密码，远程签名的钱包无需填写
 	ChainID     uint   `json:"chain_id"     binding:"required"`
+	Token       string `json:"token"` // 可选：ERC-20 代币符号或合约地址，为空表示转账原生币
+
+	SecondFactorCode string `json:"second_factor_code"` // 已绑定 TOTP 且使用 JWT 认证时必填：6 位验证码或恢复码，API Key 请求无需提交
+
+	// 可选的手续费策略：显式 fee cap (Gwei) 优先于档位，均未指定时使用 standard 档位
+	FeeTier                  string `json:"fee_tier"                      binding:"omitempty,oneof=slow standard fast"`
+	MaxFeePerGasGwei         string `json:"max_fee_per_gas_gwei"`
+	MaxPriorityFeePerGasGwei string `json:"max_priority_fee_per_gas_gwei"`
 }
 
 // CreateHDWallet 处理创建新的 HD 钱包请求 (POST /v1/wallets/create)
@@ -70,13 +129,31 @@ func (h *WalletController) CreateHDWallet(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
-	wallet, mnemonic, err := h.walletService.CreateHDWallet(ctx, userID, req.Password, req.ChainID)
+	var shamir *service.ShamirOptions
+	if req.Shamir != nil {
+		shamir = &service.ShamirOptions{Shares: req.Shamir.Shares, Threshold: req.Shamir.Threshold}
+	}
+
+	wallet, backup, err := h.walletService.CreateHDWallet(ctx, userID, req.Password, req.ChainID, shamir)
 	if err != nil {
 		// 1. 业务错误映射
 		if errors.Is(err, service.ErrChainNotSupported) {
 			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
 			return
 		}
+		if errors.Is(err, service.ErrInvalidShamirOptions) {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "Shamir 份额参数无效")
+			return
+		}
+		if errors.Is(err, service.ErrShamirSeedExists) {
+			response.Error(c, http.StatusConflict, response.CodeResourceExists, "已有助记词，Shamir 份额仅在首次生成助记词时提供，请去掉 Shamir 参数后重试")
+			return
+		}
+		if errors.Is(err, service.ErrPasswordIncorrect) {
+			// 已有助记词时，必须使用首次创建钱包时设置的密码
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "钱包密码错误，请使用首次创建钱包时设置的密码")
+			return
+		}
 
 		// 2. 内部系统错误
 		// 对于其他系统性错误（如 KeyManager/Store 失败），记录日志并返回通用错误
@@ -88,12 +165,197 @@ func (h *WalletController) CreateHDWallet(c *gin.Context) {
 		return
 	}
 
-	// 成功响应 (第一次返回助记词，用户应安全备份)
-	response.Success(c, http.StatusCreated, CreateWalletResponse{
-		Address:  wallet.Address,
-		ChainID:  wallet.ChainID,
-		Mnemonic: mnemonic, // 仅在创建成功时返回
-	}, "HD 钱包创建成功") // 修正：添加 message 参数
+	// 成功响应 (第一次返回助记词或其份额，用户应安全备份)
+	resp := CreateWalletResponse{
+		Address: wallet.Address,
+		ChainID: wallet.ChainID,
+	}
+	if backup != nil {
+		resp.Mnemonic = backup.Mnemonic
+		resp.Shares = backup.Shares
+		resp.Threshold = backup.Threshold
+	}
+	response.Success(c, http.StatusCreated, resp, "HD 钱包创建成功") // 修正：添加 message 参数
+}
+
+// RecoverWallet 处理使用 Shamir 份额恢复钱包请求 (POST /v1/wallet/recover)
+func (h *WalletController) RecoverWallet(c *gin.Context) {
+	var req RecoverWalletRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	wallet, err := h.walletService.RecoverWallet(ctx, &service.RecoverParams{
+		UserID:         userID,
+		ChainID:        req.ChainID,
+		Password:       req.Password,
+		Name:           req.Name,
+		Shares:         req.Shares,
+		DerivationPath: req.DerivationPath,
+	})
+	if err != nil {
+		// 1. 业务错误映射
+		switch {
+		case errors.Is(err, service.ErrInvalidShares):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "Shamir 份额无效、不足或不属于同一组")
+			return
+		case errors.Is(err, service.ErrWalletAlreadyExists):
+			response.Error(c, http.StatusConflict, response.CodeResourceExists, "该钱包地址已存在")
+			return
+		case errors.Is(err, service.ErrChainNotSupported):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
+			return
+		case errors.Is(err, service.ErrInvalidDerivationPath):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的派生路径")
+			return
+		}
+
+		// 2. 内部系统错误（注意：不记录任何份额内容）
+		logger.Logger.Error("Failed to recover wallet due to internal error",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "钱包恢复失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, ImportWalletResponse{
+		Address:        wallet.Address,
+		ChainID:        wallet.ChainID,
+		Name:           wallet.Name,
+		Source:         wallet.Source,
+		DerivationPath: wallet.DerivationPath,
+	}, "钱包恢复成功")
+}
+
+// ImportWallet 处理导入钱包请求 (POST /v1/wallet/import)
+func (h *WalletController) ImportWallet(c *gin.Context) {
+	var req ImportWalletRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	if req.Type != service.ImportTypeRemote && req.Password == "" {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请设置钱包密码")
+		return
+	}
+
+	keystoreJSON, err := keystoreText(req.Keystore)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "Keystore 格式错误")
+		return
+	}
+
+	// Keystore 解密与重新加密均使用 Scrypt，耗时较长
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	wallet, err := h.walletService.ImportWallet(ctx, &service.ImportParams{
+		UserID:           userID,
+		ChainID:          req.ChainID,
+		Password:         req.Password,
+		Name:             req.Name,
+		Type:             req.Type,
+		Mnemonic:         req.Mnemonic,
+		Passphrase:       req.Passphrase,
+		DerivationPath:   req.DerivationPath,
+		PrivateKey:       req.PrivateKey,
+		Keystore:         keystoreJSON,
+		KeystorePassword: req.KeystorePassword,
+		Address:          req.Address,
+	})
+	if err != nil {
+		// 1. 业务错误映射
+		switch {
+		case errors.Is(err, service.ErrWalletAlreadyExists):
+			response.Error(c, http.StatusConflict, response.CodeResourceExists, "该钱包地址已存在")
+			return
+		case errors.Is(err, service.ErrChainNotSupported):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
+			return
+		case errors.Is(err, service.ErrInvalidMnemonic):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的助记词")
+			return
+		case errors.Is(err, service.ErrInvalidDerivationPath):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的派生路径")
+			return
+		case errors.Is(err, service.ErrInvalidPrivateKey):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的私钥")
+			return
+		case errors.Is(err, service.ErrInvalidKeystore):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的 Keystore 文件")
+			return
+		case errors.Is(err, service.ErrInvalidAddress):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的钱包地址")
+			return
+		case errors.Is(err, service.ErrRemoteAccountNotAssigned):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该远程签名账户未分配给当前用户")
+			return
+		case errors.Is(err, service.ErrSignerUnavailable):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "远程签名服务未启用或不持有该地址")
+			return
+		case errors.Is(err, service.ErrInvalidImportType):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的导入类型")
+			return
+		case errors.Is(err, service.ErrPasswordIncorrect):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "Keystore 密码错误，无法解密")
+			return
+		}
+
+		// 2. 内部系统错误（注意：不记录任何导入内容）
+		logger.Logger.Error("Failed to import wallet due to internal error",
+			zap.Uint("user_id", userID),
+			zap.String("type", req.Type),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "钱包导入失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, ImportWalletResponse{
+		Address:        wallet.Address,
+		ChainID:        wallet.ChainID,
+		Name:           wallet.Name,
+		Source:         wallet.Source,
+		DerivationPath: wallet.DerivationPath,
+	}, "钱包导入成功")
+}
+
+// keystoreText 将请求中的 keystore 字段统一为 JSON 文本：既支持 JSON 对象，也支持 JSON 字符串
+func keystoreText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	if !json.Valid(raw) {
+		return "", errors.New("keystore is not valid JSON")
+	}
+
+	return string(raw), nil
 }
 
 // Transfer 处理发起转账交易请求 (POST /v1/wallets/transfer)
@@ -105,36 +367,72 @@ func (h *WalletController) Transfer(c *gin.Context) {
 		return
 	}
 
-	// 忽略用户 ID 检查 (Transfer 依赖于 from_address, 但在实际业务中可能需要验证 from_address 属于当前用户)
-	// 这里简化处理，直接调用 service
+	// 从中间件中获取用户ID，Service 层据此校验 from_address 的归属
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
 
-	txHash, err := h.walletService.Transfer(
-		ctx,
-		req.FromAddress,
-		req.ToAddress,
-		req.Amount,
-		req.Password,
-		req.ChainID,
-	)
+	txHash, err := h.walletService.Transfer(ctx, &service.TransferParams{
+		UserID:                   userID,
+		FromAddress:              req.FromAddress,
+		ToAddress:                req.ToAddress,
+		Amount:                   req.Amount,
+		Password:                 req.Password,
+		ChainID:                  req.ChainID,
+		Token:                    req.Token,
+		SecondFactorCode:         req.SecondFactorCode,
+		ViaAPIKey:                middleware.IsAPIKeyRequest(c),
+		FeeTier:                  req.FeeTier,
+		MaxFeePerGasGwei:         req.MaxFeePerGasGwei,
+		MaxPriorityFeePerGasGwei: req.MaxPriorityFeePerGasGwei,
+	})
 
 	if err != nil {
+		// 钱包密码连续错误导致的退避或锁定
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
 		// 1. 业务错误映射
 		switch {
 		case errors.Is(err, service.ErrWalletNotFound):
 			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "发送地址不存在或您无权操作")
 			return
+		case errors.Is(err, service.ErrSecondFactorRequired):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该操作需要第二因子验证码")
+			return
+		case errors.Is(err, service.ErrSecondFactorInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+			return
 		case errors.Is(err, service.ErrPasswordIncorrect):
 			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "密码错误，无法解锁钱包")
 			return
+		case errors.Is(err, service.ErrRemoteAccountNotAssigned):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该远程签名账户未分配给当前用户")
+			return
+		case errors.Is(err, service.ErrSignerUnavailable):
+			response.Error(c, http.StatusServiceUnavailable, response.CodeInternalError, "远程签名服务不可用")
+			return
 		case errors.Is(err, service.ErrChainNotSupported):
 			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
 			return
 		case errors.Is(err, service.ErrInvalidAmount):
 			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的转账金额")
 			return
+		case errors.Is(err, service.ErrInvalidAddress):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的收款地址")
+			return
+		case errors.Is(err, service.ErrTokenNotFound):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "该链不支持此代币")
+			return
+		case errors.Is(err, service.ErrInvalidFee):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的手续费档位或手续费上限")
+			return
 		case errors.Is(err, service.ErrInsufficientBal), errors.Is(err, service.ErrInsufficientGas):
 			// 余额不足和 Gas 不足都映射为 400 Bad Request
 			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "余额不足以完成交易（包括矿工费）")
@@ -159,9 +457,11 @@ func (h *WalletController) Transfer(c *gin.Context) {
 }
 
 // GetBalance 处理查询地址余额请求 (GET /v1/wallets/:address/balance)
+// 可选查询参数 token（代币符号或合约地址），为空时查询原生币余额
 func (h *WalletController) GetBalance(c *gin.Context) {
 	address := c.Param("address")
 	chainIDStr := c.Query("chain_id")
+	token := c.Query("token")
 
 	if address == "" || chainIDStr == "" {
 		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求缺少地址或链 ID")
@@ -177,18 +477,26 @@ func (h *WalletController) GetBalance(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	balance, err := h.walletService.GetBalance(ctx, address, uint(chainID))
+	balance, err := h.walletService.GetBalance(ctx, address, uint(chainID), token)
 	if err != nil {
 		// 1. 业务错误映射
-		if errors.Is(err, service.ErrChainNotSupported) {
+		switch {
+		case errors.Is(err, service.ErrChainNotSupported):
 			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
 			return
+		case errors.Is(err, service.ErrInvalidAddress):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的钱包地址")
+			return
+		case errors.Is(err, service.ErrTokenNotFound):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "该链不支持此代币")
+			return
 		}
 
 		// 2. 内部系统错误（如 RPC 连接超时、节点失败）
 		logger.Logger.Error("Failed to get balance from blockchain",
 			zap.String("address", address),
 			zap.Uint64("chain_id", chainID),
+			zap.String("token", token),
 			zap.Error(err),
 		)
 		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "查询余额失败，请检查网络或稍后重试")
@@ -196,9 +504,290 @@ func (h *WalletController) GetBalance(c *gin.Context) {
 	}
 
 	// 成功响应
+	data := gin.H{
+		"address":  address,
+		"chain_id": chainID,
+		"balance":  balance,
+	}
+	if token == "" {
+		data["balance_eth"] = balance.Amount // 兼容旧版客户端
+	}
+	response.Success(c, http.StatusOK, data, "余额查询成功")
+}
+
+// GetPortfolio 处理查询地址在所有链上资产的请求 (GET /v1/wallet/:address/portfolio)
+// 单条链查询失败时在对应条目的 error 字段中返回，不影响整体响应
+func (h *WalletController) GetPortfolio(c *gin.Context) {
+	address := c.Param("address")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	portfolio, err := h.walletService.GetPortfolio(ctx, address)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAddress) {
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的钱包地址")
+			return
+		}
+
+		logger.Logger.Error("Failed to get portfolio",
+			zap.String("address", address),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "查询资产失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, portfolio, "资产查询成功")
+}
+
+// ListTransactions 处理查询钱包交易历史请求 (GET /v1/wallet/:address/transactions)
+// 查询参数：chain_id、status、since/until (RFC3339)、cursor、limit
+func (h *WalletController) ListTransactions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	query, err := parseTransactionQuery(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "查询参数格式错误")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	address := c.Param("address")
+	page, err := h.walletService.ListTransactions(ctx, userID, address, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWalletNotFound):
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "钱包地址不存在或您无权查看")
+			return
+		case errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidTxFilter):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的分页游标或筛选条件")
+			return
+		}
+
+		logger.Logger.Error("Failed to list transactions",
+			zap.Uint("user_id", userID),
+			zap.String("address", address),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "查询交易记录失败，请稍后重试")
+		return
+	}
+
+	response.Success(c, http.StatusOK, page, "交易记录查询成功")
+}
+
+// parseTransactionQuery 解析交易列表的查询参数
+func parseTransactionQuery(c *gin.Context) (*service.TransactionQuery, error) {
+	query := &service.TransactionQuery{
+		Status: c.Query("status"),
+		Cursor: c.Query("cursor"),
+	}
+
+	if v := c.Query("chain_id"); v != "" {
+		chainID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		query.ChainID = uint(chainID)
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		query.Limit = limit
+	}
+
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		query.Since = &since
+	}
+
+	if v := c.Query("until"); v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		query.Until = &until
+	}
+
+	return query, nil
+}
+
+// ReplaceTransactionRequest 定义加速 / 取消交易的请求体
+type ReplaceTransactionRequest struct {
+	Password string `json:"password"` // 钱包密码，远程签名的钱包无需填写
+
+	// 可选的手续费策略，规则同 TransferRequest；最终手续费不低于原交易的 110%
+	FeeTier                  string `json:"fee_tier"                      binding:"omitempty,oneof=slow standard fast"`
+	MaxFeePerGasGwei         string `json:"max_fee_per_gas_gwei"`
+	MaxPriorityFeePerGasGwei string `json:"max_priority_fee_per_gas_gwei"`
+}
+
+// SpeedUpTransaction 处理加速交易请求 (POST /v1/transactions/:hash/speedup)
+func (h *WalletController) SpeedUpTransaction(c *gin.Context) {
+	h.replaceTransaction(c, h.walletService.SpeedUpTransaction, "交易加速请求已发送")
+}
+
+// CancelTransaction 处理取消交易请求 (POST /v1/transactions/:hash/cancel)
+func (h *WalletController) CancelTransaction(c *gin.Context) {
+	h.replaceTransaction(c, h.walletService.CancelTransaction, "交易取消请求已发送")
+}
+
+// replaceTransaction 是加速与取消接口的公共处理流程
+func (h *WalletController) replaceTransaction(
+	c *gin.Context,
+	replace func(ctx context.Context, params *service.ReplaceParams) (string, error),
+	successMessage string,
+) {
+	var req ReplaceTransactionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	originalHash := c.Param("hash")
+	txHash, err := replace(ctx, &service.ReplaceParams{
+		UserID:                   userID,
+		TxHash:                   originalHash,
+		Password:                 req.Password,
+		FeeTier:                  req.FeeTier,
+		MaxFeePerGasGwei:         req.MaxFeePerGasGwei,
+		MaxPriorityFeePerGasGwei: req.MaxPriorityFeePerGasGwei,
+	})
+
+	if err != nil {
+		// 钱包密码连续错误导致的退避或锁定
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
+		// 1. 业务错误映射
+		switch {
+		case errors.Is(err, service.ErrTxNotFound), errors.Is(err, service.ErrWalletNotFound):
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "交易不存在或您无权操作")
+			return
+		case errors.Is(err, service.ErrTxNotReplaceable):
+			response.Error(c, http.StatusConflict, response.CodeInvalidParam, "交易已上链或已被替换，请对最新的替换交易操作")
+			return
+		case errors.Is(err, service.ErrPasswordIncorrect):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "密码错误，无法解锁钱包")
+			return
+		case errors.Is(err, service.ErrRemoteAccountNotAssigned):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该远程签名账户未分配给当前用户")
+			return
+		case errors.Is(err, service.ErrSignerUnavailable):
+			response.Error(c, http.StatusServiceUnavailable, response.CodeInternalError, "远程签名服务不可用")
+			return
+		case errors.Is(err, service.ErrChainNotSupported):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "不支持的区块链 ID")
+			return
+		case errors.Is(err, service.ErrInvalidFee):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "无效的手续费档位或手续费上限")
+			return
+		case errors.Is(err, service.ErrReplacementUnderpriced):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "手续费需至少高于原交易 10%")
+			return
+		case errors.Is(err, service.ErrInsufficientGas):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "余额不足以支付矿工费")
+			return
+		}
+
+		// 2. 内部系统错误
+		logger.Logger.Error("Failed to replace transaction due to internal error",
+			zap.Uint("user_id", userID),
+			zap.String("tx_hash", originalHash),
+			zap.Error(err),
+		)
+		response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "交易处理失败，请稍后重试")
+		return
+	}
+
 	response.Success(c, http.StatusOK, gin.H{
-		"address":     address,
-		"chain_id":    chainID,
-		"balance_eth": balance, // 余额已在 Service 层转换为 ETH 格式
-	}, "余额查询成功")
+		"tx_hash":       txHash,
+		"replaces_hash": originalHash,
+	}, successMessage)
+}
+
+// ChangeWalletPasswordRequest 定义修改钱包密码的请求体
+type ChangeWalletPasswordRequest struct {
+	OldPassword      string `json:"old_password"       binding:"required"`
+	NewPassword      string `json:"new_password"       binding:"required,min=8"`
+	SecondFactorCode string `json:"second_factor_code"` // 已绑定第二因子时必填
+}
+
+// ChangeWalletPassword 处理修改钱包密码的请求 (PUT /wallet/password)，
+// 用户的全部 Keystore 与助记词在同一事务中以新密码重新加密
+func (h *WalletController) ChangeWalletPassword(c *gin.Context) {
+	var req ChangeWalletPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "请求参数无效或格式错误")
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+		return
+	}
+
+	// 每个 Keystore 与助记词各需一次 scrypt 解密与加密，给予较长超时
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	result, err := h.walletService.ChangeWalletPassword(ctx, &service.ChangeWalletPasswordParams{
+		UserID:           userID,
+		OldPassword:      req.OldPassword,
+		NewPassword:      req.NewPassword,
+		SecondFactorCode: req.SecondFactorCode,
+	})
+	if err != nil {
+		// 钱包密码连续错误导致的退避或锁定
+		if respondAttemptLocked(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, service.ErrWalletNotFound):
+			response.Error(c, http.StatusNotFound, response.CodeResourceNotFound, "没有需要重新加密的钱包")
+		case errors.Is(err, service.ErrSecondFactorRequired):
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "该操作需要第二因子验证码")
+		case errors.Is(err, service.ErrSecondFactorInvalid):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "第二因子验证码错误")
+		case errors.Is(err, service.ErrPasswordIncorrect):
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "原钱包密码错误，全部钱包与助记词须使用同一密码")
+		case errors.Is(err, service.ErrPasswordUnchanged):
+			response.Error(c, http.StatusBadRequest, response.CodeInvalidParam, "新密码不能与原密码相同")
+		case errors.Is(err, service.ErrWalletKeysChanged):
+			response.Error(c, http.StatusConflict, response.CodeResourceExists, "钱包在修改期间发生变化，请重试")
+		default:
+			logger.Logger.Error("Failed to change wallet password", zap.Uint("user_id", userID), zap.Error(err))
+			response.Error(c, http.StatusInternalServerError, response.CodeInternalError, "修改钱包密码失败，请稍后重试")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, result, "钱包密码已修改")
 }
@@ -12,6 +12,8 @@ const (
 	CodeUnauthorized     = 1002 // 认证或授权失败
 	CodeResourceExists   = 1003 // 资源已存在（如用户名/地址已注册）
 	CodeResourceNotFound = 1004 // 资源不存在
+	CodeTooManyRequests  = 1005 // 请求过于频繁
+	CodeAccountLocked    = 1006 // 连续失败次数过多，用户名或钱包已被临时锁定
 	CodeInternalError    = 9999 // 服务器内部错误
 )
 
@@ -0,0 +1,71 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/store"
+	dbstore "github.com/bwmspring/go-web3-wallet-backend/pkg/db"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// RotateMasterKey 将全部助记词、TOTP 密钥与 API Key 签名密钥记录的数据密钥重新包装到 key_management.active_key_id 对应的主密钥下。
+// 轮换前需在配置中保留旧主密钥，以便解包现有数据密钥；全部记录完成后方可移除旧密钥。
+func RotateMasterKey(configPath string, batchSize int, dryRun bool) error {
+	// 1. 加载配置并初始化日志
+	cfg, err := config.LoadConfigFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("配置加载失败: %w", err)
+	}
+
+	logger.InitLogger(cfg.Server.Environment)
+	defer logger.Logger.Sync()
+
+	// 2. 初始化主密钥，未配置 key_management 时无法轮换
+	cipher, err := newSeedCipher(cfg.KeyManagement)
+	if err != nil {
+		return fmt.Errorf("failed to init key management: %w", err)
+	}
+	if cipher == nil {
+		return errors.New("key_management is not configured, nothing to rotate")
+	}
+
+	// 3. 初始化数据库连接
+	database, err := dbstore.NewDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	// 4. 收到中断信号时停止，已处理的记录保持一致，可重新执行继续
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rotator := service.NewMasterKeyRotator(
+		store.NewWallets(database.DB()),
+		store.NewTOTPs(database.DB()),
+		store.NewAPIKeys(database.DB()),
+		cipher,
+		batchSize,
+	)
+	result, err := rotator.Rotate(ctx, dryRun)
+
+	logger.Logger.Info("Master key rotation finished",
+		zap.Bool("dry_run", dryRun),
+		zap.String("active_key_id", result.ActiveKeyID),
+		zap.Int("scanned", result.Scanned),
+		zap.Int("rewrapped", result.Rewrapped),
+		zap.Int("sealed", result.Sealed),
+		zap.Int("skipped", result.Skipped),
+	)
+
+	return err
+}
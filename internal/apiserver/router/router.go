@@ -1,12 +1,15 @@
 package router
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/bwmspring/go-web3-wallet-backend/config"
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/controller"
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
 	"github.com/bwmspring/go-web3-wallet-backend/internal/pkg/middleware"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
 )
 
 // RouterConfig holds the dependencies needed to configure the router
@@ -15,17 +18,34 @@ type RouterConfig struct {
 	CORSConfig  *config.CORSConfig
 	LimitConfig *config.LimitConfig
 
-	JWTService service.JWTService
+	JWTService          service.JWTService
+	TokenDenylist       service.TokenDenylist
+	APIKeyAuthenticator service.APIKeyAuthenticator
+	SignatureVerifier   service.RequestSignatureVerifier
 
 	AuthController   *controller.AuthController
 	UserController   *controller.UserController
 	WalletController *controller.WalletController
+	ChainController  *controller.ChainController
+	SIWEController   *controller.SIWEController
+	TOTPController   *controller.TOTPController
+
+	KeyExportController *controller.KeyExportController
+	AdminController     *controller.AdminController
+	APIKeyController    *controller.APIKeyController
 }
 
-// NewRouter initializes and returns the configured Gin Engine
-func NewRouter(cfg *RouterConfig) *gin.Engine {
+// NewRouter initializes and returns the configured Gin Engine,
+// returning an error when server.trusted_proxies contains an invalid IP or CIDR
+func NewRouter(cfg *RouterConfig) (*gin.Engine, error) {
 	r := gin.New()
 
+	// Gin 默认信任所有代理，调用方可通过 X-Forwarded-For 伪造 ClientIP 绕过 API Key IP 白名单，
+	// 只信任显式配置的代理地址
+	if err := r.SetTrustedProxies(cfg.ServerCfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid server trusted_proxies: %w", err)
+	}
+
 	opts := middleware.Options{
 		CORSConfig:  *cfg.CORSConfig,
 		LimitConfig: *cfg.LimitConfig,
@@ -38,22 +58,96 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 		publicV1.POST("/auth/login", cfg.AuthController.Login)
 		publicV1.POST("/auth/logout", cfg.AuthController.Logout)
 		publicV1.POST("/auth/refresh", cfg.AuthController.Refresh)
+		publicV1.POST("/auth/mfa/verify", cfg.AuthController.VerifyMFA)
+		publicV1.GET("/auth/siwe/nonce", cfg.SIWEController.Nonce)
+		publicV1.POST("/auth/siwe/verify", cfg.SIWEController.Verify)
 
 		publicV1.POST("/users/register", cfg.UserController.Register)
+		publicV1.POST("/users/password/forgot", cfg.UserController.ForgotPassword)
+		publicV1.POST("/users/password/reset", cfg.UserController.ResetPassword)
+
+		publicV1.GET("/chains/:chain_id/fees", cfg.ChainController.GetFees)
 	}
 
 	privateV1 := r.Group("/api/v1")
-	privateV1.Use(middleware.AuthMiddleware(cfg.JWTService))
+	privateV1.Use(middleware.AuthMiddleware(cfg.JWTService, cfg.TokenDenylist))
 	{
 		privateV1.GET("/users/profile", cfg.UserController.GetProfile)
+		privateV1.PUT("/users/password", cfg.UserController.ChangePassword)
+		privateV1.GET("/auth/sessions", cfg.AuthController.ListSessions)
+		privateV1.DELETE("/auth/sessions/:id", cfg.AuthController.RevokeSession)
+		privateV1.POST("/auth/tokens/revoke", cfg.AuthController.RevokeTokens)
+		privateV1.POST("/auth/siwe/link", cfg.SIWEController.Link)
+		privateV1.POST("/auth/siwe/password", cfg.SIWEController.SetPassword)
+		privateV1.POST("/auth/totp/enroll", cfg.TOTPController.Enroll)
+		privateV1.POST("/auth/totp/confirm", cfg.TOTPController.Confirm)
+		privateV1.POST("/auth/totp/disable", cfg.TOTPController.Disable)
+		privateV1.POST("/auth/totp/recovery-codes", cfg.TOTPController.RegenerateRecoveryCodes)
 
 		privateV1.POST("/wallet/create", cfg.WalletController.CreateHDWallet)
-		privateV1.GET("/wallet/:address/balance", cfg.WalletController.GetBalance)
+		privateV1.POST("/wallet/import", cfg.WalletController.ImportWallet)
+		privateV1.PUT("/wallet/password", cfg.WalletController.ChangeWalletPassword)
+		privateV1.POST("/wallet/recover", cfg.WalletController.RecoverWallet)
+		privateV1.POST("/wallet/:address/sign", cfg.WalletController.SignMessage)
+		privateV1.POST("/wallet/verify", cfg.WalletController.VerifyMessage)
+		privateV1.POST("/wallet/:address/export/keystore", cfg.KeyExportController.ExportKeystore)
+		privateV1.POST("/wallet/:address/export/mnemonic", cfg.KeyExportController.RevealMnemonic)
+
+		privateV1.POST("/api-keys", cfg.APIKeyController.Create)
+		privateV1.GET("/api-keys", cfg.APIKeyController.List)
+		privateV1.DELETE("/api-keys/:id", cfg.APIKeyController.Revoke)
 	}
 
+	// 服务端集成接口：同时接受 JWT 与 X-API-Key，API Key 需具有相应的权限范围，并可附带（或被要求附带）请求签名
+	integrationV1 := r.Group("/api/v1")
+	integrationV1.Use(
+		middleware.JWTOrAPIKeyAuth(cfg.JWTService, cfg.TokenDenylist, cfg.APIKeyAuthenticator),
+		middleware.VerifySignature(cfg.SignatureVerifier),
+	)
+	{
+		walletRead := middleware.RequireScope(model.ScopeWalletRead)
+		integrationV1.GET("/wallet/:address/balance", walletRead, cfg.WalletController.GetBalance)
+		integrationV1.GET("/wallet/:address/portfolio", walletRead, cfg.WalletController.GetPortfolio)
+
+		txRead := middleware.RequireScope(model.ScopeTxRead)
+		integrationV1.GET("/wallet/:address/transactions", txRead, cfg.WalletController.ListTransactions)
+
+		transfer := middleware.RequireScope(model.ScopeWalletTransfer)
+		integrationV1.POST("/wallet/transfer", transfer, cfg.WalletController.Transfer)
+		integrationV1.POST("/transactions/:hash/speedup", transfer, cfg.WalletController.SpeedUpTransaction)
+		integrationV1.POST("/transactions/:hash/cancel", transfer, cfg.WalletController.CancelTransaction)
+	}
+
+	// 管理接口: /api/v1/admin/*，需要认证，并按接口要求相应角色
+	adminV1 := r.Group("/api/v1/admin")
+	adminV1.Use(middleware.AuthMiddleware(cfg.JWTService, cfg.TokenDenylist))
+	{
+		// 只读：管理员、运维、审计
+		viewers := middleware.RequireRole(model.RoleAdmin, model.RoleOperator, model.RoleAuditor)
+		adminV1.GET("/users", viewers, cfg.AdminController.ListUsers)
+		adminV1.GET("/users/:id", viewers, cfg.AdminController.GetUser)
+		adminV1.GET("/users/:id/wallets", viewers, cfg.AdminController.ListUserWallets)
+
+		// 账户管理：仅管理员
+		admins := middleware.RequireRole(model.RoleAdmin)
+		adminV1.PUT("/users/:id/role", admins, cfg.AdminController.SetUserRole)
+		adminV1.POST("/users/:id/disable", admins, cfg.AdminController.DisableUser)
+		adminV1.POST("/users/:id/enable", admins, cfg.AdminController.EnableUser)
+		adminV1.POST("/users/:id/unlock", admins, cfg.AdminController.UnlockUser)
+		adminV1.POST("/users/:id/tokens/revoke", admins, cfg.AdminController.RevokeUserTokens)
+
+		// 维护任务：管理员、运维
+		operators := middleware.RequireRole(model.RoleAdmin, model.RoleOperator)
+		adminV1.GET("/jobs", operators, cfg.AdminController.ListJobs)
+		adminV1.POST("/jobs/:name", operators, cfg.AdminController.RunJob)
+	}
+
+	// 访问令牌校验公钥 (RFC 7517)
+	r.GET("/.well-known/jwks.json", cfg.AuthController.JWKS)
+
 	r.GET("/healthz", func(c *gin.Context) {
 		c.Status(200)
 	})
 
-	return r
+	return r, nil
 }
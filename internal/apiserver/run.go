@@ -43,16 +43,22 @@ func Run(configPath string) error {
 		logger.Logger.Fatal("Failed to initialize APIServer", zap.Error(err))
 	}
 
-	// 5. 获取配置好的路由
-	router := application.InitRouter()
+	// 5. 获取配置好的路由，配置无效时在启动后台任务前退出
+	router, err := application.InitRouter()
+	if err != nil {
+		logger.Logger.Fatal("Failed to initialize router", zap.Error(err))
+	}
+
+	// 6. 启动后台任务（交易回执追踪等）
+	application.StartWorkers()
 
-	// 6. 配置 HTTP 服务器
+	// 7. 配置 HTTP 服务器
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler: router,
 	}
 
-	// 7. 在独立的 goroutine 中启动服务器
+	// 8. 在独立的 goroutine 中启动服务器
 	go func() {
 		logger.Logger.Info(
 			"APIServer is starting",
@@ -65,14 +71,14 @@ func Run(configPath string) error {
 		}
 	}()
 
-	// 8. 监听操作系统信号，实现优雅关闭
+	// 9. 监听操作系统信号，实现优雅关闭
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	sig := <-quit
 	logger.Logger.Info("Received signal. Starting graceful shutdown", zap.String("signal", sig.String()))
 
-	// 9. 执行优雅关闭（5秒超时）
+	// 10. 执行优雅关闭（5秒超时）
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -80,6 +86,11 @@ func Run(configPath string) error {
 		logger.Logger.Fatal("APIServer forced to shutdown (timeout or error)", zap.Error(err))
 	}
 
+	// 11. HTTP 服务停止后再停止后台任务，共用同一个关闭超时
+	if err := application.StopWorkers(ctx); err != nil {
+		logger.Logger.Error("Background workers forced to stop", zap.Error(err))
+	}
+
 	logger.Logger.Info("APIServer exiting gracefully")
 	return nil
 }
@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+var (
+	ErrInvalidRole      = errors.New("invalid role")
+	ErrCannotModifySelf = errors.New("cannot change the role or status of the current user")
+	ErrJobNotFound      = errors.New("maintenance job not found")
+	ErrJobRunning       = errors.New("maintenance job is already running")
+)
+
+// UserFilter 定义了 store 层的用户查询条件
+type UserFilter struct {
+	Role     string // 空表示不限
+	Disabled *bool  // nil 表示不限
+	BeforeID uint   // 游标：只返回 id < BeforeID 的记录，0 表示从最新开始
+	Limit    int
+}
+
+// UserQuery 定义了用户列表接口的查询参数
+type UserQuery struct {
+	Role     string
+	Disabled *bool
+	Cursor   string // 上一页返回的 next_cursor
+	Limit    int
+}
+
+// UserPage 定义了一页用户查询结果
+type UserPage struct {
+	Items      []model.User `json:"items"`
+	NextCursor string       `json:"next_cursor,omitempty"` // 为空表示没有更多数据
+}
+
+// WalletSummary 是管理接口展示的钱包信息，不包含 Keystore 等敏感字段
+type WalletSummary struct {
+	ID             uint      `json:"id"`
+	UserID         uint      `json:"user_id"`
+	ChainID        uint      `json:"chain_id"`
+	Name           string    `json:"name"`
+	Address        string    `json:"address"`
+	DerivationPath string    `json:"derivation_path,omitempty"`
+	Source         string    `json:"source"`
+	SignerType     string    `json:"signer_type"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// UnlockResult 是解除用户锁定的结果
+type UnlockResult struct {
+	UserID              uint  `json:"user_id"`
+	ClearedLogin        int64 `json:"cleared_login"`         // 清除的用户名失败计数
+	ClearedWallets      int64 `json:"cleared_wallets"`       // 清除的钱包地址失败计数
+	ClearedMnemonics    int64 `json:"cleared_mnemonics"`     // 清除的助记词解密失败计数
+	ClearedSecondFactor int64 `json:"cleared_second_factor"` // 清除的第二因子验证码失败计数
+}
+
+// MaintenanceJob 是可由管理接口手动触发的维护任务，返回任务结果摘要。
+// ctx 在请求取消时结束，任务应保证中途退出后数据一致、可重新执行。
+type MaintenanceJob func(ctx context.Context) (any, error)
+
+// JobResult 是一次维护任务的执行结果
+type JobResult struct {
+	Job        string    `json:"job"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Result     any       `json:"result,omitempty"`
+}
+
+// AdminService 定义了管理接口的业务逻辑。
+// 角色变更与停用账户会吊销该用户已签发的全部令牌，用户需重新登录。
+type AdminService interface {
+	// ListUsers 分页查询用户
+	ListUsers(ctx context.Context, query *UserQuery) (*UserPage, error)
+
+	// GetUser 查询单个用户
+	GetUser(ctx context.Context, userID uint) (*model.User, error)
+
+	// ListUserWallets 查询用户的全部钱包
+	ListUserWallets(ctx context.Context, userID uint) ([]WalletSummary, error)
+
+	// SetUserRole 变更用户角色，actorID 为执行操作的管理员，不能变更自己的角色
+	SetUserRole(ctx context.Context, actorID uint, userID uint, role string) (*model.User, error)
+
+	// DisableUser 停用账户并吊销其全部令牌，actorID 不能停用自己
+	DisableUser(ctx context.Context, actorID uint, userID uint) (*model.User, error)
+
+	// EnableUser 重新启用已停用的账户
+	EnableUser(ctx context.Context, actorID uint, userID uint) (*model.User, error)
+
+	// UnlockUser 清除用户名及其全部钱包地址的失败计数，解除退避与锁定
+	UnlockUser(ctx context.Context, actorID uint, userID uint) (*UnlockResult, error)
+
+	// ListJobs 返回可触发的维护任务名称
+	ListJobs() []string
+
+	// RunJob 同步执行维护任务，同一任务同时只能运行一个
+	RunJob(ctx context.Context, actorID uint, name string) (*JobResult, error)
+
+	// BootstrapAdmins 将 userIDs 中的用户设为 admin，用于引导首个管理员，不存在的用户将被忽略
+	BootstrapAdmins(ctx context.Context, userIDs []uint) error
+}
+
+// adminService 实现了 AdminService 接口
+type adminService struct {
+	userStore         UserStore
+	walletStore       WalletStore
+	revocationService TokenRevocationService
+	limiter           AttemptLimiter
+
+	jobs    map[string]MaintenanceJob
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+var _ AdminService = (*adminService)(nil)
+
+// NewAdminService 创建并返回一个新的 AdminService 实例，jobs 为可触发的维护任务（按名称索引）
+func NewAdminService(
+	userStore UserStore,
+	walletStore WalletStore,
+	revocationService TokenRevocationService,
+	limiter AttemptLimiter,
+	jobs map[string]MaintenanceJob,
+) AdminService {
+	return &adminService{
+		userStore:         userStore,
+		walletStore:       walletStore,
+		revocationService: revocationService,
+		limiter:           limiter,
+		jobs:              jobs,
+		running:           make(map[string]bool, len(jobs)),
+	}
+}
+
+// ListUsers implements AdminService.
+func (s *adminService) ListUsers(ctx context.Context, query *UserQuery) (*UserPage, error) {
+	if query.Role != "" && !model.Roles[query.Role] {
+		return nil, ErrInvalidRole
+	}
+
+	beforeID, err := decodeIDCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := query.Limit
+	if pageSize <= 0 {
+		pageSize = defaultUserPageSize
+	}
+	if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+
+	// 多取一条用于判断是否还有下一页
+	users, err := s.userStore.ListUsers(ctx, &UserFilter{
+		Role:     query.Role,
+		Disabled: query.Disabled,
+		BeforeID: beforeID,
+		Limit:    pageSize + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Items: users}
+	if len(users) > pageSize {
+		page.Items = users[:pageSize]
+		page.NextCursor = encodeIDCursor(page.Items[pageSize-1].ID)
+	}
+
+	return page, nil
+}
+
+// GetUser implements AdminService.
+func (s *adminService) GetUser(ctx context.Context, userID uint) (*model.User, error) {
+	user, err := s.userStore.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+
+	return user, nil
+}
+
+// ListUserWallets implements AdminService.
+func (s *adminService) ListUserWallets(ctx context.Context, userID uint) ([]WalletSummary, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	wallets, err := s.walletStore.ListWalletsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WalletSummary, 0, len(wallets))
+	for _, wallet := range wallets {
+		result = append(result, WalletSummary{
+			ID:             wallet.ID,
+			UserID:         wallet.UserID,
+			ChainID:        wallet.ChainID,
+			Name:           wallet.Name,
+			Address:        wallet.Address,
+			DerivationPath: wallet.DerivationPath,
+			Source:         wallet.Source,
+			SignerType:     wallet.SignerType,
+			CreatedAt:      wallet.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// SetUserRole implements AdminService.
+func (s *adminService) SetUserRole(ctx context.Context, actorID uint, userID uint, role string) (*model.User, error) {
+	if !model.Roles[role] {
+		return nil, ErrInvalidRole
+	}
+	// 禁止变更自己的角色，避免唯一的管理员误操作后失去管理权限
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	updated, err := s.userStore.UpdateUserRole(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrUserNotFound
+	}
+
+	// 已签发的访问令牌携带旧角色，吊销后用户重新登录即获得新角色
+	if _, err := s.revocationService.RevokeUserTokens(ctx, userID, time.Time{}); err != nil {
+		return nil, fmt.Errorf("role updated but failed to revoke tokens: %w", err)
+	}
+
+	logger.Logger.Info("User role changed",
+		zap.Uint("actor_id", actorID),
+		zap.Uint("user_id", userID),
+		zap.String("from", user.Role),
+		zap.String("to", role),
+	)
+
+	user.Role = role
+	return user, nil
+}
+
+// DisableUser implements AdminService.
+func (s *adminService) DisableUser(ctx context.Context, actorID uint, userID uint) (*model.User, error) {
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return user, nil
+	}
+
+	now := time.Now()
+	updated, err := s.userStore.SetUserDisabledAt(ctx, userID, &now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrUserNotFound
+	}
+
+	// 登录、刷新与第二步验证均会拒绝停用的账户，这里吊销已签发的令牌使其立即下线
+	if _, err := s.revocationService.RevokeUserTokens(ctx, userID, time.Time{}); err != nil {
+		return nil, fmt.Errorf("user disabled but failed to revoke tokens: %w", err)
+	}
+
+	logger.Logger.Info("User disabled", zap.Uint("actor_id", actorID), zap.Uint("user_id", userID))
+
+	user.DisabledAt = &now
+	return user, nil
+}
+
+// EnableUser implements AdminService.
+func (s *adminService) EnableUser(ctx context.Context, actorID uint, userID uint) (*model.User, error) {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DisabledAt == nil {
+		return user, nil
+	}
+
+	updated, err := s.userStore.SetUserDisabledAt(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrUserNotFound
+	}
+
+	logger.Logger.Info("User enabled", zap.Uint("actor_id", actorID), zap.Uint("user_id", userID))
+
+	user.DisabledAt = nil
+	return user, nil
+}
+
+// UnlockUser implements AdminService.
+func (s *adminService) UnlockUser(ctx context.Context, actorID uint, userID uint) (*UnlockResult, error) {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := s.walletStore.ListWalletsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	addresses := make([]string, 0, len(wallets))
+	for _, wallet := range wallets {
+		addresses = append(addresses, wallet.Address)
+	}
+
+	result := &UnlockResult{UserID: userID}
+	if result.ClearedLogin, err = s.limiter.Unlock(ctx, model.AttemptKindLogin, user.Username); err != nil {
+		return nil, err
+	}
+	if result.ClearedWallets, err = s.limiter.Unlock(ctx, model.AttemptKindWallet, addresses...); err != nil {
+		return nil, err
+	}
+	if result.ClearedMnemonics, err = s.limiter.Unlock(ctx, model.AttemptKindMnemonic, userAttemptSubject(userID)); err != nil {
+		return nil, err
+	}
+	if result.ClearedSecondFactor, err = s.limiter.Unlock(ctx, model.AttemptKindTOTP, userAttemptSubject(userID)); err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("User unlocked",
+		zap.Uint("actor_id", actorID),
+		zap.Uint("user_id", userID),
+		zap.Int64("cleared_login", result.ClearedLogin),
+		zap.Int64("cleared_wallets", result.ClearedWallets),
+		zap.Int64("cleared_mnemonics", result.ClearedMnemonics),
+		zap.Int64("cleared_second_factor", result.ClearedSecondFactor),
+	)
+
+	return result, nil
+}
+
+// ListJobs implements AdminService.
+func (s *adminService) ListJobs() []string {
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RunJob implements AdminService.
+func (s *adminService) RunJob(ctx context.Context, actorID uint, name string) (*JobResult, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	s.mu.Lock()
+	if s.running[name] {
+		s.mu.Unlock()
+		return nil, ErrJobRunning
+	}
+	s.running[name] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, name)
+		s.mu.Unlock()
+	}()
+
+	logger.Logger.Info("Maintenance job started", zap.Uint("actor_id", actorID), zap.String("job", name))
+
+	result := &JobResult{Job: name, StartedAt: time.Now()}
+	output, err := job(ctx)
+	result.FinishedAt = time.Now()
+	result.Result = output
+
+	if err != nil {
+		logger.Logger.Error("Maintenance job failed",
+			zap.Uint("actor_id", actorID),
+			zap.String("job", name),
+			zap.Duration("elapsed", result.FinishedAt.Sub(result.StartedAt)),
+			zap.Error(err),
+		)
+		return result, fmt.Errorf("maintenance job %s failed: %w", name, err)
+	}
+
+	logger.Logger.Info("Maintenance job finished",
+		zap.Uint("actor_id", actorID),
+		zap.String("job", name),
+		zap.Duration("elapsed", result.FinishedAt.Sub(result.StartedAt)),
+	)
+
+	return result, nil
+}
+
+// BootstrapAdmins implements AdminService.
+func (s *adminService) BootstrapAdmins(ctx context.Context, userIDs []uint) error {
+	for _, userID := range userIDs {
+		user, err := s.GetUser(ctx, userID)
+		if errors.Is(err, ErrUserNotFound) {
+			logger.Logger.Warn("Bootstrap admin user not found, skipped", zap.Uint("user_id", userID))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if user.Role == model.RoleAdmin {
+			continue
+		}
+
+		if _, err := s.userStore.UpdateUserRole(ctx, userID, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		logger.Logger.Info("Bootstrap admin granted",
+			zap.Uint("user_id", userID),
+			zap.String("previous_role", user.Role),
+		)
+	}
+
+	return nil
+}
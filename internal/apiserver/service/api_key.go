@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultAPIKeyMaxPerUser = 10
+
+	// apiKeyPrefix 是全部 API Key 的固定开头，便于密钥扫描工具识别泄露
+	apiKeyPrefix = "wk_"
+
+	// apiKeyIDBytes 生成 12 位十六进制的可见部分，apiKeySecretBytes 是密钥与签名密钥的随机部分 (256 bit)
+	apiKeyIDBytes     = 6
+	apiKeySecretBytes = 32
+
+	// apiKeyTouchInterval 内重复使用同一密钥不再更新 last_used_at
+	apiKeyTouchInterval = time.Minute
+
+	// maxAPIKeyAllowedIPs 是每个 API Key 的 IP 白名单条目上限
+	maxAPIKeyAllowedIPs = 20
+)
+
+var (
+	ErrAPIKeyInvalid       = errors.New("api key is invalid, expired or revoked")
+	ErrAPIKeyIPNotAllowed  = errors.New("client ip is not in the api key allowlist")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrAPIKeyLimitExceeded = errors.New("too many active api keys")
+	ErrAPIKeyParamInvalid  = errors.New("invalid api key parameters")
+)
+
+// APIKeyStore 定义了 API Key 的存储接口
+type APIKeyStore interface {
+	// CreateAPIKey 保存新创建的 API Key
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+
+	// GetAPIKeyByHash 根据完整密钥的哈希查找记录（含已吊销、已过期的记录），不存在时返回 nil, nil
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+
+	// GetAPIKeyByID 根据 ID 查找记录，不存在时返回 nil, nil
+	GetAPIKeyByID(ctx context.Context, keyID uint) (*model.APIKey, error)
+
+	// ListAPIKeys 按创建时间倒序返回用户的全部 API Key
+	ListAPIKeys(ctx context.Context, userID uint) ([]model.APIKey, error)
+
+	// CountActiveAPIKeys 统计用户未吊销且未过期的 API Key 数量
+	CountActiveAPIKeys(ctx context.Context, userID uint, now time.Time) (int64, error)
+
+	// RevokeAPIKey 吊销用户的 API Key，记录不存在、不属于该用户或已吊销时返回 false
+	RevokeAPIKey(ctx context.Context, userID uint, keyID uint, now time.Time) (bool, error)
+
+	// TouchAPIKey 更新最近使用时间，仅当上次记录早于 staleBefore 时写入
+	TouchAPIKey(ctx context.Context, keyID uint, now time.Time, staleBefore time.Time) error
+
+	// UseRequestNonce 原子地记录签名请求的 nonce，同一密钥的 nonce 已存在（重放）时返回 false
+	UseRequestNonce(ctx context.Context, nonce *model.APIRequestNonce) (bool, error)
+
+	// DeleteExpiredRequestNonces 清理 before 之前过期的 nonce
+	DeleteExpiredRequestNonces(ctx context.Context, before time.Time) (int64, error)
+
+	// ListAPIKeySigningSecrets 按 ID 升序分页列出带有签名密钥的记录，供主密钥轮换使用
+	ListAPIKeySigningSecrets(ctx context.Context, afterID uint, limit int) ([]*model.APIKey, error)
+
+	// UpdateAPIKeyEnvelope 以原 master_key_id 为条件更新签名密钥的信封字段，记录已被并发修改时返回 false
+	UpdateAPIKeyEnvelope(ctx context.Context, key *model.APIKey, previousKeyID string) (bool, error)
+}
+
+// APIKeyAuthenticator 定义了 APIKeyAuth 中间件使用的 API Key 认证
+type APIKeyAuthenticator interface {
+	// Authenticate 校验 API Key 及请求来源 IP，成功时返回密钥所属用户与权限范围
+	Authenticate(ctx context.Context, rawKey string, clientIP string) (*APIKeyPrincipal, error)
+}
+
+// APIKeyService 定义了 API Key 的业务接口
+type APIKeyService interface {
+	APIKeyAuthenticator
+	RequestSignatureVerifier
+
+	// Create 创建 API Key，完整密钥只在返回值中出现一次。用户已绑定第二因子时需提交验证码
+	Create(ctx context.Context, params *CreateAPIKeyParams) (*CreatedAPIKey, error)
+
+	// List 返回用户的全部 API Key（不含完整密钥）
+	List(ctx context.Context, userID uint) ([]APIKeyInfo, error)
+
+	// Revoke 吊销用户的 API Key，立即生效
+	Revoke(ctx context.Context, userID uint, keyID uint) error
+}
+
+// APIKeyPrincipal 是通过 API Key 认证的调用方
+type APIKeyPrincipal struct {
+	KeyID  uint
+	UserID uint
+	Scopes []string
+
+	// RequireSignature 为 true 时该密钥的请求必须携带有效签名，见 RequestSignatureVerifier
+	RequireSignature bool
+}
+
+// CreateAPIKeyParams 定义了创建 API Key 的参数
+type CreateAPIKeyParams struct {
+	UserID     uint
+	Name       string
+	Scopes     []string   // 见 model.Scope*
+	AllowedIPs []string   // IP 或 CIDR，为空表示不限
+	ExpiresAt  *time.Time // 为空表示永不过期（受 api_key.max_ttl 限制）
+
+	// RequireSignature 为 true 时该密钥的请求必须签名，防止密钥泄露后被直接使用
+	RequireSignature bool
+
+	SecondFactorCode string
+}
+
+// APIKeyInfo 是对用户展示的 API Key 信息
+type APIKeyInfo struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowed_ips,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	RequireSignature bool `json:"require_signature"`
+}
+
+// CreatedAPIKey 是新创建的 API Key，Key 为完整密钥（服务端只保存哈希），SigningSecret 为请求签名密钥，均只返回一次
+type CreatedAPIKey struct {
+	APIKeyInfo
+	Key           string `json:"key"`
+	SigningSecret string `json:"signing_secret"`
+}
+
+// apiKeyService 实现了 APIKeyService 接口
+type apiKeyService struct {
+	store        APIKeyStore
+	userStore    UserStore
+	secondFactor SecondFactorVerifier
+	cipher       crypto.EnvelopeCipher // 为 nil 时签名密钥不做信封加密
+
+	maxPerUser    int
+	maxTTL        time.Duration // 0 表示不限
+	signatureSkew time.Duration
+}
+
+var _ APIKeyService = (*apiKeyService)(nil)
+
+// NewAPIKeyService 创建并返回一个新的 APIKeyService 实例
+func NewAPIKeyService(
+	store APIKeyStore,
+	userStore UserStore,
+	secondFactor SecondFactorVerifier,
+	cipher crypto.EnvelopeCipher,
+	cfg *config.Config,
+) (APIKeyService, error) {
+	maxTTL, err := parseDurationOrDefault(cfg.APIKey.MaxTTL, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api_key max_ttl: %w", err)
+	}
+
+	signatureSkew, err := parseDurationOrDefault(cfg.APIKey.SignatureSkew, defaultSignatureSkew)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api_key signature_skew: %w", err)
+	}
+
+	maxPerUser := cfg.APIKey.MaxPerUser
+	if maxPerUser <= 0 {
+		maxPerUser = defaultAPIKeyMaxPerUser
+	}
+
+	return &apiKeyService{
+		store:         store,
+		userStore:     userStore,
+		secondFactor:  secondFactor,
+		cipher:        cipher,
+		maxPerUser:    maxPerUser,
+		maxTTL:        maxTTL,
+		signatureSkew: signatureSkew,
+	}, nil
+}
+
+// Authenticate implements APIKeyAuthenticator.
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string, clientIP string) (*APIKeyPrincipal, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	key, err := s.store.GetAPIKeyByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key == nil || key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if !ipAllowed(splitList(key.AllowedIPs, ","), clientIP) {
+		logger.Logger.Warn("API key used from disallowed ip",
+			zap.Uint("key_id", key.ID),
+			zap.String("prefix", key.Prefix),
+			zap.String("client_ip", clientIP),
+		)
+		return nil, ErrAPIKeyIPNotAllowed
+	}
+
+	// 停用的账户或已删除的用户，其 API Key 一并失效
+	user, err := s.userStore.FindByID(key.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	// 记录最近使用时间，失败不影响认证
+	if err := s.store.TouchAPIKey(ctx, key.ID, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		logger.Logger.Warn("Failed to update api key usage", zap.Uint("key_id", key.ID), zap.Error(err))
+	}
+
+	return &APIKeyPrincipal{
+		KeyID:            key.ID,
+		UserID:           key.UserID,
+		Scopes:           splitList(key.Scopes, " "),
+		RequireSignature: key.RequireSignature,
+	}, nil
+}
+
+// Create implements APIKeyService.
+func (s *apiKeyService) Create(ctx context.Context, params *CreateAPIKeyParams) (*CreatedAPIKey, error) {
+	now := time.Now()
+
+	scopes, allowedIPs, err := s.validateParams(params, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// API Key 可绕过登录与第二因子直接调用接口，创建时需通过第二因子校验
+	if err := checkSecondFactor(ctx, s.secondFactor, params.UserID, params.SecondFactorCode, false); err != nil {
+		return nil, err
+	}
+
+	active, err := s.store.CountActiveAPIKeys(ctx, params.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active >= int64(s.maxPerUser) {
+		return nil, ErrAPIKeyLimitExceeded
+	}
+
+	rawKey, prefix, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	signingSecret, err := newSigningSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.APIKey{
+		UserID:           params.UserID,
+		Name:             params.Name,
+		Prefix:           prefix,
+		KeyHash:          hashAPIKey(rawKey),
+		Scopes:           strings.Join(scopes, " "),
+		AllowedIPs:       strings.Join(allowedIPs, ","),
+		ExpiresAt:        params.ExpiresAt,
+		RequireSignature: params.RequireSignature,
+	}
+	if err := s.sealSigningSecret(ctx, key, signingSecret); err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("API key created",
+		zap.Uint("user_id", params.UserID),
+		zap.Uint("key_id", key.ID),
+		zap.String("prefix", prefix),
+		zap.Strings("scopes", scopes),
+		zap.Bool("require_signature", params.RequireSignature),
+	)
+
+	return &CreatedAPIKey{
+		APIKeyInfo:    apiKeyInfo(key),
+		Key:           rawKey,
+		SigningSecret: signingSecret,
+	}, nil
+}
+
+// List implements APIKeyService.
+func (s *apiKeyService) List(ctx context.Context, userID uint) ([]APIKeyInfo, error) {
+	keys, err := s.store.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]APIKeyInfo, 0, len(keys))
+	for i := range keys {
+		result = append(result, apiKeyInfo(&keys[i]))
+	}
+
+	return result, nil
+}
+
+// Revoke implements APIKeyService.
+func (s *apiKeyService) Revoke(ctx context.Context, userID uint, keyID uint) error {
+	revoked, err := s.store.RevokeAPIKey(ctx, userID, keyID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	logger.Logger.Info("API key revoked", zap.Uint("user_id", userID), zap.Uint("key_id", keyID))
+	return nil
+}
+
+// validateParams 校验并规范化权限范围、IP 白名单与过期时间
+func (s *apiKeyService) validateParams(params *CreateAPIKeyParams, now time.Time) ([]string, []string, error) {
+	if strings.TrimSpace(params.Name) == "" {
+		return nil, nil, fmt.Errorf("%w: name is required", ErrAPIKeyParamInvalid)
+	}
+
+	seen := make(map[string]bool, len(params.Scopes))
+	scopes := make([]string, 0, len(params.Scopes))
+	for _, scope := range params.Scopes {
+		if !model.Scopes[scope] {
+			return nil, nil, fmt.Errorf("%w: unknown scope %q", ErrAPIKeyParamInvalid, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one scope is required", ErrAPIKeyParamInvalid)
+	}
+
+	if len(params.AllowedIPs) > maxAPIKeyAllowedIPs {
+		return nil, nil, fmt.Errorf("%w: at most %d allowed ips", ErrAPIKeyParamInvalid, maxAPIKeyAllowedIPs)
+	}
+	allowedIPs := make([]string, 0, len(params.AllowedIPs))
+	for _, entry := range params.AllowedIPs {
+		prefix, err := parseIPPrefix(entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid allowed ip %q", ErrAPIKeyParamInvalid, entry)
+		}
+		allowedIPs = append(allowedIPs, prefix.String())
+	}
+
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(now) {
+		return nil, nil, fmt.Errorf("%w: expires_at must be in the future", ErrAPIKeyParamInvalid)
+	}
+	if s.maxTTL > 0 {
+		if params.ExpiresAt == nil || params.ExpiresAt.After(now.Add(s.maxTTL)) {
+			return nil, nil, fmt.Errorf("%w: expires_at must be within %s", ErrAPIKeyParamInvalid, s.maxTTL)
+		}
+	}
+
+	return scopes, allowedIPs, nil
+}
+
+// newAPIKey 生成完整密钥 wk_<12 位十六进制>_<随机部分>，返回完整密钥与可见前缀
+func newAPIKey() (string, string, error) {
+	id := make([]byte, apiKeyIDBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix := apiKeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+// hashAPIKey 计算完整密钥的 SHA-256，数据库只保存哈希
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyInfo 将 API Key 记录转换为展示信息
+func apiKeyInfo(key *model.APIKey) APIKeyInfo {
+	return APIKeyInfo{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     splitList(key.Scopes, " "),
+		AllowedIPs: splitList(key.AllowedIPs, ","),
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+
+		RequireSignature: key.RequireSignature,
+	}
+}
+
+// parseIPPrefix 解析 IP 或 CIDR，单个 IP 视为 /32 (IPv6 为 /128)
+func parseIPPrefix(entry string) (netip.Prefix, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ipAllowed 检查 clientIP 是否在白名单内，白名单为空时不限制
+func ipAllowed(allowlist []string, clientIP string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, entry := range allowlist {
+		prefix, err := parseIPPrefix(entry)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitList 按 sep 拆分非空字符串，空字符串返回 nil
+func splitList(value string, sep string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, sep)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/web3client"
+)
+
+// ChainService 定义了链级公共信息（如手续费行情）的业务逻辑接口
+type ChainService interface {
+	// SuggestFees 返回指定链的 slow / standard / fast 三档手续费建议
+	SuggestFees(ctx context.Context, chainID uint) (*web3client.FeeEstimate, error)
+}
+
+// chainService 实现了 ChainService 接口
+type chainService struct {
+	clientManager web3client.ClientManager
+	feeOracle     web3client.FeeOracle
+}
+
+var _ ChainService = (*chainService)(nil)
+
+// NewChainService 创建并返回一个新的 ChainService 实例
+func NewChainService(clientManager web3client.ClientManager, feeOracle web3client.FeeOracle) ChainService {
+	return &chainService{
+		clientManager: clientManager,
+		feeOracle:     feeOracle,
+	}
+}
+
+// SuggestFees implements ChainService.
+func (s *chainService) SuggestFees(ctx context.Context, chainID uint) (*web3client.FeeEstimate, error) {
+	if _, err := s.clientManager.GetClient(chainID); err != nil {
+		return nil, fmt.Errorf("%w: %d", ErrChainNotSupported, chainID)
+	}
+
+	estimate, err := s.feeOracle.SuggestFees(ctx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate fees: %w", err)
+	}
+
+	return estimate, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/bwmspring/go-web3-wallet-backend/config"
@@ -19,10 +20,23 @@ var (
 	ErrTokenGenerationFailed     = errors.New("failed to generate authentication token")
 )
 
+// TokenPurposeMFAPending 标识登录第二步使用的 mfa_pending 令牌，只能用于换取访问令牌
+const TokenPurposeMFAPending = "mfa_pending"
+
 // JWTClaims 定义了 JWT 有效载荷中应包含的自定义信息
 type JWTClaims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Role      string `json:"role,omitempty"` // 签发时的用户角色，角色变更后需重新登录才能生效
+	SessionID string `json:"sid,omitempty"`  // 签发该访问令牌的会话 ID，见 SessionService
+
+	// Purpose 为空表示访问令牌；非空的令牌（如 mfa_pending）不能用于访问接口
+	Purpose string `json:"purpose,omitempty"`
+
+	// 以下字段由 ValidateToken 从标准声明 (jti / iat / exp) 中填充，供吊销检查使用
+	TokenID   string    `json:"-"`
+	IssuedAt  time.Time `json:"-"`
+	ExpiresAt time.Time `json:"-"`
 }
 
 // CustomClaims 扩展 jwt.RegisteredClaims 以包含自定义信息
@@ -31,59 +45,127 @@ type CustomClaims struct {
 	jwt.RegisteredClaims
 }
 
-// JWTService 定义了管理 JSON Web Token (JWT) 的核心契约
+// JWTService 定义了管理 JSON Web Token (JWT) 的核心契约。
+// JWT 仅作为短期访问令牌使用，续期通过 SessionService 的刷新令牌完成。
 type JWTService interface {
-	GenerateToken(user *model.User) (string, error)
+	GenerateToken(user *model.User, sessionID string) (string, error)
 	ValidateToken(token string) (*JWTClaims, error)
-	RefreshToken(token string) (string, error)
+
+	// GenerateMFAToken 签发短期 mfa_pending 令牌：用户已通过密码校验，尚需提交第二因子
+	GenerateMFAToken(user *model.User, ttl time.Duration) (string, error)
+
+	// ValidateMFAToken 校验 mfa_pending 令牌，访问令牌不能通过该校验
+	ValidateMFAToken(token string) (*JWTClaims, error)
+
+	// AccessTokenTTL 返回访问令牌的有效期
+	AccessTokenTTL() time.Duration
+
+	// JWKS 返回用于校验访问令牌的公钥集合，使用 HS256 共享密钥时为空集合
+	JWKS() *JSONWebKeySet
 }
 
 // jwtService 是 JWTService 接口的具体实现。
+// 配置了 jwt_keys 时使用非对称密钥 (RS256 / EdDSA) 签名并在头部写入 kid，
+// 校验时按 kid 选择密钥；否则使用 jwt_secret 进行 HS256 签名。
 type jwtService struct {
 	secretKey []byte
 	duration  time.Duration
+
+	signingKey *jwtKey            // 为空时使用 HS256
+	keys       map[string]*jwtKey // 全部可用于校验的密钥，按 kid 索引
+	jwks       *JSONWebKeySet
 }
 
 // NewJWTService 创建并返回一个新的 JWTService 实例。
-func NewJWTService(cfg *config.Config) JWTService {
+func NewJWTService(cfg *config.Config) (JWTService, error) {
 	duration, err := time.ParseDuration(cfg.Server.JWTDuration)
 	if err != nil {
-		logger.Logger.Fatal(
-			"JWT duration configuration is invalid",
-			zap.String("duration_str", cfg.Server.JWTDuration),
-			zap.Error(err),
-		)
+		return nil, fmt.Errorf("invalid jwt_duration %q: %w", cfg.Server.JWTDuration, err)
+	}
+
+	svc := &jwtService{
+		duration: duration,
+		jwks:     &JSONWebKeySet{Keys: []JSONWebKey{}},
 	}
 
-	if len(cfg.Server.JWTSecret) < 32 {
-		logger.Logger.Warn("JWT secret key is too short. Use a long, random string in production.")
+	if len(cfg.Server.JWTKeys) == 0 {
+		if len(cfg.Server.JWTSecret) < 32 {
+			logger.Logger.Warn("JWT secret key is too short. Use a long, random string in production.")
+		}
+		svc.secretKey = []byte(cfg.Server.JWTSecret)
+		return svc, nil
 	}
 
-	return &jwtService{
-		secretKey: []byte(cfg.Server.JWTSecret),
-		duration:  duration,
+	keys, signingKey, err := loadJWTKeys(cfg.Server.JWTKeys, cfg.Server.JWTSigningKeyID)
+	if err != nil {
+		return nil, err
 	}
+	svc.keys = keys
+	svc.signingKey = signingKey
+
+	// 按配置顺序输出，保证 JWKS 响应稳定
+	for _, keyCfg := range cfg.Server.JWTKeys {
+		svc.jwks.Keys = append(svc.jwks.Keys, keys[keyCfg.ID].jwk())
+	}
+
+	logger.Logger.Info("JWT asymmetric signing enabled",
+		zap.String("kid", signingKey.id),
+		zap.String("alg", signingKey.method.Alg()),
+		zap.Int("verification_keys", len(keys)),
+	)
+
+	return svc, nil
 }
 
 // GenerateToken 实现 JWTService 接口
-func (s *jwtService) GenerateToken(user *model.User) (string, error) {
+func (s *jwtService) GenerateToken(user *model.User, sessionID string) (string, error) {
+	return s.sign(JWTClaims{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID,
+	}, s.duration)
+}
+
+// GenerateMFAToken 实现 JWTService 接口
+func (s *jwtService) GenerateMFAToken(user *model.User, ttl time.Duration) (string, error) {
+	return s.sign(JWTClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Purpose:  TokenPurposeMFAPending,
+	}, ttl)
+}
+
+// sign 签发包含 custom 的令牌
+func (s *jwtService) sign(custom JWTClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := CustomClaims{
-		JWTClaims: JWTClaims{
-			UserID:   user.ID,
-			Username: user.Username,
-		},
+		JWTClaims: custom,
 		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.duration)),
+			ID:        uuid.NewString(), // jti，用于单独吊销该令牌
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			Issuer:    "go-web3-wallet-backend",
 		},
 	}
+	if custom.Purpose != "" {
+		// 非访问令牌写入 aud，通过 JWKS 校验令牌的其他服务可据此拒绝
+		claims.Audience = jwt.ClaimStrings{custom.Purpose}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	signedToken, err := token.SignedString(s.secretKey)
+	var (
+		signedToken string
+		err         error
+	)
+	if s.signingKey != nil {
+		token := jwt.NewWithClaims(s.signingKey.method, claims)
+		token.Header["kid"] = s.signingKey.id
+		signedToken, err = token.SignedString(s.signingKey.private)
+	} else {
+		signedToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secretKey)
+	}
 	if err != nil {
-		logger.Logger.Error("Error signing JWT token", zap.Uint("user_id", user.ID), zap.Error(err))
+		logger.Logger.Error("Error signing JWT token", zap.Uint("user_id", custom.UserID), zap.Error(err))
 		return "", fmt.Errorf("%w: failed to sign token: %s", ErrTokenGenerationFailed, err.Error())
 	}
 
@@ -92,12 +174,31 @@ func (s *jwtService) GenerateToken(user *model.User) (string, error) {
 
 // ValidateToken 实现 JWTService 接口
 func (s *jwtService) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrTokenSigningMethodInvalid
-		}
-		return s.secretKey, nil
-	})
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, fmt.Errorf("%w: token purpose %q is not access", ErrTokenInvalidOrExpired, claims.Purpose)
+	}
+	return claims, nil
+}
+
+// ValidateMFAToken 实现 JWTService 接口
+func (s *jwtService) ValidateMFAToken(tokenString string) (*JWTClaims, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != TokenPurposeMFAPending {
+		return nil, fmt.Errorf("%w: token is not an mfa_pending token", ErrTokenInvalidOrExpired)
+	}
+	return claims, nil
+}
+
+// parse 校验令牌签名与有效期并返回其声明
+func (s *jwtService) parse(tokenString string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, s.keyFunc)
 
 	if err != nil {
 		logger.Logger.Debug("Token parsing/validation error", zap.Error(err))
@@ -113,25 +214,49 @@ func (s *jwtService) ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	// token.Valid 检查了 ExpiresAt 和 IssuedAt 等标准声明
 	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
-		return &claims.JWTClaims, nil
+		result := claims.JWTClaims
+		result.TokenID = claims.ID
+		if iat := claims.RegisteredClaims.IssuedAt; iat != nil {
+			result.IssuedAt = iat.Time
+		}
+		if exp := claims.RegisteredClaims.ExpiresAt; exp != nil {
+			result.ExpiresAt = exp.Time
+		}
+		return &result, nil
 	}
 
 	// 最终的通用失败情况
 	return nil, ErrTokenInvalidOrExpired
 }
 
-// RefreshToken 刷新 JWT Token
-func (s *jwtService) RefreshToken(tokenString string) (string, error) {
-	claims, err := s.ValidateToken(tokenString)
-	if err != nil {
-		return "", err
+// keyFunc 选择校验签名的密钥。非对称模式下按头部 kid 选择，且签名算法必须与密钥匹配，
+// 防止使用公钥作为 HMAC 密钥等算法混淆攻击。
+func (s *jwtService) keyFunc(token *jwt.Token) (interface{}, error) {
+	if s.signingKey == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrTokenSigningMethodInvalid
+		}
+		return s.secretKey, nil
 	}
 
-	// 根据用户 ID 重新生成 Token
-	user := &model.User{
-		ID:       claims.UserID,
-		Username: claims.Username,
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kid %q", ErrTokenInvalidOrExpired, kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrTokenSigningMethodInvalid
 	}
 
-	return s.GenerateToken(user)
+	return key.public, nil
+}
+
+// AccessTokenTTL 实现 JWTService 接口
+func (s *jwtService) AccessTokenTTL() time.Duration {
+	return s.duration
+}
+
+// JWKS 实现 JWTService 接口
+func (s *jwtService) JWKS() *JSONWebKeySet {
+	return s.jwks
 }
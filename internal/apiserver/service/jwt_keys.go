@@ -0,0 +1,193 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+)
+
+// minRSAKeyBits 是 RS256 签名密钥的最小长度
+const minRSAKeyBits = 2048
+
+var ErrJWTKeyInvalid = errors.New("invalid jwt key configuration")
+
+// JSONWebKey 是 JWKS 中的一把公钥 (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet 是 /.well-known/jwks.json 返回的公钥集合
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// jwtKey 是一把非对称 JWT 密钥，private 为空时只用于校验（轮换前的旧密钥）
+type jwtKey struct {
+	id      string
+	method  jwt.SigningMethod
+	public  crypto.PublicKey
+	private crypto.Signer
+}
+
+// loadJWTKeys 从 PEM 文件加载全部 JWT 密钥，并返回 signingKeyID 对应的签名密钥
+func loadJWTKeys(keyCfgs []config.JWTKeyConfig, signingKeyID string) (map[string]*jwtKey, *jwtKey, error) {
+	keys := make(map[string]*jwtKey, len(keyCfgs))
+
+	for _, keyCfg := range keyCfgs {
+		if keyCfg.ID == "" {
+			return nil, nil, fmt.Errorf("%w: key id is required", ErrJWTKeyInvalid)
+		}
+		if _, exists := keys[keyCfg.ID]; exists {
+			return nil, nil, fmt.Errorf("%w: duplicate key id %q", ErrJWTKeyInvalid, keyCfg.ID)
+		}
+
+		key, err := loadJWTKey(keyCfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: key %q: %w", ErrJWTKeyInvalid, keyCfg.ID, err)
+		}
+		keys[keyCfg.ID] = key
+	}
+
+	signingKey, ok := keys[signingKeyID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: signing key %q is not configured", ErrJWTKeyInvalid, signingKeyID)
+	}
+	if signingKey.private == nil {
+		return nil, nil, fmt.Errorf("%w: signing key %q has no private key", ErrJWTKeyInvalid, signingKeyID)
+	}
+
+	return keys, signingKey, nil
+}
+
+// loadJWTKey 加载单把密钥：配置了私钥时从私钥推导公钥，否则只加载公钥
+func loadJWTKey(keyCfg config.JWTKeyConfig) (*jwtKey, error) {
+	key := &jwtKey{id: keyCfg.ID}
+
+	switch {
+	case keyCfg.PrivateKeyFile != "":
+		block, err := readPEMBlock(keyCfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		private, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+		key.private = private
+		key.public = private.Public()
+
+	case keyCfg.PublicKeyFile != "":
+		block, err := readPEMBlock(keyCfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		key.public = public
+
+	default:
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, only RSA and Ed25519 are supported", key.public)
+	}
+
+	return key, nil
+}
+
+// readPEMBlock 读取文件中的第一个 PEM 块
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+
+	return block, nil
+}
+
+// parsePrivateKey 解析 PKCS#8 私钥，兼容 PKCS#1 格式的 RSA 私钥
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		return private, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		return private, nil
+	case ed25519.PrivateKey:
+		return private, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+}
+
+// jwk 返回密钥的 JWK 公钥表示
+func (k *jwtKey) jwk() JSONWebKey {
+	result := JSONWebKey{
+		Kid: k.id,
+		Use: "sig",
+		Alg: k.method.Alg(),
+	}
+
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		result.Kty = "RSA"
+		result.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		result.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		result.Kty = "OKP"
+		result.Crv = "Ed25519"
+		result.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return result
+}
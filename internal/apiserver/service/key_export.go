@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultKeyExportMaxAttempts = 5
+	defaultKeyExportWindow      = time.Hour
+)
+
+// 导出审计记录中的失败原因
+const (
+	KeyExportReasonRateLimited       = "rate_limited"
+	KeyExportReasonInvalidCredential = "invalid_account_password"
+	KeyExportReasonSecondFactor      = "second_factor_failed"
+	KeyExportReasonWalletNotFound    = "wallet_not_found"
+	KeyExportReasonNoMnemonic        = "no_mnemonic"
+	KeyExportReasonRemoteSigner      = "remote_signer"
+	KeyExportReasonWalletPassword    = "invalid_wallet_password"
+	KeyExportReasonInternalError     = "internal_error"
+)
+
+var (
+	ErrKeyExportRateLimited = errors.New("too many key export attempts, try again later")
+	ErrMnemonicNotAvailable = errors.New("wallet was not derived from a stored mnemonic")
+)
+
+// KeyExportLogStore 定义了导出审计记录的存储接口
+type KeyExportLogStore interface {
+	// CreateKeyExportLog 写入一条导出审计记录
+	CreateKeyExportLog(ctx context.Context, log *model.KeyExportLog) error
+
+	// CountKeyExportAttempts 统计用户自 since 起的导出尝试次数（被限流拒绝的记录不计入）
+	CountKeyExportAttempts(ctx context.Context, userID uint, since time.Time) (int64, error)
+}
+
+// KeyExportService 定义了 Keystore 导出与助记词查看的业务接口。
+// 每次调用都要求账户登录密码（及已绑定的第二因子），受频率限制，并写入审计记录。
+type KeyExportService interface {
+	// ExportKeystore 导出钱包的 V3 Keystore；ExportPassword 非空时使用其重新加密
+	ExportKeystore(ctx context.Context, params *KeyExportParams) (*KeystoreExport, error)
+
+	// RevealMnemonic 解密并返回钱包所属的助记词
+	RevealMnemonic(ctx context.Context, params *KeyExportParams) (*MnemonicExport, error)
+}
+
+// KeyExportParams 封装一次导出请求的参数
+type KeyExportParams struct {
+	UserID           uint
+	Address          string
+	AccountPassword  string // 账户登录密码，用于重新认证
+	SecondFactorCode string // 用户已绑定第二因子时必填
+
+	WalletPassword string // 钱包密码：查看助记词或重新加密 Keystore 时必填
+	ExportPassword string // 可选，导出 Keystore 使用的新密码
+
+	// 请求来源，仅用于审计
+	ClientIP  string
+	UserAgent string
+}
+
+// KeystoreExport 是 Keystore 导出结果
+type KeystoreExport struct {
+	Address     string `json:"address"`
+	Keystore    string `json:"keystore"`     // V3 Keystore JSON
+	ReEncrypted bool   `json:"re_encrypted"` // 是否已使用 ExportPassword 重新加密
+}
+
+// MnemonicExport 是助记词查看结果
+type MnemonicExport struct {
+	Address        string `json:"address"`
+	Mnemonic       string `json:"mnemonic"`
+	DerivationPath string `json:"derivation_path"`
+}
+
+// keyExportService 实现了 KeyExportService 接口
+type keyExportService struct {
+	userStore    UserStore
+	walletStore  WalletStore
+	logStore     KeyExportLogStore
+	keyManager   crypto.KeyManager
+	seedCipher   crypto.EnvelopeCipher
+	secondFactor SecondFactorVerifier
+
+	maxAttempts         int
+	window              time.Duration
+	requireSecondFactor bool
+}
+
+var _ KeyExportService = (*keyExportService)(nil)
+
+// NewKeyExportService 创建并返回一个新的 KeyExportService 实例
+func NewKeyExportService(
+	userStore UserStore,
+	walletStore WalletStore,
+	logStore KeyExportLogStore,
+	keyManager crypto.KeyManager,
+	seedCipher crypto.EnvelopeCipher,
+	secondFactor SecondFactorVerifier,
+	cfg config.KeyExportConfig,
+) (KeyExportService, error) {
+	window, err := parseDurationOrDefault(cfg.Window, defaultKeyExportWindow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key_export window: %w", err)
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultKeyExportMaxAttempts
+	}
+
+	return &keyExportService{
+		userStore:           userStore,
+		walletStore:         walletStore,
+		logStore:            logStore,
+		keyManager:          keyManager,
+		seedCipher:          seedCipher,
+		secondFactor:        secondFactor,
+		maxAttempts:         maxAttempts,
+		window:              window,
+		requireSecondFactor: cfg.RequireSecondFactor,
+	}, nil
+}
+
+// ExportKeystore implements KeyExportService.
+func (s *keyExportService) ExportKeystore(ctx context.Context, params *KeyExportParams) (*KeystoreExport, error) {
+	var result *KeystoreExport
+
+	err := s.audited(ctx, params, model.KeyExportTypeKeystore, func(wallet *model.Wallet) error {
+		if wallet.SignerType == crypto.SignerTypeRemote {
+			return ErrKeyNotCustodied
+		}
+
+		// 未指定导出密码：原样返回以钱包密码加密的 Keystore
+		if params.ExportPassword == "" {
+			result = &KeystoreExport{Address: wallet.Address, Keystore: wallet.EncryptedKey}
+			return nil
+		}
+
+		privateKeyHex, err := s.keyManager.DecryptKeystore(wallet.EncryptedKey, params.WalletPassword)
+		if err != nil {
+			if errors.Is(err, crypto.ErrInvalidPassword) {
+				return ErrPasswordIncorrect
+			}
+			return fmt.Errorf("failed to decrypt keystore: %w", err)
+		}
+
+		keystoreJSON, err := s.keyManager.EncryptPrivateKey(privateKeyHex, params.ExportPassword)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt keystore: %w", err)
+		}
+
+		result = &KeystoreExport{Address: wallet.Address, Keystore: keystoreJSON, ReEncrypted: true}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RevealMnemonic implements KeyExportService.
+func (s *keyExportService) RevealMnemonic(ctx context.Context, params *KeyExportParams) (*MnemonicExport, error) {
+	var result *MnemonicExport
+
+	err := s.audited(ctx, params, model.KeyExportTypeMnemonic, func(wallet *model.Wallet) error {
+		if wallet.MnemonicID == nil {
+			return ErrMnemonicNotAvailable
+		}
+
+		seed, err := s.walletStore.GetMnemonicSeedByID(ctx, *wallet.MnemonicID)
+		if err != nil {
+			return fmt.Errorf("failed to load mnemonic seed: %w", err)
+		}
+		if seed == nil {
+			return ErrMnemonicNotAvailable
+		}
+
+		encryptedSeed, err := openMnemonicSeed(ctx, s.seedCipher, seed)
+		if err != nil {
+			return err
+		}
+
+		mnemonic, err := s.keyManager.DecryptMnemonic(encryptedSeed, params.WalletPassword)
+		if err != nil {
+			if errors.Is(err, crypto.ErrInvalidPassword) {
+				return ErrPasswordIncorrect
+			}
+			return fmt.Errorf("failed to decrypt mnemonic: %w", err)
+		}
+
+		result = &MnemonicExport{
+			Address:        wallet.Address,
+			Mnemonic:       mnemonic,
+			DerivationPath: wallet.DerivationPath,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// audited 执行导出的公共流程：频率限制 -> 账户密码 -> 第二因子 -> 钱包归属 -> export，
+// 无论成功与否均写入审计记录。成功时审计写入失败会拒绝返回密钥材料。
+func (s *keyExportService) audited(
+	ctx context.Context,
+	params *KeyExportParams,
+	exportType string,
+	export func(wallet *model.Wallet) error,
+) error {
+	entry := &model.KeyExportLog{
+		UserID:        params.UserID,
+		WalletAddress: truncate(params.Address, 42),
+		ExportType:    exportType,
+		ClientIP:      params.ClientIP,
+		UserAgent:     truncate(params.UserAgent, 255),
+	}
+	if common.IsHexAddress(params.Address) {
+		entry.WalletAddress = common.HexToAddress(params.Address).Hex()
+	}
+
+	err := s.authorize(ctx, params, entry.WalletAddress, export)
+	if err == nil {
+		entry.Success = true
+		if logErr := s.logStore.CreateKeyExportLog(ctx, entry); logErr != nil {
+			return fmt.Errorf("failed to record key export: %w", logErr)
+		}
+
+		logger.Logger.Info("Key material exported",
+			zap.Uint("user_id", params.UserID),
+			zap.String("address", entry.WalletAddress),
+			zap.String("type", exportType),
+		)
+		return nil
+	}
+
+	entry.FailureReason = keyExportFailureReason(err)
+	if logErr := s.logStore.CreateKeyExportLog(ctx, entry); logErr != nil {
+		logger.Logger.Error("Failed to record failed key export attempt",
+			zap.Uint("user_id", params.UserID),
+			zap.String("reason", entry.FailureReason),
+			zap.Error(logErr),
+		)
+	}
+
+	logger.Logger.Warn("Key export attempt rejected",
+		zap.Uint("user_id", params.UserID),
+		zap.String("address", entry.WalletAddress),
+		zap.String("type", exportType),
+		zap.String("reason", entry.FailureReason),
+	)
+	return err
+}
+
+// authorize 依次完成频率限制、重新认证与归属校验，通过后执行 export
+func (s *keyExportService) authorize(
+	ctx context.Context,
+	params *KeyExportParams,
+	address string,
+	export func(wallet *model.Wallet) error,
+) error {
+	// 1. 频率限制：窗口期内的尝试次数（含失败）不得超过上限
+	attempts, err := s.logStore.CountKeyExportAttempts(ctx, params.UserID, time.Now().Add(-s.window))
+	if err != nil {
+		return err
+	}
+	if attempts >= int64(s.maxAttempts) {
+		return ErrKeyExportRateLimited
+	}
+
+	// 2. 校验账户登录密码
+	user, err := s.userStore.FindByID(params.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user == nil) {
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to retrieve user: %w", ErrStoreOperationFailed, err)
+	}
+	if user.PasswordHash == "" {
+		return ErrPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(params.AccountPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	// 3. 第二因子：用户已绑定时必须校验；配置要求强制第二因子时未绑定也拒绝
+	err = checkSecondFactor(ctx, s.secondFactor, params.UserID, params.SecondFactorCode, s.requireSecondFactor)
+	if err != nil {
+		return err
+	}
+
+	// 4. 钱包归属校验
+	if !common.IsHexAddress(address) {
+		return ErrInvalidAddress
+	}
+	wallet, err := s.walletStore.GetWalletByAddress(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet: %w", err)
+	}
+	if wallet == nil || wallet.UserID != params.UserID {
+		return ErrWalletNotFound
+	}
+
+	return export(wallet)
+}
+
+// keyExportFailureReason 将错误映射为审计记录中的失败原因
+func keyExportFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrKeyExportRateLimited), errors.Is(err, ErrTooManyAttempts), errors.Is(err, ErrAttemptsLocked):
+		return KeyExportReasonRateLimited
+	case errors.Is(err, ErrInvalidCredentials), errors.Is(err, ErrPasswordNotSet):
+		return KeyExportReasonInvalidCredential
+	case errors.Is(err, ErrSecondFactorRequired), errors.Is(err, ErrSecondFactorInvalid):
+		return KeyExportReasonSecondFactor
+	case errors.Is(err, ErrWalletNotFound), errors.Is(err, ErrInvalidAddress):
+		return KeyExportReasonWalletNotFound
+	case errors.Is(err, ErrMnemonicNotAvailable):
+		return KeyExportReasonNoMnemonic
+	case errors.Is(err, ErrKeyNotCustodied):
+		return KeyExportReasonRemoteSigner
+	case errors.Is(err, ErrPasswordIncorrect):
+		return KeyExportReasonWalletPassword
+	}
+	return KeyExportReasonInternalError
+}
+
+// truncate 按字节截断字符串，避免超出数据库字段长度
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
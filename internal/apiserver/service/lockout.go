@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultBackoffAfter    = 3
+	defaultBackoffBase     = time.Second
+	defaultMaxFailures     = 10
+	defaultLockoutDuration = 15 * time.Minute
+	defaultFailureWindow   = 15 * time.Minute
+
+	// maxAttemptSubjectLength 与 failed_attempts.subject 字段长度一致
+	maxAttemptSubjectLength = 100
+)
+
+var (
+	ErrTooManyAttempts = errors.New("too many failed attempts, retry later")
+	ErrAttemptsLocked  = errors.New("locked after too many failed attempts")
+)
+
+// AttemptLockedError 表示用户名或钱包地址正处于退避或锁定期，errors.Is 可匹配
+// ErrTooManyAttempts（指数退避）或 ErrAttemptsLocked（达到失败上限后的锁定）
+type AttemptLockedError struct {
+	Locked     bool          // true 为锁定，false 为指数退避
+	RetryAfter time.Duration // 距可重试的剩余时间
+}
+
+func (e *AttemptLockedError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", e.Unwrap(), e.RetryAfter.Round(time.Second))
+}
+
+func (e *AttemptLockedError) Unwrap() error {
+	if e.Locked {
+		return ErrAttemptsLocked
+	}
+	return ErrTooManyAttempts
+}
+
+// FailedAttemptStore 定义了失败尝试计数的存储接口
+type FailedAttemptStore interface {
+	// GetFailedAttempt 查找计数记录，不存在时返回 nil, nil
+	GetFailedAttempt(ctx context.Context, kind string, subject string) (*model.FailedAttempt, error)
+
+	// ReserveFailedAttempt 在一条语句中原子地累加失败次数，并按 policy 计算累加后的 locked_until 与 expires_at，
+	// 结果写回 attempt。记录不存在或已过期时从 1 重新计数；当前处于退避或锁定期时不做修改并返回 false。
+	// attempt 需预先填写 Kind、Subject、LastFailedAt（当前时间）及首次失败时的 LockedUntil、ExpiresAt
+	ReserveFailedAttempt(ctx context.Context, attempt *model.FailedAttempt, policy BackoffPolicy) (bool, error)
+
+	// DeleteFailedAttempts 删除计数记录（清除锁定），返回删除的记录数
+	DeleteFailedAttempts(ctx context.Context, kind string, subjects []string) (int64, error)
+
+	// DeleteExpiredFailedAttempts 清理 before 之前过期的记录
+	DeleteExpiredFailedAttempts(ctx context.Context, before time.Time) (int64, error)
+}
+
+// BackoffPolicy 描述连续失败后的等待时长：未达到 BackoffAfter 次时为 0，此后从 BackoffBase 开始每次翻倍
+// （不超过 LockoutDuration），达到 MaxFailures 次时为 LockoutDuration。FailureWindow 为计数的有效期。
+type BackoffPolicy struct {
+	BackoffAfter    int
+	BackoffBase     time.Duration
+	MaxFailures     int
+	LockoutDuration time.Duration
+	FailureWindow   time.Duration
+}
+
+// Delay 返回第 failures 次连续失败后需等待的时长
+func (p BackoffPolicy) Delay(failures int) time.Duration {
+	if failures >= p.MaxFailures {
+		return p.LockoutDuration
+	}
+	if failures < p.BackoffAfter {
+		return 0
+	}
+
+	delay := p.BackoffBase
+	for i := p.BackoffAfter; i < failures && delay < p.LockoutDuration; i++ {
+		delay *= 2
+	}
+	return min(delay, p.LockoutDuration)
+}
+
+// AttemptLimiter 定义了按用户名 / 钱包地址限制密码尝试的接口：
+// 连续失败达到 backoff_after 次后按指数退避拒绝尝试，达到 max_failures 次后锁定 lockout_duration。
+// 每次尝试在校验密码前先按失败计入（Reserve），校验通过后清除（Succeed），
+// 并发请求因此无法在计数写入前绕过退避。
+type AttemptLimiter interface {
+	// Reserve 在校验密码前调用，原子地将本次尝试计为一次失败；
+	// 处于退避或锁定期时不计数并返回 *AttemptLockedError
+	Reserve(ctx context.Context, kind string, subject string) error
+
+	// Succeed 在校验通过后清除计数，写入失败时只记录日志
+	Succeed(ctx context.Context, kind string, subject string)
+
+	// Unlock 清除计数与锁定，返回清除的记录数
+	Unlock(ctx context.Context, kind string, subjects ...string) (int64, error)
+}
+
+// attemptLimiter 实现了 AttemptLimiter 接口，计数保存在数据库中，多实例部署时共享
+type attemptLimiter struct {
+	store  FailedAttemptStore
+	policy BackoffPolicy
+}
+
+var _ AttemptLimiter = (*attemptLimiter)(nil)
+
+// NewAttemptLimiter 创建并返回一个新的 AttemptLimiter 实例
+func NewAttemptLimiter(store FailedAttemptStore, cfg config.LockoutConfig) (AttemptLimiter, error) {
+	backoffBase, err := parseDurationOrDefault(cfg.BackoffBase, defaultBackoffBase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout backoff_base: %w", err)
+	}
+
+	lockoutDuration, err := parseDurationOrDefault(cfg.LockoutDuration, defaultLockoutDuration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout lockout_duration: %w", err)
+	}
+
+	failureWindow, err := parseDurationOrDefault(cfg.FailureWindow, defaultFailureWindow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout failure_window: %w", err)
+	}
+
+	backoffAfter := cfg.BackoffAfter
+	if backoffAfter <= 0 {
+		backoffAfter = defaultBackoffAfter
+	}
+
+	maxFailures := cfg.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultMaxFailures
+	}
+	if maxFailures < backoffAfter {
+		return nil, fmt.Errorf("lockout max_failures (%d) must not be less than backoff_after (%d)", maxFailures, backoffAfter)
+	}
+
+	return &attemptLimiter{
+		store: store,
+		policy: BackoffPolicy{
+			BackoffAfter:    backoffAfter,
+			BackoffBase:     backoffBase,
+			MaxFailures:     maxFailures,
+			LockoutDuration: lockoutDuration,
+			FailureWindow:   failureWindow,
+		},
+	}, nil
+}
+
+// Reserve implements AttemptLimiter.
+func (l *attemptLimiter) Reserve(ctx context.Context, kind string, subject string) error {
+	now := time.Now()
+	subject = truncate(subject, maxAttemptSubjectLength)
+
+	// 首次失败时写入的值；已有记录时由存储层在同一语句中按 policy 计算
+	attempt := &model.FailedAttempt{
+		Kind:         kind,
+		Subject:      subject,
+		Failures:     1,
+		LastFailedAt: now,
+		ExpiresAt:    now.Add(l.policy.FailureWindow),
+	}
+	if delay := l.policy.Delay(1); delay > 0 {
+		lockedUntil := now.Add(delay)
+		attempt.LockedUntil = &lockedUntil
+		attempt.ExpiresAt = later(attempt.ExpiresAt, lockedUntil)
+	}
+
+	reserved, err := l.store.ReserveFailedAttempt(ctx, attempt, l.policy)
+	if err != nil {
+		return err
+	}
+
+	if !reserved {
+		current, err := l.store.GetFailedAttempt(ctx, kind, subject)
+		if err != nil {
+			return err
+		}
+		// 读取前锁定恰好到期或记录已被清除时仍拒绝本次尝试，客户端稍后重试即可
+		lockedErr := &AttemptLockedError{RetryAfter: time.Second}
+		if current != nil && current.LockedUntil != nil {
+			lockedErr.Locked = current.Failures >= l.policy.MaxFailures
+			lockedErr.RetryAfter = max(current.LockedUntil.Sub(now), time.Second)
+		}
+		return lockedErr
+	}
+
+	if attempt.Failures >= l.policy.MaxFailures && attempt.LockedUntil != nil {
+		logger.Logger.Warn("Locked after too many failed attempts",
+			zap.String("kind", kind),
+			zap.String("subject", subject),
+			zap.Int("failures", attempt.Failures),
+			zap.Time("locked_until", *attempt.LockedUntil),
+		)
+	}
+
+	return nil
+}
+
+// Succeed implements AttemptLimiter.
+func (l *attemptLimiter) Succeed(ctx context.Context, kind string, subject string) {
+	if _, err := l.store.DeleteFailedAttempts(ctx, kind, []string{truncate(subject, maxAttemptSubjectLength)}); err != nil {
+		logger.Logger.Warn("Failed to reset failed attempts", zap.String("kind", kind), zap.Error(err))
+	}
+}
+
+// Unlock implements AttemptLimiter.
+func (l *attemptLimiter) Unlock(ctx context.Context, kind string, subjects ...string) (int64, error) {
+	if len(subjects) == 0 {
+		return 0, nil
+	}
+
+	truncated := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		truncated = append(truncated, truncate(subject, maxAttemptSubjectLength))
+	}
+
+	return l.store.DeleteFailedAttempts(ctx, kind, truncated)
+}
+
+// userAttemptSubject 返回按用户计数的失败尝试 Subject（助记词解密、第二因子验证码）
+func userAttemptSubject(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
+
+// later 返回 a、b 中较晚的时间
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// memoryAttemptStore 以互斥锁模拟 ReserveFailedAttempt 单条语句的原子性
+type memoryAttemptStore struct {
+	mu      sync.Mutex
+	records map[string]model.FailedAttempt
+}
+
+func newMemoryAttemptStore() *memoryAttemptStore {
+	return &memoryAttemptStore{records: make(map[string]model.FailedAttempt)}
+}
+
+func (m *memoryAttemptStore) GetFailedAttempt(_ context.Context, kind string, subject string) (*model.FailedAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[kind+"/"+subject]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *memoryAttemptStore) ReserveFailedAttempt(_ context.Context, attempt *model.FailedAttempt, policy BackoffPolicy) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := attempt.Kind + "/" + attempt.Subject
+	current, ok := m.records[key]
+	if ok && current.LockedUntil != nil && current.LockedUntil.After(attempt.LastFailedAt) {
+		return false, nil
+	}
+
+	if ok && current.ExpiresAt.After(attempt.LastFailedAt) {
+		attempt.Failures = current.Failures + 1
+		attempt.LockedUntil = nil
+		attempt.ExpiresAt = later(current.ExpiresAt, attempt.ExpiresAt)
+		if delay := policy.Delay(attempt.Failures); delay > 0 {
+			lockedUntil := attempt.LastFailedAt.Add(delay)
+			attempt.LockedUntil = &lockedUntil
+			attempt.ExpiresAt = later(attempt.ExpiresAt, lockedUntil)
+		}
+	}
+
+	m.records[key] = *attempt
+	return true, nil
+}
+
+func (m *memoryAttemptStore) DeleteFailedAttempts(_ context.Context, kind string, subjects []string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for _, subject := range subjects {
+		if _, ok := m.records[kind+"/"+subject]; ok {
+			delete(m.records, kind+"/"+subject)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *memoryAttemptStore) DeleteExpiredFailedAttempts(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestBackoffPolicyDelay(t *testing.T) {
+	policy := BackoffPolicy{BackoffAfter: 3, BackoffBase: time.Second, MaxFailures: 10, LockoutDuration: 15 * time.Minute}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{9, 64 * time.Second},
+		{10, 15 * time.Minute},
+		{100, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := policy.Delay(tt.failures); got != tt.want {
+			t.Errorf("Delay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+
+	capped := BackoffPolicy{BackoffAfter: 1, BackoffBase: time.Minute, MaxFailures: 20, LockoutDuration: 5 * time.Minute}
+	if got := capped.Delay(19); got != 5*time.Minute {
+		t.Errorf("capped Delay(19) = %s, want 5m", got)
+	}
+}
+
+func TestReserveConcurrentBurst(t *testing.T) {
+	store := newMemoryAttemptStore()
+	limiter, err := NewAttemptLimiter(store, config.LockoutConfig{})
+	if err != nil {
+		t.Fatalf("NewAttemptLimiter: %v", err)
+	}
+
+	// 并发请求全部在校验密码前计数，第 backoff_after 次后其余请求均被退避拒绝
+	const burst = 50
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		allowed  int
+		rejected int
+	)
+	for range burst {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := limiter.Reserve(context.Background(), model.AttemptKindLogin, "alice")
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				allowed++
+			case errors.Is(err, ErrTooManyAttempts):
+				rejected++
+			default:
+				t.Errorf("Reserve: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != defaultBackoffAfter || rejected != burst-defaultBackoffAfter {
+		t.Errorf("allowed/rejected = %d/%d, want %d/%d", allowed, rejected, defaultBackoffAfter, burst-defaultBackoffAfter)
+	}
+}
+
+func TestReserveLockAndSucceed(t *testing.T) {
+	store := newMemoryAttemptStore()
+	limiter, err := NewAttemptLimiter(store, config.LockoutConfig{BackoffAfter: 2, MaxFailures: 2, LockoutDuration: "1m"})
+	if err != nil {
+		t.Fatalf("NewAttemptLimiter: %v", err)
+	}
+	ctx := context.Background()
+
+	// 校验通过后清除计数，下一次从 1 开始
+	if err := limiter.Reserve(ctx, model.AttemptKindWallet, "0xabc"); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	limiter.Succeed(ctx, model.AttemptKindWallet, "0xabc")
+
+	for i := range 2 {
+		if err := limiter.Reserve(ctx, model.AttemptKindWallet, "0xabc"); err != nil {
+			t.Fatalf("Reserve %d: %v", i+1, err)
+		}
+	}
+
+	err = limiter.Reserve(ctx, model.AttemptKindWallet, "0xabc")
+	var locked *AttemptLockedError
+	if !errors.As(err, &locked) || !locked.Locked || !errors.Is(err, ErrAttemptsLocked) {
+		t.Fatalf("Reserve after max failures = %v, want locked", err)
+	}
+	if locked.RetryAfter <= 0 || locked.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %s", locked.RetryAfter)
+	}
+
+	if n, err := limiter.Unlock(ctx, model.AttemptKindWallet, "0xabc"); err != nil || n != 1 {
+		t.Fatalf("Unlock = %d, %v", n, err)
+	}
+	if err := limiter.Reserve(ctx, model.AttemptKindWallet, "0xabc"); err != nil {
+		t.Errorf("Reserve after unlock: %v", err)
+	}
+}
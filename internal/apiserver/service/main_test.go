@@ -0,0 +1,18 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	// 业务代码直接使用全局 Logger，测试中替换为不输出的实例
+	logger.Logger = zap.NewNop()
+	logger.SLogger = logger.Logger.Sugar()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// 可由管理接口触发的维护任务名称
+const (
+	JobPurgeExpired    = "purge-expired"     // 清理过期的令牌吊销记录、SIWE nonce、会话、签名请求 nonce、失败计数与密码重置令牌
+	JobPollReceipts    = "poll-receipts"     // 立即执行一轮交易回执追踪
+	JobRotateMasterKey = "rotate-master-key" // 将信封加密的数据密钥重新包装到当前主密钥下
+)
+
+// PurgeExpiredResult 是清理任务删除的记录数
+type PurgeExpiredResult struct {
+	RevokedTokens  int64 `json:"revoked_tokens"`
+	SIWENonces     int64 `json:"siwe_nonces"`
+	Sessions       int64 `json:"sessions"`
+	RequestNonces  int64 `json:"request_nonces"`
+	FailedAttempts int64 `json:"failed_attempts"`
+	PasswordResets int64 `json:"password_resets"`
+}
+
+// NewPurgeExpiredJob 返回清理过期记录的维护任务
+func NewPurgeExpiredJob(
+	revokeStore TokenRevocationStore,
+	nonceStore SIWENonceStore,
+	sessionStore SessionStore,
+	apiKeyStore APIKeyStore,
+	attemptStore FailedAttemptStore,
+	resetStore PasswordResetStore,
+) MaintenanceJob {
+	return func(ctx context.Context) (any, error) {
+		now := time.Now()
+		result := &PurgeExpiredResult{}
+
+		var err error
+		if result.RevokedTokens, err = revokeStore.DeleteExpiredRevokedTokens(ctx, now); err != nil {
+			return result, err
+		}
+		if result.SIWENonces, err = nonceStore.DeleteExpiredSIWENonces(ctx, now); err != nil {
+			return result, err
+		}
+		if result.Sessions, err = sessionStore.DeleteExpiredSessions(ctx, now); err != nil {
+			return result, err
+		}
+		if result.RequestNonces, err = apiKeyStore.DeleteExpiredRequestNonces(ctx, now); err != nil {
+			return result, err
+		}
+		if result.FailedAttempts, err = attemptStore.DeleteExpiredFailedAttempts(ctx, now); err != nil {
+			return result, err
+		}
+		if result.PasswordResets, err = resetStore.DeleteExpiredPasswordResetTokens(ctx, now); err != nil {
+			return result, err
+		}
+
+		return result, nil
+	}
+}
+
+// NewPollReceiptsJob 返回执行一轮回执追踪的维护任务，未启用后台追踪时可用于手动更新交易状态
+func NewPollReceiptsJob(tracker *ReceiptTracker) MaintenanceJob {
+	return func(ctx context.Context) (any, error) {
+		tracker.Poll(ctx)
+		return nil, ctx.Err()
+	}
+}
+
+// NewRotateMasterKeyJob 返回主密钥轮换的维护任务，与 rotate-master-key 命令效果相同
+func NewRotateMasterKeyJob(rotator *MasterKeyRotator) MaintenanceJob {
+	return func(ctx context.Context) (any, error) {
+		return rotator.Rotate(ctx, false)
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const defaultRotationBatchSize = 100
+
+// MasterKeyRotationResult 汇总一次主密钥轮换的结果
+type MasterKeyRotationResult struct {
+	ActiveKeyID string `json:"active_key_id"` // 轮换目标主密钥
+	Scanned     int    `json:"scanned"`       // 扫描的记录数（助记词、TOTP 密钥与 API Key 签名密钥）
+	Rewrapped   int    `json:"rewrapped"`     // 由旧主密钥重新包装的记录数
+	Sealed      int    `json:"sealed"`        // 首次做信封加密的历史记录数
+	Skipped     int    `json:"skipped"`       // 已使用目标主密钥或被并发修改而跳过的记录数
+}
+
+// MasterKeyRotator 将全部助记词、TOTP 密钥与 API Key 签名密钥记录的数据密钥重新包装到当前主密钥下。
+// 只替换被包装的数据密钥（历史记录则对其现有密文做一次信封加密），
+// 全程不接触钱包密码，也无法得到助记词明文。
+type MasterKeyRotator struct {
+	store       WalletStore
+	totpStore   TOTPStore
+	apiKeyStore APIKeyStore
+	cipher      crypto.EnvelopeCipher
+	batchSize   int
+}
+
+// NewMasterKeyRotator 创建主密钥轮换器，batchSize <= 0 时使用默认值
+func NewMasterKeyRotator(
+	store WalletStore,
+	totpStore TOTPStore,
+	apiKeyStore APIKeyStore,
+	cipher crypto.EnvelopeCipher,
+	batchSize int,
+) *MasterKeyRotator {
+	if batchSize <= 0 {
+		batchSize = defaultRotationBatchSize
+	}
+
+	return &MasterKeyRotator{
+		store:       store,
+		totpStore:   totpStore,
+		apiKeyStore: apiKeyStore,
+		cipher:      cipher,
+		batchSize:   batchSize,
+	}
+}
+
+// Rotate 按 ID 顺序分批处理全部记录；dryRun 为 true 时只统计不写入。
+// 每条记录以原 master_key_id 为条件更新，可安全重复执行或与线上服务并发运行。
+func (r *MasterKeyRotator) Rotate(ctx context.Context, dryRun bool) (*MasterKeyRotationResult, error) {
+	result := &MasterKeyRotationResult{ActiveKeyID: r.cipher.ActiveKeyID()}
+
+	if err := r.rotateSeeds(ctx, dryRun, result); err != nil {
+		return result, err
+	}
+	if err := r.rotateTOTPs(ctx, dryRun, result); err != nil {
+		return result, err
+	}
+	if err := r.rotateAPIKeys(ctx, dryRun, result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// rotateSeeds 处理全部助记词记录
+func (r *MasterKeyRotator) rotateSeeds(ctx context.Context, dryRun bool, result *MasterKeyRotationResult) error {
+	var afterID uint
+	for {
+		seeds, err := r.store.ListMnemonicSeeds(ctx, afterID, r.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list mnemonic seeds after %d: %w", afterID, err)
+		}
+		if len(seeds) == 0 {
+			return nil
+		}
+
+		for _, seed := range seeds {
+			afterID = seed.ID
+
+			err := r.rotateEnvelope(ctx, dryRun, result, seedEnvelope(seed), func(envelope *crypto.Envelope, previousKeyID string) (bool, error) {
+				seed.EncryptedSeed = envelope.Ciphertext
+				seed.WrappedDataKey = envelope.WrappedKey
+				seed.MasterKeyID = envelope.KeyID
+				return r.store.UpdateMnemonicSeedEnvelope(ctx, seed, previousKeyID)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to rotate mnemonic seed %d: %w", seed.ID, err)
+			}
+		}
+
+		logger.Logger.Info("Master key rotation progress",
+			zap.String("table", "mnemonic_seeds"),
+			zap.Uint("last_id", afterID),
+			zap.Int("scanned", result.Scanned),
+			zap.Int("rewrapped", result.Rewrapped),
+			zap.Int("sealed", result.Sealed),
+		)
+	}
+}
+
+// rotateTOTPs 处理全部 TOTP 密钥记录
+func (r *MasterKeyRotator) rotateTOTPs(ctx context.Context, dryRun bool, result *MasterKeyRotationResult) error {
+	var afterID uint
+	for {
+		records, err := r.totpStore.ListTOTPs(ctx, afterID, r.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list totps after %d: %w", afterID, err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		for _, record := range records {
+			afterID = record.ID
+
+			err := r.rotateEnvelope(ctx, dryRun, result, totpEnvelope(record), func(envelope *crypto.Envelope, previousKeyID string) (bool, error) {
+				record.Secret = envelope.Ciphertext
+				record.WrappedDataKey = envelope.WrappedKey
+				record.MasterKeyID = envelope.KeyID
+				return r.totpStore.UpdateTOTPEnvelope(ctx, record, previousKeyID)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to rotate totp %d: %w", record.ID, err)
+			}
+		}
+
+		logger.Logger.Info("Master key rotation progress",
+			zap.String("table", "user_totps"),
+			zap.Uint("last_id", afterID),
+			zap.Int("scanned", result.Scanned),
+			zap.Int("rewrapped", result.Rewrapped),
+			zap.Int("sealed", result.Sealed),
+		)
+	}
+}
+
+// rotateAPIKeys 处理全部 API Key 签名密钥记录
+func (r *MasterKeyRotator) rotateAPIKeys(ctx context.Context, dryRun bool, result *MasterKeyRotationResult) error {
+	var afterID uint
+	for {
+		keys, err := r.apiKeyStore.ListAPIKeySigningSecrets(ctx, afterID, r.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list api keys after %d: %w", afterID, err)
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		for _, key := range keys {
+			afterID = key.ID
+
+			err := r.rotateEnvelope(ctx, dryRun, result, apiKeyEnvelope(key), func(envelope *crypto.Envelope, previousKeyID string) (bool, error) {
+				key.SigningSecret = envelope.Ciphertext
+				key.WrappedDataKey = envelope.WrappedKey
+				key.MasterKeyID = envelope.KeyID
+				return r.apiKeyStore.UpdateAPIKeyEnvelope(ctx, key, previousKeyID)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to rotate api key %d: %w", key.ID, err)
+			}
+		}
+
+		logger.Logger.Info("Master key rotation progress",
+			zap.String("table", "api_keys"),
+			zap.Uint("last_id", afterID),
+			zap.Int("scanned", result.Scanned),
+			zap.Int("rewrapped", result.Rewrapped),
+			zap.Int("sealed", result.Sealed),
+		)
+	}
+}
+
+// rotateEnvelope 将单条记录的信封包装到当前主密钥下，并通过 update 以原主密钥 ID 为条件写回。
+// 未做信封加密的历史记录（KeyID 为空）对其现有内容做一次信封加密。
+func (r *MasterKeyRotator) rotateEnvelope(
+	ctx context.Context,
+	dryRun bool,
+	result *MasterKeyRotationResult,
+	current *crypto.Envelope,
+	update func(envelope *crypto.Envelope, previousKeyID string) (bool, error),
+) error {
+	result.Scanned++
+
+	previousKeyID := current.KeyID
+	if previousKeyID == result.ActiveKeyID {
+		result.Skipped++
+		return nil
+	}
+
+	if dryRun {
+		if previousKeyID == "" {
+			result.Sealed++
+		} else {
+			result.Rewrapped++
+		}
+		return nil
+	}
+
+	var (
+		envelope *crypto.Envelope
+		err      error
+	)
+	if previousKeyID == "" {
+		envelope, err = r.cipher.Seal(ctx, []byte(current.Ciphertext))
+	} else {
+		envelope, err = r.cipher.Rewrap(ctx, current)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to re-wrap: %w", err)
+	}
+
+	updated, err := update(envelope, previousKeyID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !updated:
+		result.Skipped++
+	case previousKeyID == "":
+		result.Sealed++
+	default:
+		result.Rewrapped++
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+var (
+	ErrInvalidMessageType = errors.New("unsupported message type")
+	ErrInvalidTypedData   = errors.New("invalid EIP-712 typed data")
+	ErrInvalidSignature   = errors.New("invalid signature")
+)
+
+// SignMessageParams 封装一次消息签名请求的参数
+type SignMessageParams struct {
+	UserID   uint
+	Address  string
+	Password string // 钱包密码，远程签名的钱包无需填写
+	Type     string // personal / typed_data，见 crypto.MessageType*
+
+	Message   []byte              // Type = personal：待签名的原始消息
+	TypedData *apitypes.TypedData // Type = typed_data：EIP-712 结构化数据
+
+	SecondFactorCode string // 用户已绑定第二因子时必填
+}
+
+// VerifyMessageParams 封装一次签名校验请求的参数
+type VerifyMessageParams struct {
+	Type      string
+	Message   []byte
+	TypedData *apitypes.TypedData
+	Signature string // 0x 前缀的 65 字节十六进制签名
+	Address   string // 可选，期望的签名者地址
+}
+
+// MessageSignature 是消息签名结果，V 为 27 / 28
+type MessageSignature struct {
+	Address   string `json:"address"`
+	Type      string `json:"type"`
+	Hash      string `json:"hash"`      // 实际被签名的 32 字节哈希
+	Signature string `json:"signature"` // [R || S || V] 拼接的 65 字节十六进制
+	R         string `json:"r"`
+	S         string `json:"s"`
+	V         uint8  `json:"v"`
+}
+
+// MessageVerification 是签名校验结果
+type MessageVerification struct {
+	Signer  string `json:"signer"`            // 从签名中恢复的地址
+	Hash    string `json:"hash"`              // 签名对应的 32 字节哈希
+	Matches *bool  `json:"matches,omitempty"` // 指定了期望地址时，恢复的地址是否与之一致
+}
+
+// SignMessage implements WalletService.
+// 流程：归属校验 -> 计算 EIP-191 / EIP-712 哈希 -> 第二因子 -> 获取签名者并签名 -> 以 V = 27/28 返回。
+func (s *walletService) SignMessage(ctx context.Context, params *SignMessageParams) (*MessageSignature, error) {
+	// 1. 归属校验
+	if !common.IsHexAddress(params.Address) {
+		return nil, ErrWalletNotFound
+	}
+	from := common.HexToAddress(params.Address)
+
+	wallet, err := s.store.GetWalletByAddress(ctx, from.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+	if wallet == nil || wallet.UserID != params.UserID {
+		return nil, ErrWalletNotFound
+	}
+
+	// 2. 计算待签名哈希，先于解锁 Keystore 校验输入
+	hash, err := messageHash(params.Type, params.Message, params.TypedData)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 第二因子：EIP-2612 permit / Permit2 等签名可直接转走代币，与转账同等要求，先于钱包密码校验
+	if err := checkSecondFactor(ctx, s.secondFactor, params.UserID, params.SecondFactorCode, false); err != nil {
+		return nil, err
+	}
+
+	// 4. 获取签名者并签名
+	signer, err := s.signerFor(ctx, wallet, params.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := signer.SignHash(ctx, from, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	// 5. 与 personal_sign / eth_signTypedData_v4 保持一致，V 使用 27 / 28
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+
+	logger.Logger.Info("Message signed",
+		zap.Uint("user_id", params.UserID),
+		zap.String("address", from.Hex()),
+		zap.String("type", params.Type),
+	)
+
+	return &MessageSignature{
+		Address:   from.Hex(),
+		Type:      params.Type,
+		Hash:      hexutil.Encode(hash),
+		Signature: hexutil.Encode(sig),
+		R:         hexutil.Encode(sig[:32]),
+		S:         hexutil.Encode(sig[32:64]),
+		V:         sig[64],
+	}, nil
+}
+
+// VerifyMessage implements WalletService.
+func (s *walletService) VerifyMessage(_ context.Context, params *VerifyMessageParams) (*MessageVerification, error) {
+	hash, err := messageHash(params.Type, params.Message, params.TypedData)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := hexutil.Decode(params.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	signer, err := crypto.RecoverAddress(hash, sig)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	result := &MessageVerification{
+		Signer: signer.Hex(),
+		Hash:   hexutil.Encode(hash),
+	}
+
+	if params.Address != "" {
+		if !common.IsHexAddress(params.Address) {
+			return nil, ErrInvalidAddress
+		}
+		matches := common.HexToAddress(params.Address) == signer
+		result.Matches = &matches
+	}
+
+	return result, nil
+}
+
+// messageHash 按消息类型计算待签名的 32 字节哈希
+func messageHash(messageType string, message []byte, typedData *apitypes.TypedData) ([]byte, error) {
+	switch messageType {
+	case crypto.MessageTypePersonal:
+		return crypto.PersonalMessageHash(message), nil
+
+	case crypto.MessageTypeTypedData:
+		hash, err := crypto.TypedDataHash(typedData)
+		if err != nil {
+			if errors.Is(err, crypto.ErrInvalidTypedData) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidTypedData, err)
+			}
+			return nil, err
+		}
+		return hash, nil
+
+	default:
+		return nil, ErrInvalidMessageType
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+// NonceStore 定义了持久化 nonce 分配器的接口 (DIP: 由 service 层定义，由 store 层基于 Postgres 行锁实现)
+// 同一 (chainID, address) 的并发发送通过 ReserveNonce 串行化，避免重复使用 PendingNonceAt 返回的 nonce。
+type NonceStore interface {
+	// ReserveNonce 为地址分配一个 nonce；chainPendingNonce 为链上 pending nonce，用于检测偏差与空洞
+	ReserveNonce(ctx context.Context, chainID uint, address string, chainPendingNonce uint64) (uint64, error)
+
+	// ReleaseNonce 释放一个未成功广播的 nonce，使其可被复用
+	ReleaseNonce(ctx context.Context, chainID uint, address string, nonce uint64) error
+
+	// MarkNonceBroadcast 将 nonce 标记为已广播
+	MarkNonceBroadcast(ctx context.Context, chainID uint, address string, nonce uint64) error
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultPasswordResetTTL         = 30 * time.Minute
+	defaultPasswordResetMaxRequests = 3
+
+	// passwordResetTokenBytes 是重置令牌的随机字节数 (256 bit)
+	passwordResetTokenBytes = 32
+
+	// PasswordResetDeliveryLog 将重置令牌写入服务日志，仅用于开发环境，生产环境拒绝启动
+	PasswordResetDeliveryLog = "log"
+
+	// productionEnvironment 对应 server.environment 的生产环境取值
+	productionEnvironment = "production"
+)
+
+var (
+	ErrPasswordUnchanged         = errors.New("new password must differ from the current password")
+	ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid, expired or already used")
+	ErrPasswordResetDisabled     = errors.New("password reset delivery is not configured")
+)
+
+// PasswordResetStore 定义了找回密码令牌的存储接口
+type PasswordResetStore interface {
+	// CreatePasswordResetToken 保存新令牌，并在同一事务中作废该用户此前未使用的令牌
+	CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error
+
+	// CountPasswordResetTokensSince 统计用户自 since 起申请的令牌数量
+	CountPasswordResetTokensSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+
+	// ResetPassword 在同一事务中消费令牌、更新密码哈希并作废该用户的其他令牌，
+	// 令牌不存在、已使用或已过期时返回 false
+	ResetPassword(ctx context.Context, tokenHash string, passwordHash string, now time.Time) (uint, bool, error)
+
+	// DeleteExpiredPasswordResetTokens 清理 before 之前过期的令牌
+	DeleteExpiredPasswordResetTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PasswordResetNotifier 定义了重置令牌的投递渠道（如邮件、短信）
+type PasswordResetNotifier interface {
+	// SendPasswordReset 将重置令牌发送给用户
+	SendPasswordReset(ctx context.Context, user *model.User, token string, expiresAt time.Time) error
+}
+
+// logPasswordResetNotifier 将重置令牌写入服务日志，是开发环境中邮件等渠道的替代实现
+type logPasswordResetNotifier struct{}
+
+var _ PasswordResetNotifier = logPasswordResetNotifier{}
+
+// NewLogPasswordResetNotifier 返回将重置令牌写入日志的 PasswordResetNotifier，不得用于生产环境
+func NewLogPasswordResetNotifier() PasswordResetNotifier {
+	return logPasswordResetNotifier{}
+}
+
+// SendPasswordReset implements PasswordResetNotifier.
+func (logPasswordResetNotifier) SendPasswordReset(_ context.Context, user *model.User, token string, expiresAt time.Time) error {
+	logger.Logger.Warn("Password reset token issued (log delivery, development only)",
+		zap.Uint("user_id", user.ID),
+		zap.String("username", user.Username),
+		zap.String("token", token),
+		zap.Time("expires_at", expiresAt),
+	)
+	return nil
+}
+
+// NewPasswordResetNotifier 按 password_reset.delivery 配置返回投递渠道，为空时返回 nil（不启用找回密码）。
+// log 渠道会将可直接接管账户的令牌写入日志，server.environment 为 production 时拒绝使用。
+func NewPasswordResetNotifier(cfg config.PasswordResetConfig, environment string) (PasswordResetNotifier, error) {
+	switch cfg.Delivery {
+	case "":
+		return nil, nil
+	case PasswordResetDeliveryLog:
+		if environment == productionEnvironment {
+			return nil, errors.New("password_reset delivery \"log\" writes reset tokens to service logs and is not allowed in production")
+		}
+		return NewLogPasswordResetNotifier(), nil
+	default:
+		return nil, fmt.Errorf("unsupported password_reset delivery: %q", cfg.Delivery)
+	}
+}
+
+// PasswordService 定义了账户密码修改与找回的业务接口。
+// 账户密码与钱包密码相互独立：修改或重置账户密码不影响 Keystore，钱包密码见 WalletService.ChangeWalletPassword。
+type PasswordService interface {
+	// ChangePassword 校验原密码（及已绑定的第二因子）后修改账户密码，并吊销该用户已签发的全部令牌
+	ChangePassword(ctx context.Context, params *ChangePasswordParams) error
+
+	// RequestPasswordReset 为用户签发重置令牌并通过 PasswordResetNotifier 投递，未配置投递渠道时返回 ErrPasswordResetDisabled。
+	// 为避免泄露用户是否存在，用户不存在、已停用或申请过于频繁时同样返回 nil
+	RequestPasswordReset(ctx context.Context, username string) error
+
+	// ResetPassword 使用重置令牌设置新密码，并吊销该用户已签发的全部令牌、解除登录锁定
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+}
+
+// ChangePasswordParams 封装修改账户密码的参数
+type ChangePasswordParams struct {
+	UserID           uint
+	OldPassword      string
+	NewPassword      string
+	SecondFactorCode string // 用户已绑定第二因子时必填
+}
+
+// passwordService 实现了 PasswordService 接口
+type passwordService struct {
+	userStore         UserStore
+	resetStore        PasswordResetStore
+	notifier          PasswordResetNotifier
+	secondFactor      SecondFactorVerifier
+	revocationService TokenRevocationService
+	limiter           AttemptLimiter
+
+	resetTTL         time.Duration
+	resetMaxRequests int
+}
+
+var _ PasswordService = (*passwordService)(nil)
+
+// NewPasswordService 创建并返回一个新的 PasswordService 实例，notifier 为 nil 时不启用找回密码
+func NewPasswordService(
+	userStore UserStore,
+	resetStore PasswordResetStore,
+	notifier PasswordResetNotifier,
+	secondFactor SecondFactorVerifier,
+	revocationService TokenRevocationService,
+	limiter AttemptLimiter,
+	cfg config.PasswordResetConfig,
+) (PasswordService, error) {
+	resetTTL, err := parseDurationOrDefault(cfg.TokenTTL, defaultPasswordResetTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password_reset token_ttl: %w", err)
+	}
+
+	maxRequests := cfg.MaxRequests
+	if maxRequests <= 0 {
+		maxRequests = defaultPasswordResetMaxRequests
+	}
+
+	return &passwordService{
+		userStore:         userStore,
+		resetStore:        resetStore,
+		notifier:          notifier,
+		secondFactor:      secondFactor,
+		revocationService: revocationService,
+		limiter:           limiter,
+		resetTTL:          resetTTL,
+		resetMaxRequests:  maxRequests,
+	}, nil
+}
+
+// ChangePassword implements PasswordService.
+// 原密码错误与登录失败共用同一用户名的失败计数，防止借此接口暴力破解。
+func (s *passwordService) ChangePassword(ctx context.Context, params *ChangePasswordParams) error {
+	user, err := s.userStore.FindByID(params.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+
+	if user.PasswordHash == "" {
+		return ErrPasswordNotSet
+	}
+
+	if err := s.limiter.Reserve(ctx, model.AttemptKindLogin, user.Username); err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(params.OldPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+	s.limiter.Succeed(ctx, model.AttemptKindLogin, user.Username)
+
+	if err := checkSecondFactor(ctx, s.secondFactor, user.ID, params.SecondFactorCode, false); err != nil {
+		return err
+	}
+
+	if params.NewPassword == params.OldPassword {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(params.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPasswordHashFailed, err)
+	}
+
+	updated, err := s.userStore.UpdatePasswordHash(ctx, user.ID, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("%w: failed to update password: %w", ErrStoreOperationFailed, err)
+	}
+	if !updated {
+		return ErrUserNotFound
+	}
+
+	// 其他设备上的会话可能由泄露的旧密码建立，全部失效
+	if _, err := s.revocationService.RevokeUserTokens(ctx, user.ID, time.Time{}); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	logger.Logger.Info("User password changed", zap.Uint("user_id", user.ID))
+
+	return nil
+}
+
+// RequestPasswordReset implements PasswordService.
+func (s *passwordService) RequestPasswordReset(ctx context.Context, username string) error {
+	if s.notifier == nil {
+		return ErrPasswordResetDisabled
+	}
+
+	user, err := s.userStore.FindByUsername(username)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user == nil) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to retrieve user: %w", ErrStoreOperationFailed, err)
+	}
+	if user.DisabledAt != nil {
+		logger.Logger.Warn("Password reset requested for disabled user", zap.Uint("user_id", user.ID))
+		return nil
+	}
+
+	now := time.Now()
+	count, err := s.resetStore.CountPasswordResetTokensSince(ctx, user.ID, now.Add(-s.resetTTL))
+	if err != nil {
+		return err
+	}
+	if count >= int64(s.resetMaxRequests) {
+		logger.Logger.Warn("Password reset requests exceed limit", zap.Uint("user_id", user.ID))
+		return nil
+	}
+
+	token, tokenHash, err := newPasswordResetToken()
+	if err != nil {
+		return err
+	}
+
+	record := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(s.resetTTL),
+	}
+	if err := s.resetStore.CreatePasswordResetToken(ctx, record); err != nil {
+		return err
+	}
+
+	if err := s.notifier.SendPasswordReset(ctx, user, token, record.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to deliver password reset token: %w", err)
+	}
+
+	logger.Logger.Info("Password reset token issued", zap.Uint("user_id", user.ID))
+
+	return nil
+}
+
+// ResetPassword implements PasswordService.
+func (s *passwordService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	if token == "" {
+		return ErrPasswordResetTokenInvalid
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPasswordHashFailed, err)
+	}
+
+	userID, ok, err := s.resetStore.ResetPassword(ctx, hashPasswordResetToken(token), string(hashedPassword), time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPasswordResetTokenInvalid
+	}
+
+	if _, err := s.revocationService.RevokeUserTokens(ctx, userID, time.Time{}); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	// 持有重置令牌即证明了对账户的控制，解除此前因密码错误导致的锁定
+	user, err := s.userStore.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+	if _, err := s.limiter.Unlock(ctx, model.AttemptKindLogin, user.Username); err != nil {
+		logger.Logger.Warn("Failed to clear login lockout after password reset", zap.Uint("user_id", userID), zap.Error(err))
+	}
+
+	logger.Logger.Info("User password reset", zap.Uint("user_id", userID))
+
+	return nil
+}
+
+// newPasswordResetToken 生成重置令牌及其哈希
+func newPasswordResetToken() (string, string, error) {
+	buf := make([]byte, passwordResetTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashPasswordResetToken(token), nil
+}
+
+// hashPasswordResetToken 计算重置令牌的 SHA-256，数据库只保存哈希
+func hashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
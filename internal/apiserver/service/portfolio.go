@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/conversion"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// Portfolio 描述一个地址在所有已配置链上的资产
+type Portfolio struct {
+	Address string           `json:"address"`
+	Chains  []ChainPortfolio `json:"chains"`
+}
+
+// ChainPortfolio 描述一个地址在单条链上的资产，Error 非空表示该链查询失败
+type ChainPortfolio struct {
+	ChainID  uint      `json:"chain_id"`
+	Name     string    `json:"name"`
+	Balances []Balance `json:"balances,omitempty"` // 第一项为原生币，其余为已注册代币
+	Error    string    `json:"error,omitempty"`
+}
+
+// GetPortfolio implements WalletService.
+// 对 ClientManager 中的每条链并发查询，单条链失败时在对应条目中返回错误而不影响其他链。
+func (s *walletService) GetPortfolio(ctx context.Context, address string) (*Portfolio, error) {
+	if !common.IsHexAddress(address) {
+		return nil, ErrInvalidAddress
+	}
+	owner := common.HexToAddress(address)
+
+	chainIDs := s.clientManager.ChainIDs()
+	chains := make([]ChainPortfolio, len(chainIDs))
+
+	var wg sync.WaitGroup
+	for i, chainID := range chainIDs {
+		wg.Add(1)
+		go func(i int, chainID uint) {
+			defer wg.Done()
+			chains[i] = s.chainPortfolio(ctx, chainID, owner)
+		}(i, chainID)
+	}
+	wg.Wait()
+
+	return &Portfolio{
+		Address: owner.Hex(),
+		Chains:  chains,
+	}, nil
+}
+
+// chainPortfolio 查询单条链上的原生币与已注册代币余额
+func (s *walletService) chainPortfolio(ctx context.Context, chainID uint, owner common.Address) ChainPortfolio {
+	result := ChainPortfolio{
+		ChainID: chainID,
+		Name:    s.chainName(chainID),
+	}
+
+	balances, err := s.readChainBalances(ctx, chainID, owner)
+	if err != nil {
+		logger.Logger.Warn("Failed to load chain portfolio",
+			zap.Uint("chain_id", chainID),
+			zap.String("address", owner.Hex()),
+			zap.Error(err),
+		)
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Balances = balances
+	return result
+}
+
+// readChainBalances 通过 BalanceReader 批量读取余额，并按 decimals 换算为人类可读金额
+func (s *walletService) readChainBalances(ctx context.Context, chainID uint, owner common.Address) ([]Balance, error) {
+	tokens, err := s.tokenRegistry.List(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenAddresses := make([]common.Address, len(tokens))
+	for i, token := range tokens {
+		tokenAddresses[i] = token.Address
+	}
+
+	snapshot, err := s.balanceReader.ReadBalances(ctx, chainID, owner, tokenAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]Balance, 0, len(tokens)+1)
+	balances = append(balances, Balance{
+		Symbol:   s.tokenRegistry.NativeSymbol(chainID),
+		Decimals: conversion.EtherDecimals,
+		Amount:   conversion.WeiToEther(snapshot.Native).String(),
+	})
+
+	for i, token := range tokens {
+		balance := Balance{
+			Symbol:       token.Symbol,
+			TokenAddress: token.Address.Hex(),
+			Decimals:     token.Decimals,
+		}
+
+		if tokenResult := snapshot.Tokens[i]; tokenResult.Err != nil {
+			balance.Error = fmt.Sprintf("failed to read balance: %s", tokenResult.Err.Error())
+		} else {
+			balance.Amount = conversion.FromBaseUnits(tokenResult.Balance, token.Decimals).String()
+		}
+
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// chainName 返回链配置中的名称
+func (s *walletService) chainName(chainID uint) string {
+	for _, chainCfg := range s.cfg.Chains {
+		if chainCfg.ChainID == chainID {
+			return chainCfg.Name
+		}
+	}
+	return ""
+}
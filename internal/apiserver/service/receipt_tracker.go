@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/web3client"
+)
+
+// ReceiptTracker 的默认参数，对应 config.TrackerConfig / BlockchainConfig 中未配置的字段
+const (
+	defaultTrackerPollInterval = 15 * time.Second
+	defaultTrackerBatchSize    = 200
+	defaultTrackerDropAfter    = 30 * time.Minute
+	defaultConfirmations       = 12
+)
+
+// ReceiptTracker 是后台轮询交易回执的 worker。
+// 它为 pending / mined 状态的交易记录区块号、gas 消耗与实际 gas 价格，
+// 在达到链配置的确认深度后将交易标记为 confirmed / failed，并检测重组导致的交易"被撤销打包"。
+type ReceiptTracker struct {
+	txStore       TransactionStore
+	clientManager web3client.ClientManager
+
+	confirmations map[uint]uint64
+	interval      time.Duration
+	batchSize     int
+	dropAfter     time.Duration
+
+	// mu 串行化 Poll，后台循环与手动触发的维护任务不会并发处理同一批交易；
+	// cursor 是上一批最后一笔交易的 ID，每轮从其后继续，到末尾后回到开头
+	mu     sync.Mutex
+	cursor uint
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReceiptTracker 创建 ReceiptTracker，配置中的时长字段格式错误时返回错误
+func NewReceiptTracker(
+	txStore TransactionStore,
+	clientManager web3client.ClientManager,
+	cfg *config.Config,
+) (*ReceiptTracker, error) {
+	interval, err := parseDurationOrDefault(cfg.Tracker.PollInterval, defaultTrackerPollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracker poll_interval: %w", err)
+	}
+
+	dropAfter, err := parseDurationOrDefault(cfg.Tracker.DropAfter, defaultTrackerDropAfter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracker drop_after: %w", err)
+	}
+
+	batchSize := cfg.Tracker.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultTrackerBatchSize
+	}
+
+	confirmations := make(map[uint]uint64, len(cfg.Chains))
+	for _, chainCfg := range cfg.Chains {
+		confirmations[chainCfg.ChainID] = chainCfg.Confirmations
+	}
+
+	return &ReceiptTracker{
+		txStore:       txStore,
+		clientManager: clientManager,
+		confirmations: confirmations,
+		interval:      interval,
+		batchSize:     batchSize,
+		dropAfter:     dropAfter,
+	}, nil
+}
+
+// Start 在独立 goroutine 中启动轮询循环
+func (t *ReceiptTracker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+
+	go t.run(ctx)
+
+	logger.Logger.Info("Receipt tracker started",
+		zap.Duration("interval", t.interval),
+		zap.Int("batch_size", t.batchSize),
+	)
+}
+
+// Stop 通知轮询循环退出，并等待当前一轮处理完成或 ctx 超时
+func (t *ReceiptTracker) Stop(ctx context.Context) error {
+	if t.cancel == nil {
+		return nil
+	}
+	t.cancel()
+
+	select {
+	case <-t.done:
+		logger.Logger.Info("Receipt tracker stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("receipt tracker did not stop in time: %w", ctx.Err())
+	}
+}
+
+// run 是轮询主循环
+func (t *ReceiptTracker) run(ctx context.Context) {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll 执行一轮追踪：按 ID 游标取下一批交易，按链获取最新高度，再逐笔检查回执。
+// 游标保证长期 pending 的交易不会一直占满批次而使更新的交易得不到处理。
+func (t *ReceiptTracker) Poll(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txs, err := t.txStore.ListTrackableTransactions(ctx, t.cursor, t.batchSize)
+	if err != nil {
+		logger.Logger.Error("Failed to load trackable transactions", zap.Error(err))
+		return
+	}
+
+	if len(txs) < t.batchSize {
+		t.cursor = 0
+	} else {
+		t.cursor = txs[len(txs)-1].ID
+	}
+
+	heads := make(map[uint]uint64)
+	for i := range txs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		tx := &txs[i]
+
+		head, ok := heads[tx.ChainID]
+		if !ok {
+			head, err = t.clientManager.BlockNumber(ctx, tx.ChainID)
+			if err != nil {
+				logger.Logger.Warn("Failed to fetch chain head", zap.Uint("chain_id", tx.ChainID), zap.Error(err))
+				continue
+			}
+			heads[tx.ChainID] = head
+		}
+
+		if err := t.track(ctx, tx, head); err != nil {
+			logger.Logger.Warn("Failed to track transaction",
+				zap.Uint("chain_id", tx.ChainID),
+				zap.String("tx_hash", tx.Hash),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// track 检查单笔交易的回执并更新其状态
+func (t *ReceiptTracker) track(ctx context.Context, tx *model.Transaction, head uint64) error {
+	hash := common.HexToHash(tx.Hash)
+
+	receipt, err := t.clientManager.TransactionReceipt(ctx, tx.ChainID, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		if tx.Status == model.TxStatusMined {
+			// 之前已打包，现在回执消失：交易所在区块被重组撤销
+			return t.revertToPending(ctx, tx, "receipt disappeared")
+		}
+		return t.checkDropped(ctx, tx)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 校验回执所在区块仍在主链上，防止节点返回孤块中的回执
+	header, err := t.clientManager.HeaderByNumber(ctx, tx.ChainID, receipt.BlockNumber)
+	if err != nil {
+		return err
+	}
+	if header.Hash() != receipt.BlockHash {
+		if tx.Status == model.TxStatusMined {
+			return t.revertToPending(ctx, tx, "receipt block no longer canonical")
+		}
+		return nil // 节点尚未同步到一致状态，下一轮再查
+	}
+
+	if tx.BlockHash != nil && *tx.BlockHash != receipt.BlockHash.Hex() {
+		logger.Logger.Warn("Transaction re-mined in a different block after reorg",
+			zap.String("tx_hash", tx.Hash),
+			zap.String("old_block_hash", *tx.BlockHash),
+			zap.String("new_block_hash", receipt.BlockHash.Hex()),
+		)
+	}
+
+	// 记录回执信息
+	blockNumber := receipt.BlockNumber.Uint64()
+	blockHash := receipt.BlockHash.Hex()
+	gasUsed := receipt.GasUsed
+	tx.BlockNumber = &blockNumber
+	tx.BlockHash = &blockHash
+	tx.GasUsed = &gasUsed
+	if receipt.EffectiveGasPrice != nil {
+		effectiveGasPrice := receipt.EffectiveGasPrice.String()
+		tx.EffectiveGasPrice = &effectiveGasPrice
+	}
+
+	// 根据确认深度决定最终状态
+	var confirmations uint64
+	if head >= blockNumber {
+		confirmations = head - blockNumber + 1
+	}
+
+	tx.Status = model.TxStatusMined
+	if confirmations >= t.requiredConfirmations(tx.ChainID) {
+		now := time.Now()
+		tx.ConfirmedAt = &now
+		tx.Status = model.TxStatusConfirmed
+		if receipt.Status == 0 {
+			tx.Status = model.TxStatusFailed
+		}
+	}
+
+	return t.txStore.UpdateTransactionStatus(ctx, tx)
+}
+
+// checkDropped 判断未上链的交易是否已被丢弃
+func (t *ReceiptTracker) checkDropped(ctx context.Context, tx *model.Transaction) error {
+	// 1. nonce 已被其他交易消耗：该交易再也无法上链
+	confirmedNonce, err := t.clientManager.NonceAt(ctx, tx.ChainID, common.HexToAddress(tx.FromAddress))
+	if err != nil {
+		return err
+	}
+	if confirmedNonce > tx.Nonce {
+		if tx.ReplacedByHash != nil {
+			// 同 nonce 的加速/取消交易已上链
+			return t.markReplaced(ctx, tx)
+		}
+		return t.markDropped(ctx, tx, "nonce consumed by another transaction")
+	}
+
+	// 2. 超过 drop_after 且节点已不认识该交易：视为被 mempool 驱逐。
+	// 已被替换的交易会被节点从 mempool 移除，需等待 nonce 被消耗后再判定。
+	if tx.ReplacedByHash != nil || time.Since(tx.CreatedAt) < t.dropAfter {
+		return nil
+	}
+
+	_, _, err = t.clientManager.TransactionByHash(ctx, tx.ChainID, common.HexToHash(tx.Hash))
+	if errors.Is(err, ethereum.NotFound) {
+		return t.markDropped(ctx, tx, "evicted from mempool")
+	}
+
+	return err
+}
+
+// markDropped 将交易标记为 dropped
+func (t *ReceiptTracker) markDropped(ctx context.Context, tx *model.Transaction, reason string) error {
+	logger.Logger.Warn("Transaction dropped",
+		zap.Uint("chain_id", tx.ChainID),
+		zap.String("tx_hash", tx.Hash),
+		zap.Uint64("nonce", tx.Nonce),
+		zap.String("reason", reason),
+	)
+
+	tx.Status = model.TxStatusDropped
+	return t.txStore.UpdateTransactionStatus(ctx, tx)
+}
+
+// markReplaced 将已被替换交易取代的原交易标记为 replaced
+func (t *ReceiptTracker) markReplaced(ctx context.Context, tx *model.Transaction) error {
+	logger.Logger.Info("Transaction replaced",
+		zap.Uint("chain_id", tx.ChainID),
+		zap.String("tx_hash", tx.Hash),
+		zap.String("replaced_by", *tx.ReplacedByHash),
+	)
+
+	tx.Status = model.TxStatusReplaced
+	return t.txStore.UpdateTransactionStatus(ctx, tx)
+}
+
+// revertToPending 在检测到重组时清空回执字段，交易回到 pending 等待重新打包
+func (t *ReceiptTracker) revertToPending(ctx context.Context, tx *model.Transaction, reason string) error {
+	logger.Logger.Warn("Reorg detected, transaction un-mined",
+		zap.Uint("chain_id", tx.ChainID),
+		zap.String("tx_hash", tx.Hash),
+		zap.String("reason", reason),
+	)
+
+	tx.Status = model.TxStatusPending
+	tx.BlockNumber = nil
+	tx.BlockHash = nil
+	tx.GasUsed = nil
+	tx.EffectiveGasPrice = nil
+	tx.ConfirmedAt = nil
+
+	return t.txStore.UpdateTransactionStatus(ctx, tx)
+}
+
+// requiredConfirmations 返回链配置的确认深度
+func (t *ReceiptTracker) requiredConfirmations(chainID uint) uint64 {
+	if n := t.confirmations[chainID]; n > 0 {
+		return n
+	}
+	return defaultConfirmations
+}
+
+// parseDurationOrDefault 解析时长字符串，空字符串返回默认值
+func parseDurationOrDefault(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive: %s", value)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/web3client"
+)
+
+// pagingTxStore 记录每轮轮询取到的交易 ID
+type pagingTxStore struct {
+	TransactionStore
+
+	ids     []uint
+	batches [][]uint
+}
+
+func (p *pagingTxStore) ListTrackableTransactions(_ context.Context, afterID uint, limit int) ([]model.Transaction, error) {
+	var txs []model.Transaction
+	var batch []uint
+	for _, id := range p.ids {
+		if id > afterID && len(txs) < limit {
+			txs = append(txs, model.Transaction{ID: id, ChainID: 1})
+			batch = append(batch, id)
+		}
+	}
+	p.batches = append(p.batches, batch)
+	return txs, nil
+}
+
+// unreachableClients 查询区块高度总是失败，使 Poll 跳过回执检查
+type unreachableClients struct {
+	web3client.ClientManager
+}
+
+func (unreachableClients) BlockNumber(context.Context, uint) (uint64, error) {
+	return 0, errors.New("unreachable")
+}
+
+func TestReceiptTrackerPollPagesWithCursor(t *testing.T) {
+	store := &pagingTxStore{ids: []uint{1, 2, 3, 4, 5}}
+	tracker := &ReceiptTracker{txStore: store, clientManager: unreachableClients{}, batchSize: 2}
+
+	for range 4 {
+		tracker.Poll(context.Background())
+	}
+
+	// 不足一批说明已到末尾，下一轮回到开头，较新的交易不会被较旧的交易饿死
+	want := [][]uint{{1, 2}, {3, 4}, {5}, {1, 2}}
+	if !reflect.DeepEqual(store.batches, want) {
+		t.Errorf("batches = %v, want %v", store.batches, want)
+	}
+}
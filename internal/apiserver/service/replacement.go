@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// replacementBumpPercent 是 txpool 替换同 nonce 交易时要求的最小手续费涨幅 (geth 默认 10%)
+const replacementBumpPercent = 10
+
+// SpeedUpTransaction implements WalletService.
+// 使用原交易的 nonce、收款方、金额与 calldata，以更高的手续费重新签名并广播。
+func (s *walletService) SpeedUpTransaction(ctx context.Context, params *ReplaceParams) (string, error) {
+	return s.replaceTransaction(ctx, params, false)
+}
+
+// CancelTransaction implements WalletService.
+// 在原交易的 nonce 上发送一笔 0 金额的自转账，替换交易上链后原交易即失效。
+func (s *walletService) CancelTransaction(ctx context.Context, params *ReplaceParams) (string, error) {
+	return s.replaceTransaction(ctx, params, true)
+}
+
+// replaceTransaction 是加速与取消的公共流程：
+// 归属校验 -> 可替换性校验 -> 获取签名者 -> 确定不低于原交易 110% 的手续费 -> 签名 -> 广播 -> 关联原交易
+func (s *walletService) replaceTransaction(ctx context.Context, params *ReplaceParams, cancel bool) (string, error) {
+	// 1. 加载原交易并校验归属与状态
+	original, err := s.loadReplaceableTransaction(ctx, params.UserID, params.TxHash)
+	if err != nil {
+		return "", err
+	}
+	chainID := original.ChainID
+
+	if err := s.ensureChainSupported(chainID); err != nil {
+		return "", err
+	}
+
+	from := common.HexToAddress(original.FromAddress)
+	wallet, err := s.store.GetWalletByAddress(ctx, from.Hex())
+	if err != nil {
+		return "", fmt.Errorf("failed to load wallet: %w", err)
+	}
+	if wallet == nil || wallet.UserID != params.UserID {
+		return "", ErrWalletNotFound
+	}
+
+	// 2. 原交易的 nonce 已在链上被消耗（已打包），无法再替换
+	confirmedNonce, err := s.clientManager.NonceAt(ctx, chainID, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to get confirmed nonce: %w", err)
+	}
+	if confirmedNonce > original.Nonce {
+		return "", ErrTxNotReplaceable
+	}
+
+	originalTx, err := decodeRawTransaction(original.RawTx)
+	if err != nil {
+		return "", err
+	}
+
+	// 3. 获取钱包的签名者
+	signer, err := s.signerFor(ctx, wallet, params.Password)
+	if err != nil {
+		return "", err
+	}
+
+	// 4. 手续费：取当前档位 / 显式 fee cap 与原交易涨幅下限中的较高者
+	suggested, err := s.resolveFees(ctx, chainID, params.FeeTier, params.MaxFeePerGasGwei, params.MaxPriorityFeePerGasGwei)
+	if err != nil {
+		return "", err
+	}
+	explicit := params.MaxFeePerGasGwei != "" || params.MaxPriorityFeePerGasGwei != ""
+
+	fees, err := replacementFees(originalTx, suggested, explicit)
+	if err != nil {
+		return "", err
+	}
+
+	// 5. 构建替换交易：加速沿用原交易内容，取消则改为 0 金额自转账
+	to := *originalTx.To()
+	value := originalTx.Value()
+	gasLimit := originalTx.Gas()
+	data := originalTx.Data()
+	if cancel {
+		to = from
+		value = new(big.Int)
+		gasLimit = ethparams.TxGas
+		data = nil
+	}
+
+	balance, err := s.clientManager.GetBalanceByAddress(ctx, chainID, from.Hex())
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch balance: %w", err)
+	}
+	fee := new(big.Int).Mul(fees.feeCap(), new(big.Int).SetUint64(gasLimit))
+	if balance.Cmp(new(big.Int).Add(value, fee)) < 0 {
+		return "", ErrInsufficientGas
+	}
+
+	// 6. 签名并广播（nonce 沿用原交易，无需经过 nonce 分配器）
+	chainIDBig := new(big.Int).SetUint64(uint64(chainID))
+	tx := fees.newTx(chainIDBig, original.Nonce, to, value, gasLimit, data)
+
+	signedTx, err := s.signAndSend(ctx, chainID, from, tx, signer)
+	if err != nil {
+		return "", err
+	}
+
+	// 7. 写入交易历史并关联原交易；加速 ERC-20 转账时沿用原记录中的代币收款方与金额
+	var transfer *tokenTransfer
+	if !cancel {
+		transfer = tokenTransferOf(original)
+	}
+	s.recordTransaction(ctx, params.UserID, chainID, from, signedTx, transfer, original)
+
+	logger.Logger.Info("Replacement transaction broadcast",
+		zap.Uint("user_id", params.UserID),
+		zap.Uint("chain_id", chainID),
+		zap.String("from", from.Hex()),
+		zap.Bool("cancel", cancel),
+		zap.String("original_tx_hash", original.Hash),
+		zap.String("tx_hash", signedTx.Hash().Hex()),
+		zap.Uint64("nonce", original.Nonce),
+	)
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// loadReplaceableTransaction 加载属于 userID 且仍可被替换的交易：
+// 必须处于 pending 状态，且尚未被其他交易替换（应对最新的替换交易再次加速）
+func (s *walletService) loadReplaceableTransaction(
+	ctx context.Context,
+	userID uint,
+	txHash string,
+) (*model.Transaction, error) {
+	raw, err := hexutil.Decode(txHash)
+	if err != nil || len(raw) != common.HashLength {
+		return nil, ErrTxNotFound
+	}
+
+	record, err := s.txStore.GetTransactionByHash(ctx, common.BytesToHash(raw).Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transaction: %w", err)
+	}
+	if record == nil || record.UserID != userID {
+		return nil, ErrTxNotFound
+	}
+
+	if record.Status != model.TxStatusPending || record.ReplacedByHash != nil {
+		return nil, ErrTxNotReplaceable
+	}
+
+	return record, nil
+}
+
+// decodeRawTransaction 解析交易历史中保存的已签名交易字节
+func decodeRawTransaction(rawTx string) (*types.Transaction, error) {
+	raw, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw transaction: %w", err)
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode raw transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// replacementFees 计算替换交易的手续费，交易类型与原交易保持一致。
+// 建议值低于原交易的 110% 时自动抬升到下限；但显式指定的 fee cap 不足时返回 ErrReplacementUnderpriced。
+func replacementFees(original *types.Transaction, suggested *txFees, explicit bool) (*txFees, error) {
+	if original.Type() == types.LegacyTxType {
+		minGasPrice := bumpFee(original.GasPrice())
+		gasPrice := suggested.feeCap()
+
+		if gasPrice.Cmp(minGasPrice) < 0 {
+			if explicit {
+				return nil, ErrReplacementUnderpriced
+			}
+			gasPrice = minGasPrice
+		}
+
+		return &txFees{legacy: true, gasPrice: gasPrice}, nil
+	}
+
+	// EIP-1559：txpool 要求 maxFee 与 maxPriorityFee 同时满足涨幅
+	minFeeCap := bumpFee(original.GasFeeCap())
+	minTipCap := bumpFee(original.GasTipCap())
+
+	feeCap, tipCap := suggested.maxFeePerGas, suggested.maxPriorityFeePerGas
+	if suggested.legacy {
+		feeCap, tipCap = suggested.gasPrice, suggested.gasPrice
+	}
+
+	if feeCap.Cmp(minFeeCap) < 0 || tipCap.Cmp(minTipCap) < 0 {
+		if explicit {
+			return nil, ErrReplacementUnderpriced
+		}
+		feeCap = bigMax(feeCap, minFeeCap)
+		tipCap = bigMax(tipCap, minTipCap)
+	}
+
+	return &txFees{maxFeePerGas: feeCap, maxPriorityFeePerGas: tipCap}, nil
+}
+
+// tokenTransferOf 从交易历史记录还原 ERC-20 转账信息，原生币转账返回 nil
+func tokenTransferOf(record *model.Transaction) *tokenTransfer {
+	if record.TokenAddress == nil {
+		return nil
+	}
+
+	amount, ok := new(big.Int).SetString(record.Value, 10)
+	if !ok {
+		return nil
+	}
+
+	return &tokenTransfer{
+		token:     common.HexToAddress(*record.TokenAddress),
+		recipient: common.HexToAddress(record.ToAddress),
+		amount:    amount,
+	}
+}
+
+// bumpFee 返回 fee 上浮 replacementBumpPercent 后的值（向上取整）
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+replacementBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// bigMax 返回两个大整数中的较大者
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultSignatureSkew = 5 * time.Minute
+
+	// 请求 nonce 的长度范围，字符集为 [A-Za-z0-9_-]
+	minRequestNonceLength = 16
+	maxRequestNonceLength = 64
+)
+
+var (
+	ErrRequestSignatureInvalid = errors.New("request signature is invalid")
+	ErrRequestSignatureExpired = errors.New("request timestamp is outside the allowed clock skew")
+	ErrRequestReplayed         = errors.New("request nonce has already been used")
+)
+
+// RequestSignatureVerifier 定义了 VerifySignature 中间件使用的请求签名校验
+type RequestSignatureVerifier interface {
+	// VerifyRequestSignature 校验 API Key 请求的签名、时间戳与 nonce，nonce 在时钟偏差窗口内只能使用一次
+	VerifyRequestSignature(ctx context.Context, keyID uint, req *SignedRequest) error
+}
+
+// SignedRequest 是待校验的签名请求，签名内容见 CanonicalRequest
+type SignedRequest struct {
+	Method    string
+	Path      string // 含查询字符串的请求 URI
+	Timestamp string // Unix 秒
+	Nonce     string
+	BodyHash  string // 请求体 SHA-256 的十六进制小写编码
+	Signature string // HMAC-SHA256 的十六进制编码
+}
+
+// CanonicalRequest 返回签名内容：METHOD、请求 URI、时间戳、nonce、请求体哈希，以换行连接。
+// 客户端使用创建 API Key 时返回的 signing_secret 计算 HMAC-SHA256，十六进制编码后放入 X-Signature。
+func CanonicalRequest(req *SignedRequest) string {
+	return strings.Join([]string{
+		strings.ToUpper(req.Method),
+		req.Path,
+		req.Timestamp,
+		req.Nonce,
+		strings.ToLower(req.BodyHash),
+	}, "\n")
+}
+
+// HashRequestBody 计算请求体的 SHA-256，返回十六进制小写编码
+func HashRequestBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignRequest 使用签名密钥计算请求签名
+func SignRequest(signingSecret string, req *SignedRequest) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(CanonicalRequest(req)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequestSignature implements RequestSignatureVerifier.
+// 先校验时间戳与签名，签名有效后才记录 nonce，避免伪造请求占用 nonce。
+func (s *apiKeyService) VerifyRequestSignature(ctx context.Context, keyID uint, req *SignedRequest) error {
+	now := time.Now()
+
+	unix, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return ErrRequestSignatureInvalid
+	}
+	timestamp := time.Unix(unix, 0)
+	if timestamp.Before(now.Add(-s.signatureSkew)) || timestamp.After(now.Add(s.signatureSkew)) {
+		return ErrRequestSignatureExpired
+	}
+
+	if !isRequestNonce(req.Nonce) {
+		return ErrRequestSignatureInvalid
+	}
+
+	key, err := s.store.GetAPIKeyByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if key == nil || key.SigningSecret == "" {
+		return ErrRequestSignatureInvalid
+	}
+
+	signingSecret, err := s.openSigningSecret(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	expected, err := hex.DecodeString(SignRequest(signingSecret, req))
+	if err != nil {
+		return err
+	}
+	actual, err := hex.DecodeString(req.Signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		logger.Logger.Warn("Invalid request signature",
+			zap.Uint("key_id", key.ID),
+			zap.String("prefix", key.Prefix),
+			zap.String("method", req.Method),
+			zap.String("path", req.Path),
+		)
+		return ErrRequestSignatureInvalid
+	}
+
+	// 清理已过期的 nonce，失败不影响校验
+	if _, err := s.store.DeleteExpiredRequestNonces(ctx, now); err != nil {
+		logger.Logger.Warn("Failed to purge expired request nonces", zap.Error(err))
+	}
+
+	// 时间戳超出偏差窗口的请求已被拒绝，nonce 只需保留到窗口结束
+	inserted, err := s.store.UseRequestNonce(ctx, &model.APIRequestNonce{
+		APIKeyID:  key.ID,
+		Nonce:     req.Nonce,
+		ExpiresAt: timestamp.Add(s.signatureSkew),
+	})
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		logger.Logger.Warn("Replayed request nonce",
+			zap.Uint("key_id", key.ID),
+			zap.String("prefix", key.Prefix),
+		)
+		return ErrRequestReplayed
+	}
+
+	return nil
+}
+
+// sealSigningSecret 按配置对签名密钥做信封加密后写入记录，未配置密钥管理时保存明文
+func (s *apiKeyService) sealSigningSecret(ctx context.Context, key *model.APIKey, signingSecret string) error {
+	if s.cipher == nil {
+		key.SigningSecret = signingSecret
+		return nil
+	}
+
+	envelope, err := s.cipher.Seal(ctx, []byte(signingSecret))
+	if err != nil {
+		return fmt.Errorf("failed to seal api key signing secret: %w", err)
+	}
+
+	key.SigningSecret = envelope.Ciphertext
+	key.WrappedDataKey = envelope.WrappedKey
+	key.MasterKeyID = envelope.KeyID
+	return nil
+}
+
+// openSigningSecret 解密记录中的签名密钥，兼容未加密的历史数据
+func (s *apiKeyService) openSigningSecret(ctx context.Context, key *model.APIKey) (string, error) {
+	if key.MasterKeyID == "" {
+		return key.SigningSecret, nil
+	}
+	if s.cipher == nil {
+		return "", fmt.Errorf("signing secret of api key %d is envelope-encrypted but key management is not configured", key.ID)
+	}
+
+	secret, err := s.cipher.Open(ctx, apiKeyEnvelope(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to open signing secret for api key %d: %w", key.ID, err)
+	}
+
+	return string(secret), nil
+}
+
+// apiKeyEnvelope 返回记录中签名密钥的信封
+func apiKeyEnvelope(key *model.APIKey) *crypto.Envelope {
+	return &crypto.Envelope{
+		Ciphertext: key.SigningSecret,
+		WrappedKey: key.WrappedDataKey,
+		KeyID:      key.MasterKeyID,
+	}
+}
+
+// newSigningSecret 生成请求签名密钥 (256 bit，Base64 URL 编码)
+func newSigningSecret() (string, error) {
+	buf := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// isRequestNonce 判断 nonce 的长度与字符集是否合法
+func isRequestNonce(nonce string) bool {
+	if len(nonce) < minRequestNonceLength || len(nonce) > maxRequestNonceLength {
+		return false
+	}
+	for _, r := range nonce {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
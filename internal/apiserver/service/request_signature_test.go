@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// 固定向量：canonical 字符串与签名由独立实现（Python hmac / hashlib）计算
+const (
+	vectorSecret    = "test-signing-secret"
+	vectorBody      = `{"to":"0x0000000000000000000000000000000000000001","amount":"0.1"}`
+	vectorBodyHash  = "edf1ac6a48744e701a2d8b59bc320805e6be61922f5a7a9dbcf2fd6b8762bb8e"
+	vectorCanonical = "POST\n/api/v1/wallet/transfer?chain_id=1\n1760688000\nn0nce_Value-0001\n" + vectorBodyHash
+	vectorSignature = "ef817fdfe71ff1d826fc2bca8f47f71045a389041f487144f25e39ee1cd01889"
+)
+
+// fakeNonceStore 只实现 VerifyRequestSignature 使用的 APIKeyStore 方法
+type fakeNonceStore struct {
+	APIKeyStore
+
+	key    *model.APIKey
+	nonces map[string]bool
+}
+
+func (f *fakeNonceStore) GetAPIKeyByID(_ context.Context, keyID uint) (*model.APIKey, error) {
+	if f.key == nil || f.key.ID != keyID {
+		return nil, nil
+	}
+	return f.key, nil
+}
+
+func (f *fakeNonceStore) UseRequestNonce(_ context.Context, nonce *model.APIRequestNonce) (bool, error) {
+	if f.nonces[nonce.Nonce] {
+		return false, nil
+	}
+	f.nonces[nonce.Nonce] = true
+	return true, nil
+}
+
+func (f *fakeNonceStore) DeleteExpiredRequestNonces(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestCanonicalRequestVector(t *testing.T) {
+	if got := HashRequestBody([]byte(vectorBody)); got != vectorBodyHash {
+		t.Fatalf("HashRequestBody = %s, want %s", got, vectorBodyHash)
+	}
+	if got := HashRequestBody(nil); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("HashRequestBody(empty) = %s", got)
+	}
+
+	// 方法名大小写与哈希大小写均被规范化
+	req := &SignedRequest{
+		Method:    "post",
+		Path:      "/api/v1/wallet/transfer?chain_id=1",
+		Timestamp: "1760688000",
+		Nonce:     "n0nce_Value-0001",
+		BodyHash:  "EDF1AC6A48744E701A2D8B59BC320805E6BE61922F5A7A9DBCF2FD6B8762BB8E",
+	}
+	if got := CanonicalRequest(req); got != vectorCanonical {
+		t.Fatalf("CanonicalRequest = %q, want %q", got, vectorCanonical)
+	}
+	if got := SignRequest(vectorSecret, req); got != vectorSignature {
+		t.Errorf("SignRequest = %s, want %s", got, vectorSignature)
+	}
+}
+
+func newSignatureTestService() (*apiKeyService, *fakeNonceStore) {
+	store := &fakeNonceStore{
+		key:    &model.APIKey{ID: 7, Prefix: "wk_test", SigningSecret: vectorSecret},
+		nonces: make(map[string]bool),
+	}
+	return &apiKeyService{store: store, signatureSkew: defaultSignatureSkew}, store
+}
+
+func signedRequestAt(t time.Time, nonce string) *SignedRequest {
+	req := &SignedRequest{
+		Method:    "POST",
+		Path:      "/api/v1/wallet/transfer?chain_id=1",
+		Timestamp: strconv.FormatInt(t.Unix(), 10),
+		Nonce:     nonce,
+		BodyHash:  HashRequestBody([]byte(vectorBody)),
+	}
+	req.Signature = SignRequest(vectorSecret, req)
+	return req
+}
+
+func TestVerifyRequestSignature(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("valid then replayed", func(t *testing.T) {
+		s, _ := newSignatureTestService()
+		req := signedRequestAt(now, "replay-nonce-00001")
+
+		if err := s.VerifyRequestSignature(ctx, 7, req); err != nil {
+			t.Fatalf("first VerifyRequestSignature: %v", err)
+		}
+		if err := s.VerifyRequestSignature(ctx, 7, req); !errors.Is(err, ErrRequestReplayed) {
+			t.Errorf("replayed VerifyRequestSignature error = %v, want ErrRequestReplayed", err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		keyID  uint
+		mutate func(req *SignedRequest)
+		want   error
+	}{
+		{"timestamp too old", 7, func(req *SignedRequest) {
+			*req = *signedRequestAt(now.Add(-defaultSignatureSkew-time.Minute), req.Nonce)
+		}, ErrRequestSignatureExpired},
+		{"timestamp too far ahead", 7, func(req *SignedRequest) {
+			*req = *signedRequestAt(now.Add(defaultSignatureSkew+time.Minute), req.Nonce)
+		}, ErrRequestSignatureExpired},
+		{"timestamp not numeric", 7, func(req *SignedRequest) { req.Timestamp = "yesterday" }, ErrRequestSignatureInvalid},
+		{"nonce too short", 7, func(req *SignedRequest) { *req = *signedRequestAt(now, "short") }, ErrRequestSignatureInvalid},
+		{"nonce with invalid characters", 7, func(req *SignedRequest) {
+			*req = *signedRequestAt(now, "nonce with spaces!!")
+		}, ErrRequestSignatureInvalid},
+		{"tampered path", 7, func(req *SignedRequest) { req.Path += "&amount=100" }, ErrRequestSignatureInvalid},
+		{"tampered body", 7, func(req *SignedRequest) { req.BodyHash = HashRequestBody([]byte("{}")) }, ErrRequestSignatureInvalid},
+		{"signature not hex", 7, func(req *SignedRequest) { req.Signature = "zz" }, ErrRequestSignatureInvalid},
+		{"unknown key", 8, func(*SignedRequest) {}, ErrRequestSignatureInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store := newSignatureTestService()
+			req := signedRequestAt(now, "rejected-nonce-0001")
+			tt.mutate(req)
+
+			if err := s.VerifyRequestSignature(ctx, tt.keyID, req); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyRequestSignature error = %v, want %v", err, tt.want)
+			}
+			// 被拒绝的请求不得占用 nonce
+			if len(store.nonces) != 0 {
+				t.Errorf("rejected request recorded nonce: %v", store.nonces)
+			}
+		})
+	}
+}
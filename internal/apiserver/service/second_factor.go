@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSecondFactorRequired = errors.New("second factor code required")
+	ErrSecondFactorInvalid  = errors.New("invalid second factor code")
+)
+
+// SecondFactorVerifier 定义了敏感操作的第二因子校验接口（如 TOTP、短信、邮件验证码）
+type SecondFactorVerifier interface {
+	// Enrolled 返回用户是否已绑定第二因子
+	Enrolled(ctx context.Context, userID uint) (bool, error)
+
+	// Verify 校验用户提交的验证码，失败时返回 ErrSecondFactorInvalid
+	Verify(ctx context.Context, userID uint, code string) error
+}
+
+// noopSecondFactorVerifier 是未接入任何第二因子时的默认实现：所有用户均视为未绑定
+type noopSecondFactorVerifier struct{}
+
+var _ SecondFactorVerifier = noopSecondFactorVerifier{}
+
+// NewNoopSecondFactorVerifier 返回不做任何校验的 SecondFactorVerifier
+func NewNoopSecondFactorVerifier() SecondFactorVerifier {
+	return noopSecondFactorVerifier{}
+}
+
+// Enrolled implements SecondFactorVerifier.
+func (noopSecondFactorVerifier) Enrolled(context.Context, uint) (bool, error) {
+	return false, nil
+}
+
+// Verify implements SecondFactorVerifier.
+func (noopSecondFactorVerifier) Verify(context.Context, uint, string) error {
+	return ErrSecondFactorInvalid
+}
+
+// checkSecondFactor 执行敏感操作的第二因子校验：用户已绑定时必须提交有效验证码；
+// required 为 true 时未绑定的用户同样被拒绝
+func checkSecondFactor(ctx context.Context, verifier SecondFactorVerifier, userID uint, code string, required bool) error {
+	enrolled, err := verifier.Enrolled(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check second factor enrollment: %w", err)
+	}
+
+	switch {
+	case enrolled && code == "":
+		return ErrSecondFactorRequired
+	case enrolled:
+		return verifier.Verify(ctx, userID, code)
+	case required:
+		return ErrSecondFactorRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+)
+
+// ErrSeedCipherUnavailable 表示记录已做信封加密，但当前未配置 key_management
+var ErrSeedCipherUnavailable = errors.New("mnemonic seed is envelope-encrypted but key management is not configured")
+
+// sealMnemonicSeed 对钱包密码加密后的助记词再做信封加密并写入 seed；
+// cipher 为 nil（未配置 key_management）时原样保存。
+func sealMnemonicSeed(
+	ctx context.Context,
+	cipher crypto.EnvelopeCipher,
+	seed *model.MnemonicSeed,
+	encryptedSeed string,
+) error {
+	if cipher == nil {
+		seed.EncryptedSeed = encryptedSeed
+		return nil
+	}
+
+	envelope, err := cipher.Seal(ctx, []byte(encryptedSeed))
+	if err != nil {
+		return fmt.Errorf("failed to seal mnemonic seed: %w", err)
+	}
+
+	seed.EncryptedSeed = envelope.Ciphertext
+	seed.WrappedDataKey = envelope.WrappedKey
+	seed.MasterKeyID = envelope.KeyID
+	return nil
+}
+
+// openMnemonicSeed 解开信封，返回钱包密码加密的助记词 JSON；
+// 未做信封加密的历史记录直接返回 EncryptedSeed。
+func openMnemonicSeed(ctx context.Context, cipher crypto.EnvelopeCipher, seed *model.MnemonicSeed) (string, error) {
+	if seed.MasterKeyID == "" {
+		return seed.EncryptedSeed, nil
+	}
+	if cipher == nil {
+		return "", ErrSeedCipherUnavailable
+	}
+
+	encryptedSeed, err := cipher.Open(ctx, seedEnvelope(seed))
+	if err != nil {
+		return "", fmt.Errorf("failed to open mnemonic seed %d: %w", seed.ID, err)
+	}
+
+	return string(encryptedSeed), nil
+}
+
+// seedEnvelope 从 seed 记录组装信封
+func seedEnvelope(seed *model.MnemonicSeed) *crypto.Envelope {
+	return &crypto.Envelope{
+		Ciphertext: seed.EncryptedSeed,
+		WrappedKey: seed.WrappedDataKey,
+		KeyID:      seed.MasterKeyID,
+	}
+}
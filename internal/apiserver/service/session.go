@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	// refreshTokenBytes 是不透明刷新令牌的随机字节数
+	refreshTokenBytes = 32
+
+	// tokenTypeBearer 是访问令牌的类型
+	tokenTypeBearer = "Bearer"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid, expired or revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// SessionStore 定义了刷新令牌（会话）的存储接口
+type SessionStore interface {
+	// CreateSession 保存新签发的刷新令牌
+	CreateSession(ctx context.Context, session *model.Session) error
+
+	// GetSessionByTokenHash 根据刷新令牌哈希查找记录（含已轮换、已注销的记录），不存在时返回 nil, nil
+	GetSessionByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error)
+
+	// RotateSession 原子地将 current 标记为已轮换并写入 next，current 已被轮换或注销时返回 false
+	RotateSession(ctx context.Context, current *model.Session, next *model.Session, now time.Time) (bool, error)
+
+	// RevokeSessionFamily 注销用户某个会话下的全部刷新令牌，返回被注销的记录数
+	RevokeSessionFamily(ctx context.Context, userID uint, familyID string, now time.Time) (int64, error)
+
+	// RevokeSessionsStartedBefore 注销用户在 startedBefore 之前登录的全部会话，返回被注销的记录数
+	RevokeSessionsStartedBefore(ctx context.Context, userID uint, startedBefore time.Time, now time.Time) (int64, error)
+
+	// ListActiveSessions 返回用户每个有效会话当前可用的刷新令牌记录
+	ListActiveSessions(ctx context.Context, userID uint, now time.Time) ([]model.Session, error)
+
+	// DeleteExpiredSessions 删除 before 之前已过期的刷新令牌记录，返回删除的记录数
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SessionService 定义了登录会话的业务接口：签发短期访问令牌与不透明刷新令牌，
+// 刷新令牌每次使用后轮换，已轮换的令牌再次出现时注销整个会话。
+type SessionService interface {
+	// CreateSession 为已通过认证的用户创建新会话
+	CreateSession(ctx context.Context, user *model.User, client SessionClient) (*TokenPair, error)
+
+	// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+	Refresh(ctx context.Context, refreshToken string, client SessionClient) (*TokenPair, error)
+
+	// Logout 注销刷新令牌所属的会话，令牌无效时不返回错误
+	Logout(ctx context.Context, refreshToken string) error
+
+	// ListSessions 列出用户的有效会话，currentSessionID 对应的会话标记为当前设备
+	ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]SessionInfo, error)
+
+	// RevokeSession 注销用户的指定会话
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+}
+
+// SessionClient 是创建或刷新会话的请求来源
+type SessionClient struct {
+	ClientIP  string
+	UserAgent string
+}
+
+// TokenPair 是签发给客户端的访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int64     `json:"expires_in"` // 访问令牌有效期（秒）
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	SessionID        string    `json:"session_id"`
+}
+
+// SessionInfo 是展示给用户的会话（设备）信息
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	StartedAt  time.Time `json:"started_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	ClientIP   string    `json:"client_ip"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+}
+
+// sessionService 实现了 SessionService 接口
+type sessionService struct {
+	store             SessionStore
+	userStore         UserStore
+	jwtService        JWTService
+	revocationService TokenRevocationService // 注销会话时吊销该会话已签发的访问令牌
+	refreshTTL        time.Duration
+}
+
+var _ SessionService = (*sessionService)(nil)
+
+// NewSessionService 创建并返回一个新的 SessionService 实例
+func NewSessionService(
+	store SessionStore,
+	userStore UserStore,
+	jwtService JWTService,
+	revocationService TokenRevocationService,
+	cfg *config.Config,
+) (SessionService, error) {
+	refreshTTL, err := parseDurationOrDefault(cfg.Server.RefreshTokenDuration, defaultRefreshTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh_token_duration: %w", err)
+	}
+
+	return &sessionService{
+		store:             store,
+		userStore:         userStore,
+		jwtService:        jwtService,
+		revocationService: revocationService,
+		refreshTTL:        refreshTTL,
+	}, nil
+}
+
+// CreateSession implements SessionService.
+func (s *sessionService) CreateSession(ctx context.Context, user *model.User, client SessionClient) (*TokenPair, error) {
+	refreshToken, tokenHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &model.Session{
+		FamilyID:  uuid.NewString(),
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		StartedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+		ClientIP:  client.ClientIP,
+		UserAgent: truncate(client.UserAgent, 255),
+	}
+
+	if err := s.store.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Session created",
+		zap.Uint("user_id", user.ID),
+		zap.String("session_id", session.FamilyID),
+	)
+
+	return s.issue(user, session, refreshToken)
+}
+
+// Refresh implements SessionService.
+// 流程：查找令牌 -> 已轮换则判定为重放并注销整个会话 -> 原子轮换 -> 签发新的令牌对。
+// 新令牌沿用会话登录时确定的过期时间，持续刷新也不能延长会话。
+func (s *sessionService) Refresh(ctx context.Context, refreshToken string, client SessionClient) (*TokenPair, error) {
+	// 1. 查找令牌
+	current, err := s.store.GetSessionByTokenHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.RevokedAt != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	now := time.Now()
+
+	// 2. 重放检测：已轮换的令牌只可能来自泄露的副本，注销整个会话
+	if current.RotatedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, current, now)
+	}
+	if !now.Before(current.ExpiresAt) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 3. 加载用户，确保用户仍然存在且未被停用
+	user, err := s.userStore.FindByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 4. 原子轮换：并发使用同一令牌时只有一个请求成功，其余视为重放
+	nextToken, nextHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	next := &model.Session{
+		FamilyID:  current.FamilyID,
+		UserID:    current.UserID,
+		TokenHash: nextHash,
+		StartedAt: current.StartedAt,
+		ExpiresAt: current.ExpiresAt,
+		ClientIP:  client.ClientIP,
+		UserAgent: truncate(client.UserAgent, 255),
+	}
+
+	rotated, err := s.store.RotateSession(ctx, current, next, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, s.revokeReusedFamily(ctx, current, now)
+	}
+
+	return s.issue(user, next, nextToken)
+}
+
+// Logout implements SessionService.
+func (s *sessionService) Logout(ctx context.Context, refreshToken string) error {
+	session, err := s.store.GetSessionByTokenHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return nil
+	}
+
+	if _, err := s.revokeFamily(ctx, session.UserID, session.FamilyID, time.Now()); err != nil {
+		return err
+	}
+
+	logger.Logger.Info("Session logged out",
+		zap.Uint("user_id", session.UserID),
+		zap.String("session_id", session.FamilyID),
+	)
+
+	return nil
+}
+
+// ListSessions implements SessionService.
+func (s *sessionService) ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]SessionInfo, error) {
+	active, err := s.store.ListActiveSessions(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SessionInfo, 0, len(active))
+	for _, session := range active {
+		result = append(result, SessionInfo{
+			ID:         session.FamilyID,
+			StartedAt:  session.StartedAt,
+			LastUsedAt: session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+			ClientIP:   session.ClientIP,
+			UserAgent:  session.UserAgent,
+			Current:    session.FamilyID == currentSessionID,
+		})
+	}
+
+	return result, nil
+}
+
+// RevokeSession implements SessionService.
+func (s *sessionService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	revoked, err := s.revokeFamily(ctx, userID, sessionID, time.Now())
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrSessionNotFound
+	}
+
+	logger.Logger.Info("Session revoked",
+		zap.Uint("user_id", userID),
+		zap.String("session_id", sessionID),
+	)
+
+	return nil
+}
+
+// issue 为会话签发访问令牌，并与刷新令牌一起返回
+func (s *sessionService) issue(user *model.User, session *model.Session, refreshToken string) (*TokenPair, error) {
+	accessToken, err := s.jwtService.GenerateToken(user, session.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        tokenTypeBearer,
+		ExpiresIn:        int64(s.jwtService.AccessTokenTTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		SessionID:        session.FamilyID,
+	}, nil
+}
+
+// revokeReusedFamily 在检测到刷新令牌重放时注销整个会话
+func (s *sessionService) revokeReusedFamily(ctx context.Context, session *model.Session, now time.Time) error {
+	logger.Logger.Warn("Refresh token reuse detected, revoking session",
+		zap.Uint("user_id", session.UserID),
+		zap.String("session_id", session.FamilyID),
+	)
+
+	if _, err := s.revokeFamily(ctx, session.UserID, session.FamilyID, now); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+// revokeFamily 注销会话下的全部刷新令牌，并吊销该会话已签发、尚未过期的访问令牌，返回被注销的刷新令牌数
+func (s *sessionService) revokeFamily(ctx context.Context, userID uint, familyID string, now time.Time) (int64, error) {
+	revoked, err := s.store.RevokeSessionFamily(ctx, userID, familyID, now)
+	if err != nil {
+		return 0, err
+	}
+	if revoked == 0 {
+		return 0, nil
+	}
+
+	until := now.Add(s.jwtService.AccessTokenTTL())
+	if err := s.revocationService.RevokeSessionTokens(ctx, userID, familyID, until); err != nil {
+		return 0, fmt.Errorf("failed to revoke session access tokens: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// newRefreshToken 生成不透明刷新令牌及其哈希
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+// hashRefreshToken 计算刷新令牌的 SHA-256，数据库只保存哈希
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
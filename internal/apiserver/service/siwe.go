@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/siwe"
+)
+
+const (
+	defaultSIWENonceTTL = 10 * time.Minute
+
+	// siweNonceBytes 生成 32 位十六进制 nonce，满足 EIP-4361 对字母数字且不少于 8 位的要求
+	siweNonceBytes = 16
+
+	// siweClockSkew 允许客户端 Issued At 超前服务端的时间
+	siweClockSkew = time.Minute
+)
+
+var (
+	ErrSIWEDisabled         = errors.New("sign-in with ethereum is not enabled")
+	ErrSIWEInvalidMessage   = errors.New("malformed sign-in with ethereum message")
+	ErrSIWEDomainMismatch   = errors.New("sign-in message domain is not allowed")
+	ErrSIWEInvalidNonce     = errors.New("sign-in nonce is invalid, expired or already used")
+	ErrSIWEExpired          = errors.New("sign-in message is expired or not yet valid")
+	ErrSIWEInvalidSignature = errors.New("sign-in message signature does not match its address")
+	ErrSIWEAddressConflict  = errors.New("ethereum address is already linked to another account")
+	ErrSIWEAddressMismatch  = errors.New("sign-in message address is not linked to this account")
+	ErrPasswordAlreadySet   = errors.New("account password is already set")
+	ErrPasswordNotSet       = errors.New("account password is not set, set it with a sign-in with ethereum signature first")
+)
+
+// SIWENonceStore 定义了 SIWE nonce 的存储接口，nonce 只能被消费一次
+type SIWENonceStore interface {
+	// CreateSIWENonce 保存新签发的 nonce
+	CreateSIWENonce(ctx context.Context, nonce *model.SIWENonce) error
+
+	// ConsumeSIWENonce 原子地删除未过期的 nonce，返回 false 表示不存在、已使用或已过期
+	ConsumeSIWENonce(ctx context.Context, nonce string, now time.Time) (bool, error)
+
+	// DeleteExpiredSIWENonces 清理 before 之前过期的 nonce
+	DeleteExpiredSIWENonces(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SIWEService 定义了 Sign-In with Ethereum (EIP-4361) 登录的业务接口
+type SIWEService interface {
+	// IssueNonce 签发一个一次性 nonce，客户端将其写入待签名的 EIP-4361 消息
+	IssueNonce(ctx context.Context) (*SIWENonce, error)
+
+	// Login 校验签名消息，返回地址关联的用户（不存在时自动创建），令牌由 SessionService 签发
+	Login(ctx context.Context, message string, signature string) (*SIWELogin, error)
+
+	// Link 校验签名消息，并将签名地址关联到已登录的用户
+	Link(ctx context.Context, userID uint, message string, signature string) (*model.User, error)
+
+	// SetInitialPassword 为通过 SIWE 创建、尚无账户密码的用户设置密码。
+	// 关联地址签名的新消息作为重新认证；设置后即可使用依赖账户密码的接口（导出密钥、修改密码等）
+	SetInitialPassword(ctx context.Context, userID uint, message string, signature string, newPassword string) error
+}
+
+// SIWENonce 是签发给客户端的 nonce
+type SIWENonce struct {
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SIWELogin 是 SIWE 登录结果
+type SIWELogin struct {
+	User    *model.User
+	Created bool // 是否为本次登录新创建的用户
+}
+
+// siweService 实现了 SIWEService 接口
+type siweService struct {
+	userStore  UserStore
+	nonceStore SIWENonceStore
+
+	domains  map[string]struct{}
+	chainIDs map[uint]struct{}
+	nonceTTL time.Duration
+}
+
+var _ SIWEService = (*siweService)(nil)
+
+// NewSIWEService 创建并返回一个新的 SIWEService 实例。
+// 仅接受 siwe.domains 中的 domain 和 chains 中已配置的链，siwe.domains 为空时禁用 SIWE。
+func NewSIWEService(
+	userStore UserStore,
+	nonceStore SIWENonceStore,
+	cfg *config.Config,
+) (SIWEService, error) {
+	nonceTTL, err := parseDurationOrDefault(cfg.SIWE.NonceTTL, defaultSIWENonceTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid siwe nonce_ttl: %w", err)
+	}
+
+	domains := make(map[string]struct{}, len(cfg.SIWE.Domains))
+	for _, domain := range cfg.SIWE.Domains {
+		domains[domain] = struct{}{}
+	}
+
+	chainIDs := make(map[uint]struct{}, len(cfg.Chains))
+	for _, chainCfg := range cfg.Chains {
+		chainIDs[chainCfg.ChainID] = struct{}{}
+	}
+
+	return &siweService{
+		userStore:  userStore,
+		nonceStore: nonceStore,
+		domains:    domains,
+		chainIDs:   chainIDs,
+		nonceTTL:   nonceTTL,
+	}, nil
+}
+
+// IssueNonce implements SIWEService.
+func (s *siweService) IssueNonce(ctx context.Context) (*SIWENonce, error) {
+	if len(s.domains) == 0 {
+		return nil, ErrSIWEDisabled
+	}
+
+	buf := make([]byte, siweNonceBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate siwe nonce: %w", err)
+	}
+
+	now := time.Now()
+	nonce := &model.SIWENonce{
+		Nonce:     hex.EncodeToString(buf),
+		ExpiresAt: now.Add(s.nonceTTL),
+	}
+
+	// 清理过期 nonce，避免未认证接口导致表无限增长；失败不影响签发
+	if _, err := s.nonceStore.DeleteExpiredSIWENonces(ctx, now); err != nil {
+		logger.Logger.Warn("Failed to purge expired SIWE nonces", zap.Error(err))
+	}
+
+	if err := s.nonceStore.CreateSIWENonce(ctx, nonce); err != nil {
+		return nil, err
+	}
+
+	return &SIWENonce{Nonce: nonce.Nonce, ExpiresAt: nonce.ExpiresAt}, nil
+}
+
+// Login implements SIWEService.
+// 地址已关联用户时直接登录，否则创建以地址为用户名、无登录密码的新用户。
+func (s *siweService) Login(ctx context.Context, message string, signature string) (*SIWELogin, error) {
+	msg, err := s.verify(ctx, message, signature)
+	if err != nil {
+		return nil, err
+	}
+	address := msg.Address.Hex()
+
+	user, created, err := s.findOrCreateUser(address)
+	if err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	logger.Logger.Info("User signed in with ethereum",
+		zap.Uint("user_id", user.ID),
+		zap.String("address", address),
+		zap.Bool("created", created),
+	)
+
+	return &SIWELogin{User: user, Created: created}, nil
+}
+
+// Link implements SIWEService.
+func (s *siweService) Link(ctx context.Context, userID uint, message string, signature string) (*model.User, error) {
+	msg, err := s.verify(ctx, message, signature)
+	if err != nil {
+		return nil, err
+	}
+	address := msg.Address.Hex()
+
+	linked, err := s.userStore.LinkEthereumAddress(userID, address)
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrSIWEAddressConflict
+		}
+		return nil, fmt.Errorf("%w: failed to link ethereum address: %w", ErrStoreOperationFailed, err)
+	}
+	if !linked {
+		// 用户已关联了其他地址
+		return nil, ErrSIWEAddressConflict
+	}
+
+	user, err := s.userStore.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+
+	logger.Logger.Info("Ethereum address linked to user",
+		zap.Uint("user_id", userID),
+		zap.String("address", address),
+	)
+
+	return user, nil
+}
+
+// SetInitialPassword implements SIWEService.
+func (s *siweService) SetInitialPassword(
+	ctx context.Context,
+	userID uint,
+	message string,
+	signature string,
+	newPassword string,
+) error {
+	user, err := s.userStore.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+	if user.PasswordHash != "" {
+		return ErrPasswordAlreadySet
+	}
+	if user.EthereumAddress == nil {
+		return ErrSIWEAddressMismatch
+	}
+
+	msg, err := s.verify(ctx, message, signature)
+	if err != nil {
+		return err
+	}
+	if msg.Address != common.HexToAddress(*user.EthereumAddress) {
+		return ErrSIWEAddressMismatch
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPasswordHashFailed, err)
+	}
+
+	set, err := s.userStore.SetInitialPasswordHash(ctx, userID, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStoreOperationFailed, err)
+	}
+	if !set {
+		return ErrPasswordAlreadySet
+	}
+
+	logger.Logger.Info("Initial password set for SIWE user",
+		zap.Uint("user_id", userID),
+		zap.String("address", msg.Address.Hex()),
+	)
+
+	return nil
+}
+
+// verify 解析并校验 EIP-4361 消息：domain、链、有效期、签名，最后消费 nonce。
+// nonce 在其他校验全部通过后才被消费，签名错误的请求不会使合法用户的 nonce 失效。
+func (s *siweService) verify(ctx context.Context, message string, signature string) (*siwe.Message, error) {
+	if len(s.domains) == 0 {
+		return nil, ErrSIWEDisabled
+	}
+
+	msg, err := siwe.ParseMessage(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSIWEInvalidMessage, err)
+	}
+
+	if _, ok := s.domains[msg.Domain]; !ok {
+		return nil, ErrSIWEDomainMismatch
+	}
+	if _, ok := s.chainIDs[msg.ChainID]; !ok {
+		return nil, ErrChainNotSupported
+	}
+
+	now := time.Now()
+	if err := msg.CheckTime(now); err != nil {
+		return nil, ErrSIWEExpired
+	}
+	if msg.IssuedAt.After(now.Add(siweClockSkew)) {
+		return nil, ErrSIWEExpired
+	}
+
+	signer, err := siwe.RecoverAddress(message, signature)
+	if err != nil || signer != msg.Address {
+		return nil, ErrSIWEInvalidSignature
+	}
+
+	consumed, err := s.nonceStore.ConsumeSIWENonce(ctx, msg.Nonce, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrSIWEInvalidNonce
+	}
+
+	return msg, nil
+}
+
+// findOrCreateUser 查找地址关联的用户，不存在时创建；并发创建冲突时重新查找
+func (s *siweService) findOrCreateUser(address string) (*model.User, bool, error) {
+	user, err := s.userStore.FindByEthereumAddress(address)
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("%w: failed to query user by address: %w", ErrStoreOperationFailed, err)
+	}
+
+	linkedAddress := address
+	user = &model.User{
+		Username:        address, // 地址格式的用户名保留给 SIWE 用户，见 Register
+		EthereumAddress: &linkedAddress,
+		Role:            model.RoleUser,
+	}
+
+	if err := s.userStore.CreateUser(user); err != nil {
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, false, fmt.Errorf("%w: failed to create user: %w", ErrStoreOperationFailed, err)
+		}
+
+		user, err = s.userStore.FindByEthereumAddress(address)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: failed to query user by address: %w", ErrStoreOperationFailed, err)
+		}
+		return user, false, nil
+	}
+
+	return user, true, nil
+}
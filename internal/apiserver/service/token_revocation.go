@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	defaultRevocationCacheSize = 10000
+	defaultRevocationCacheTTL  = 30 * time.Second
+
+	// sessionRevocationPrefix 是会话吊销记录在 revoked_tokens 中的键前缀，与 jti (UUID) 不会冲突
+	sessionRevocationPrefix = "session:"
+)
+
+var ErrRevocationTimeInFuture = errors.New("revocation time must not be in the future")
+
+// TokenRevocationStore 定义了访问令牌吊销记录的存储接口
+type TokenRevocationStore interface {
+	// RevokeToken 将单个访问令牌加入吊销列表，令牌此前已被吊销时返回 false
+	RevokeToken(ctx context.Context, token *model.RevokedToken) (bool, error)
+
+	// IsTokenRevoked 检查访问令牌 (jti) 是否已被单独吊销
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+
+	// DeleteExpiredRevokedTokens 清理 before 之前已自然过期的吊销记录
+	DeleteExpiredRevokedTokens(ctx context.Context, before time.Time) (int64, error)
+
+	// RevokeUserTokensBefore 设置用户的令牌吊销时间点，已有更晚的时间点时保持不变，返回生效后的时间点
+	RevokeUserTokensBefore(ctx context.Context, userID uint, before time.Time) (time.Time, error)
+
+	// GetUserTokensRevokedBefore 返回用户的令牌吊销时间点，未设置时返回 nil, nil
+	GetUserTokensRevokedBefore(ctx context.Context, userID uint) (*time.Time, error)
+}
+
+// TokenDenylist 定义了 JWTAuth 使用的访问令牌吊销检查
+type TokenDenylist interface {
+	// IsRevoked 检查已通过签名校验的访问令牌是否已被吊销
+	IsRevoked(ctx context.Context, claims *JWTClaims) (bool, error)
+}
+
+// TokenRevocationService 定义了访问令牌吊销的业务接口
+type TokenRevocationService interface {
+	TokenDenylist
+
+	// RevokeToken 吊销单个访问令牌，如登出时吊销当前令牌
+	RevokeToken(ctx context.Context, claims *JWTClaims) error
+
+	// RevokeSessionTokens 吊销携带该会话 ID 的全部访问令牌，until 为此前签发的访问令牌的最晚过期时间
+	RevokeSessionTokens(ctx context.Context, userID uint, sessionID string, until time.Time) error
+
+	// ConsumeToken 将一次性令牌（如 mfa_pending）标记为已使用，令牌已被使用或吊销时返回 false
+	ConsumeToken(ctx context.Context, claims *JWTClaims) (bool, error)
+
+	// RevokeUserTokens 吊销用户在 before 及之前签发的全部访问令牌，并注销此前登录的会话（刷新令牌）。
+	// before 为零值时使用当前时间，返回生效后的吊销时间点。
+	RevokeUserTokens(ctx context.Context, userID uint, before time.Time) (*TokenRevocation, error)
+}
+
+// TokenRevocation 是吊销用户令牌的结果
+type TokenRevocation struct {
+	UserID          uint      `json:"user_id"`
+	RevokedBefore   time.Time `json:"revoked_before"`
+	RevokedSessions int64     `json:"revoked_sessions"`
+}
+
+// revocationCacheEntry 是吊销查询结果的缓存条目
+type revocationCacheEntry struct {
+	revoked   bool      // jti 缓存：是否已吊销
+	before    time.Time // 用户缓存：吊销时间点，零值表示未设置
+	expiresAt time.Time // 条目失效时间
+}
+
+// tokenRevocationService 实现了 TokenRevocationService 接口。
+// 吊销记录保存在数据库中，查询结果缓存在进程内 LRU：已吊销的 jti 缓存至令牌过期，
+// 其余结果缓存 cacheTTL，即其他实例上的吊销在本实例最多延迟 cacheTTL 生效。
+type tokenRevocationService struct {
+	store        TokenRevocationStore
+	sessionStore SessionStore
+	userStore    UserStore
+
+	tokenCache *lru.Cache[string, revocationCacheEntry]
+	userCache  *lru.Cache[uint, revocationCacheEntry]
+	cacheTTL   time.Duration
+}
+
+var _ TokenRevocationService = (*tokenRevocationService)(nil)
+
+// NewTokenRevocationService 创建并返回一个新的 TokenRevocationService 实例
+func NewTokenRevocationService(
+	store TokenRevocationStore,
+	sessionStore SessionStore,
+	userStore UserStore,
+	cfg *config.Config,
+) (TokenRevocationService, error) {
+	cacheTTL, err := parseDurationOrDefault(cfg.TokenRevocation.CacheTTL, defaultRevocationCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token_revocation cache_ttl: %w", err)
+	}
+
+	cacheSize := cfg.TokenRevocation.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = defaultRevocationCacheSize
+	}
+
+	return &tokenRevocationService{
+		store:        store,
+		sessionStore: sessionStore,
+		userStore:    userStore,
+		tokenCache:   lru.NewCache[string, revocationCacheEntry](cacheSize),
+		userCache:    lru.NewCache[uint, revocationCacheEntry](cacheSize),
+		cacheTTL:     cacheTTL,
+	}, nil
+}
+
+// IsRevoked implements TokenDenylist.
+// 令牌被单独吊销、所属会话已被注销，或签发时间不晚于用户的吊销时间点时视为已吊销。
+func (s *tokenRevocationService) IsRevoked(ctx context.Context, claims *JWTClaims) (bool, error) {
+	now := time.Now()
+
+	before, err := s.userRevokedBefore(ctx, claims.UserID, now)
+	if err != nil {
+		return false, err
+	}
+	// iat 精度为秒，与时间点同一秒内签发的令牌同样视为已吊销
+	if !before.IsZero() && !claims.IssuedAt.After(before) {
+		return true, nil
+	}
+
+	// 会话被注销后，携带该会话 ID 的访问令牌一并失效
+	if claims.SessionID != "" {
+		revoked, err := s.isKeyRevoked(ctx, sessionRevocationPrefix+claims.SessionID, claims.ExpiresAt, now)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	// 未携带 jti 的令牌只能按用户时间点或会话吊销
+	if claims.TokenID == "" {
+		return false, nil
+	}
+
+	return s.isKeyRevoked(ctx, claims.TokenID, claims.ExpiresAt, now)
+}
+
+// RevokeToken implements TokenRevocationService.
+func (s *tokenRevocationService) RevokeToken(ctx context.Context, claims *JWTClaims) error {
+	if claims.TokenID == "" {
+		return nil
+	}
+
+	if _, err := s.revoke(ctx, claims); err != nil {
+		return err
+	}
+
+	logger.Logger.Info("Access token revoked",
+		zap.Uint("user_id", claims.UserID),
+		zap.String("jti", claims.TokenID),
+	)
+
+	return nil
+}
+
+// RevokeSessionTokens implements TokenRevocationService.
+func (s *tokenRevocationService) RevokeSessionTokens(ctx context.Context, userID uint, sessionID string, until time.Time) error {
+	key := sessionRevocationPrefix + sessionID
+
+	if _, err := s.store.RevokeToken(ctx, &model.RevokedToken{
+		JTI:       key,
+		UserID:    userID,
+		ExpiresAt: until,
+	}); err != nil {
+		return err
+	}
+	s.cacheKey(key, true, until, time.Now())
+
+	logger.Logger.Info("Session access tokens revoked",
+		zap.Uint("user_id", userID),
+		zap.String("session_id", sessionID),
+	)
+
+	return nil
+}
+
+// ConsumeToken implements TokenRevocationService.
+// 先检查用户吊销时间点，再原子地写入吊销记录，并发提交同一令牌时只有一个请求成功。
+func (s *tokenRevocationService) ConsumeToken(ctx context.Context, claims *JWTClaims) (bool, error) {
+	if claims.TokenID == "" {
+		return false, nil
+	}
+
+	before, err := s.userRevokedBefore(ctx, claims.UserID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if !before.IsZero() && !claims.IssuedAt.After(before) {
+		return false, nil
+	}
+
+	return s.revoke(ctx, claims)
+}
+
+// revoke 写入 jti 吊销记录并更新缓存，令牌此前已被吊销时返回 false
+func (s *tokenRevocationService) revoke(ctx context.Context, claims *JWTClaims) (bool, error) {
+	now := time.Now()
+
+	// 清理已过期的吊销记录，失败不影响吊销
+	if _, err := s.store.DeleteExpiredRevokedTokens(ctx, now); err != nil {
+		logger.Logger.Warn("Failed to purge expired revoked tokens", zap.Error(err))
+	}
+
+	inserted, err := s.store.RevokeToken(ctx, &model.RevokedToken{
+		JTI:       claims.TokenID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	s.cacheKey(claims.TokenID, true, claims.ExpiresAt, now)
+
+	return inserted, nil
+}
+
+// RevokeUserTokens implements TokenRevocationService.
+func (s *tokenRevocationService) RevokeUserTokens(ctx context.Context, userID uint, before time.Time) (*TokenRevocation, error) {
+	now := time.Now()
+	if before.IsZero() {
+		before = now
+	}
+	// 未来的时间点会使用户在此之前无法登录，不允许
+	if before.After(now) {
+		return nil, ErrRevocationTimeInFuture
+	}
+
+	if _, err := s.userStore.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+
+	effective, err := s.store.RevokeUserTokensBefore(ctx, userID, before)
+	if err != nil {
+		return nil, err
+	}
+	s.userCache.Add(userID, revocationCacheEntry{before: effective, expiresAt: now.Add(s.cacheTTL)})
+
+	// 访问令牌失效后，同时注销此前登录的会话，避免通过刷新令牌换取新的访问令牌
+	sessions, err := s.sessionStore.RevokeSessionsStartedBefore(ctx, userID, before, now)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("User tokens revoked",
+		zap.Uint("user_id", userID),
+		zap.Time("revoked_before", effective),
+		zap.Int64("revoked_sessions", sessions),
+	)
+
+	return &TokenRevocation{
+		UserID:          userID,
+		RevokedBefore:   effective,
+		RevokedSessions: sessions,
+	}, nil
+}
+
+// userRevokedBefore 返回用户的令牌吊销时间点（优先读取缓存），未设置时返回零值
+func (s *tokenRevocationService) userRevokedBefore(ctx context.Context, userID uint, now time.Time) (time.Time, error) {
+	if entry, ok := s.userCache.Get(userID); ok && now.Before(entry.expiresAt) {
+		return entry.before, nil
+	}
+
+	before, err := s.store.GetUserTokensRevokedBefore(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	entry := revocationCacheEntry{expiresAt: now.Add(s.cacheTTL)}
+	if before != nil {
+		entry.before = *before
+	}
+	s.userCache.Add(userID, entry)
+
+	return entry.before, nil
+}
+
+// isKeyRevoked 查询 jti 或会话吊销键是否已有吊销记录（优先读取缓存）
+func (s *tokenRevocationService) isKeyRevoked(ctx context.Context, key string, tokenExpiresAt time.Time, now time.Time) (bool, error) {
+	if entry, ok := s.tokenCache.Get(key); ok && now.Before(entry.expiresAt) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := s.store.IsTokenRevoked(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	s.cacheKey(key, revoked, tokenExpiresAt, now)
+
+	return revoked, nil
+}
+
+// cacheKey 缓存 jti 或会话吊销键的吊销状态：已吊销的结果缓存至 revokedUntil，否则缓存 cacheTTL
+func (s *tokenRevocationService) cacheKey(key string, revoked bool, revokedUntil time.Time, now time.Time) {
+	expiresAt := now.Add(s.cacheTTL)
+	if revoked && revokedUntil.After(expiresAt) {
+		expiresAt = revokedUntil
+	}
+	s.tokenCache.Add(key, revocationCacheEntry{revoked: revoked, expiresAt: expiresAt})
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// memoryRevocationStore 以 map 保存吊销记录，未设置用户吊销时间点
+type memoryRevocationStore struct {
+	revoked map[string]model.RevokedToken
+}
+
+func (m *memoryRevocationStore) RevokeToken(_ context.Context, token *model.RevokedToken) (bool, error) {
+	if _, ok := m.revoked[token.JTI]; ok {
+		return false, nil
+	}
+	m.revoked[token.JTI] = *token
+	return true, nil
+}
+
+func (m *memoryRevocationStore) IsTokenRevoked(_ context.Context, jti string) (bool, error) {
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *memoryRevocationStore) DeleteExpiredRevokedTokens(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryRevocationStore) RevokeUserTokensBefore(_ context.Context, _ uint, before time.Time) (time.Time, error) {
+	return before, nil
+}
+
+func (m *memoryRevocationStore) GetUserTokensRevokedBefore(context.Context, uint) (*time.Time, error) {
+	return nil, nil
+}
+
+func TestRevokeSessionTokens(t *testing.T) {
+	store := &memoryRevocationStore{revoked: make(map[string]model.RevokedToken)}
+	s, err := NewTokenRevocationService(store, nil, nil, &config.Config{})
+	if err != nil {
+		t.Fatalf("NewTokenRevocationService: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	claims := func(sessionID string) *JWTClaims {
+		return &JWTClaims{
+			UserID:    1,
+			SessionID: sessionID,
+			TokenID:   sessionID + "-jti",
+			IssuedAt:  now.Add(-time.Minute),
+			ExpiresAt: now.Add(14 * time.Minute),
+		}
+	}
+
+	// 先查询一次，确认未吊销的缓存结果不会掩盖随后的吊销
+	if revoked, err := s.IsRevoked(ctx, claims("session-a")); err != nil || revoked {
+		t.Fatalf("IsRevoked before revocation = %v, %v", revoked, err)
+	}
+
+	if err := s.RevokeSessionTokens(ctx, 1, "session-a", now.Add(15*time.Minute)); err != nil {
+		t.Fatalf("RevokeSessionTokens: %v", err)
+	}
+
+	if revoked, err := s.IsRevoked(ctx, claims("session-a")); err != nil || !revoked {
+		t.Errorf("IsRevoked for revoked session = %v, %v, want true", revoked, err)
+	}
+	if revoked, err := s.IsRevoked(ctx, claims("session-b")); err != nil || revoked {
+		t.Errorf("IsRevoked for other session = %v, %v, want false", revoked, err)
+	}
+	if _, ok := store.revoked[sessionRevocationPrefix+"session-a"]; !ok {
+		t.Errorf("session revocation not persisted: %v", store.revoked)
+	}
+}
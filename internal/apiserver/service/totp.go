@@ -0,0 +1,557 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/totp"
+)
+
+const (
+	defaultTOTPIssuer  = "Web3 Wallet"
+	defaultMFATokenTTL = 5 * time.Minute
+
+	// totpSkew 允许前后各一个时间步 (±30s) 的时钟偏差
+	totpSkew = 1
+
+	// recoveryCodeCount 是每次生成的恢复码数量
+	recoveryCodeCount = 10
+
+	// recoveryCodeBytes 生成 12 位 Base32 恢复码 (60 bit)，展示为 xxxx-xxxx-xxxx
+	recoveryCodeBytes = 8
+	recoveryCodeChars = 12
+)
+
+var (
+	ErrTOTPAlreadyEnrolled = errors.New("totp is already enrolled")
+	ErrTOTPNotEnrolled     = errors.New("totp is not enrolled")
+	ErrMFATokenInvalid     = errors.New("mfa token is invalid, expired or already used")
+)
+
+// recoveryCodeEncoding 是恢复码使用的小写无填充 Base32 编码
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// TOTPStore 定义了 TOTP 密钥与恢复码的存储接口
+type TOTPStore interface {
+	// GetTOTP 查找用户的 TOTP 记录（含未确认的记录），不存在时返回 nil, nil
+	GetTOTP(ctx context.Context, userID uint) (*model.UserTOTP, error)
+
+	// SavePendingTOTP 用新的未确认记录替换用户未确认的记录，用户已确认绑定时返回 false
+	SavePendingTOTP(ctx context.Context, record *model.UserTOTP) (bool, error)
+
+	// ConfirmTOTP 确认绑定、记录已使用的时间步并写入恢复码，记录不存在或已确认时返回 false
+	ConfirmTOTP(ctx context.Context, userID uint, step int64, codeHashes []string, now time.Time) (bool, error)
+
+	// UseTOTPStep 原子地记录已使用的时间步，step 不大于上次使用的时间步（重放）时返回 false
+	UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
+
+	// UseRecoveryCode 原子地将未使用的恢复码标记为已使用，不存在或已使用时返回 false
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string, now time.Time) (bool, error)
+
+	// ReplaceRecoveryCodes 删除用户现有的全部恢复码并写入新的恢复码
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+
+	// DeleteTOTP 删除用户的 TOTP 记录及全部恢复码
+	DeleteTOTP(ctx context.Context, userID uint) error
+
+	// ListTOTPs 按 ID 升序分页列出 TOTP 记录，供主密钥轮换使用
+	ListTOTPs(ctx context.Context, afterID uint, limit int) ([]*model.UserTOTP, error)
+
+	// UpdateTOTPEnvelope 以原 master_key_id 为条件更新信封字段，记录已被并发修改时返回 false
+	UpdateTOTPEnvelope(ctx context.Context, record *model.UserTOTP, previousKeyID string) (bool, error)
+}
+
+// TOTPService 定义了 TOTP (RFC 6238) 第二因子的业务接口，同时作为敏感操作的 SecondFactorVerifier。
+// 验证码可以是 6 位 TOTP，也可以是一次性恢复码。
+type TOTPService interface {
+	SecondFactorVerifier
+
+	// Enroll 生成新的 TOTP 密钥，用户提交验证码确认前不生效；重复调用会替换未确认的密钥
+	Enroll(ctx context.Context, userID uint) (*TOTPEnrollment, error)
+
+	// Confirm 使用验证码确认绑定，返回仅展示一次的恢复码
+	Confirm(ctx context.Context, userID uint, code string) ([]string, error)
+
+	// Disable 校验账户密码与验证码后解除绑定，并删除全部恢复码
+	Disable(ctx context.Context, userID uint, password string, code string) error
+
+	// RegenerateRecoveryCodes 校验账户密码与验证码后重新生成恢复码，旧恢复码全部失效
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, password string, code string) ([]string, error)
+
+	// BeginLogin 在用户通过第一因子后调用：已绑定 TOTP 时签发 mfa_pending 令牌，未绑定时返回 nil
+	BeginLogin(ctx context.Context, user *model.User) (*MFAChallenge, error)
+
+	// CompleteLogin 校验 mfa_pending 令牌与验证码，返回登录用户。令牌只能使用一次，验证失败需重新登录
+	CompleteLogin(ctx context.Context, mfaToken string, code string) (*model.User, error)
+}
+
+// TOTPEnrollment 是待确认的 TOTP 绑定信息
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`      // Base32 密钥，供无法扫码时手动输入
+	URI    string `json:"otpauth_uri"` // otpauth:// URI，可渲染为二维码
+}
+
+// MFAChallenge 是登录第二步所需的 mfa_pending 令牌
+type MFAChallenge struct {
+	MFAToken  string
+	ExpiresIn int64 // 有效期（秒）
+}
+
+// totpService 实现了 TOTPService 接口
+type totpService struct {
+	store             TOTPStore
+	userStore         UserStore
+	jwtService        JWTService
+	revocationService TokenRevocationService
+	cipher            crypto.EnvelopeCipher // 为 nil 时 TOTP 密钥不做信封加密
+	limiter           AttemptLimiter        // 按用户限制验证码与账户密码的错误尝试
+
+	issuer      string
+	mfaTokenTTL time.Duration
+}
+
+var _ TOTPService = (*totpService)(nil)
+
+// NewTOTPService 创建并返回一个新的 TOTPService 实例
+func NewTOTPService(
+	store TOTPStore,
+	userStore UserStore,
+	jwtService JWTService,
+	revocationService TokenRevocationService,
+	cipher crypto.EnvelopeCipher,
+	limiter AttemptLimiter,
+	cfg *config.Config,
+) (TOTPService, error) {
+	mfaTokenTTL, err := parseDurationOrDefault(cfg.TOTP.MFATokenTTL, defaultMFATokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid totp mfa_token_ttl: %w", err)
+	}
+
+	issuer := cfg.TOTP.Issuer
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+
+	return &totpService{
+		store:             store,
+		userStore:         userStore,
+		jwtService:        jwtService,
+		revocationService: revocationService,
+		cipher:            cipher,
+		limiter:           limiter,
+		issuer:            issuer,
+		mfaTokenTTL:       mfaTokenTTL,
+	}, nil
+}
+
+// Enrolled implements SecondFactorVerifier.
+func (s *totpService) Enrolled(ctx context.Context, userID uint) (bool, error) {
+	record, err := s.store.GetTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return record != nil && record.ConfirmedAt != nil, nil
+}
+
+// Verify implements SecondFactorVerifier.
+// 6 位数字按 TOTP 校验（同一时间步只能使用一次），其余按恢复码校验（每个只能使用一次）。
+// 校验前按用户计入一次尝试 (RFC 6238 §5.2)，连续失败时退避或锁定，返回 *AttemptLockedError。
+func (s *totpService) Verify(ctx context.Context, userID uint, code string) error {
+	record, err := s.store.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if record == nil || record.ConfirmedAt == nil {
+		return ErrSecondFactorInvalid
+	}
+
+	subject := userAttemptSubject(userID)
+	if err := s.limiter.Reserve(ctx, model.AttemptKindTOTP, subject); err != nil {
+		return err
+	}
+	if err := s.verifyCode(ctx, record, code); err != nil {
+		return err
+	}
+	s.limiter.Succeed(ctx, model.AttemptKindTOTP, subject)
+
+	return nil
+}
+
+// verifyCode 校验已确认绑定用户的 TOTP 验证码或恢复码，并将其标记为已使用
+func (s *totpService) verifyCode(ctx context.Context, record *model.UserTOTP, code string) error {
+	userID := record.UserID
+
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		step, err := s.validateCode(ctx, record, code)
+		if err != nil {
+			return err
+		}
+
+		used, err := s.store.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			logger.Logger.Warn("TOTP code replay rejected", zap.Uint("user_id", userID))
+			return ErrSecondFactorInvalid
+		}
+		return nil
+	}
+
+	used, err := s.store.UseRecoveryCode(ctx, userID, hashRecoveryCode(userID, code), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrSecondFactorInvalid
+	}
+
+	logger.Logger.Info("Recovery code used", zap.Uint("user_id", userID))
+	return nil
+}
+
+// Enroll implements TOTPService.
+func (s *totpService) Enroll(ctx context.Context, userID uint) (*TOTPEnrollment, error) {
+	user, err := s.userStore.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+
+	enrolled, err := s.Enrolled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &model.UserTOTP{UserID: userID}
+	if err := s.sealSecret(ctx, record, secret); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.store.SavePendingTOTP(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.issuer, user.Username, secret),
+	}, nil
+}
+
+// Confirm implements TOTPService.
+func (s *totpService) Confirm(ctx context.Context, userID uint, code string) ([]string, error) {
+	record, err := s.store.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrTOTPNotEnrolled
+	}
+	if record.ConfirmedAt != nil {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	subject := userAttemptSubject(userID)
+	if err := s.limiter.Reserve(ctx, model.AttemptKindTOTP, subject); err != nil {
+		return nil, err
+	}
+	step, err := s.validateCode(ctx, record, strings.TrimSpace(code))
+	if err != nil {
+		return nil, err
+	}
+	s.limiter.Succeed(ctx, model.AttemptKindTOTP, subject)
+
+	codes, hashes, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed, err := s.store.ConfirmTOTP(ctx, userID, step, hashes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	logger.Logger.Info("TOTP enrolled", zap.Uint("user_id", userID))
+	return codes, nil
+}
+
+// Disable implements TOTPService.
+// 仅凭访问令牌与验证码不足以解除绑定，还需校验账户密码。
+func (s *totpService) Disable(ctx context.Context, userID uint, password string, code string) error {
+	record, err := s.store.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrTOTPNotEnrolled
+	}
+
+	if err := s.checkPassword(ctx, userID, password); err != nil {
+		return err
+	}
+
+	// 未确认的绑定不要求验证码
+	if record.ConfirmedAt != nil {
+		if err := s.Verify(ctx, userID, code); err != nil {
+			return err
+		}
+	}
+
+	if err := s.store.DeleteTOTP(ctx, userID); err != nil {
+		return err
+	}
+
+	logger.Logger.Info("TOTP disabled", zap.Uint("user_id", userID))
+	return nil
+}
+
+// RegenerateRecoveryCodes implements TOTPService.
+func (s *totpService) RegenerateRecoveryCodes(ctx context.Context, userID uint, password string, code string) ([]string, error) {
+	enrolled, err := s.Enrolled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	if err := s.checkPassword(ctx, userID, password); err != nil {
+		return nil, err
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Recovery codes regenerated", zap.Uint("user_id", userID))
+	return codes, nil
+}
+
+// BeginLogin implements TOTPService.
+func (s *totpService) BeginLogin(ctx context.Context, user *model.User) (*MFAChallenge, error) {
+	enrolled, err := s.Enrolled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, nil
+	}
+
+	token, err := s.jwtService.GenerateMFAToken(user, s.mfaTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAChallenge{
+		MFAToken:  token,
+		ExpiresIn: int64(s.mfaTokenTTL.Seconds()),
+	}, nil
+}
+
+// CompleteLogin implements TOTPService.
+// 令牌在校验验证码之前即被消费，避免在有效期内用同一令牌暴力尝试验证码。
+func (s *totpService) CompleteLogin(ctx context.Context, mfaToken string, code string) (*model.User, error) {
+	claims, err := s.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, ErrMFATokenInvalid
+	}
+
+	consumed, err := s.revocationService.ConsumeToken(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrMFATokenInvalid
+	}
+
+	if err := s.Verify(ctx, claims.UserID, code); err != nil {
+		if errors.Is(err, ErrSecondFactorInvalid) {
+			logger.Logger.Warn("MFA login failed", zap.Uint("user_id", claims.UserID))
+		}
+		return nil, err
+	}
+
+	user, err := s.userStore.FindByID(claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFATokenInvalid
+		}
+		return nil, fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	return user, nil
+}
+
+// checkPassword 校验账户密码，与登录共用同一用户名的失败计数
+func (s *totpService) checkPassword(ctx context.Context, userID uint, password string) error {
+	user, err := s.userStore.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("%w: failed to load user: %w", ErrStoreOperationFailed, err)
+	}
+
+	if user.PasswordHash == "" {
+		return ErrPasswordNotSet
+	}
+
+	if err := s.limiter.Reserve(ctx, model.AttemptKindLogin, user.Username); err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	s.limiter.Succeed(ctx, model.AttemptKindLogin, user.Username)
+
+	return nil
+}
+
+// validateCode 校验 TOTP 验证码，返回匹配的时间步
+func (s *totpService) validateCode(ctx context.Context, record *model.UserTOTP, code string) (int64, error) {
+	if !isTOTPCode(code) {
+		return 0, ErrSecondFactorInvalid
+	}
+
+	secret, err := s.openSecret(ctx, record)
+	if err != nil {
+		return 0, err
+	}
+
+	step, ok, err := totp.Validate(secret, code, time.Now(), totpSkew)
+	if err != nil {
+		return 0, fmt.Errorf("failed to validate totp code: %w", err)
+	}
+	if !ok || step <= record.LastUsedStep {
+		return 0, ErrSecondFactorInvalid
+	}
+
+	return step, nil
+}
+
+// sealSecret 对 TOTP 密钥做信封加密并写入 record；cipher 为 nil 时原样保存
+func (s *totpService) sealSecret(ctx context.Context, record *model.UserTOTP, secret string) error {
+	if s.cipher == nil {
+		record.Secret = secret
+		return nil
+	}
+
+	envelope, err := s.cipher.Seal(ctx, []byte(secret))
+	if err != nil {
+		return fmt.Errorf("failed to seal totp secret: %w", err)
+	}
+
+	record.Secret = envelope.Ciphertext
+	record.WrappedDataKey = envelope.WrappedKey
+	record.MasterKeyID = envelope.KeyID
+	return nil
+}
+
+// openSecret 返回 TOTP 密钥明文，未做信封加密的记录直接返回 Secret
+func (s *totpService) openSecret(ctx context.Context, record *model.UserTOTP) (string, error) {
+	if record.MasterKeyID == "" {
+		return record.Secret, nil
+	}
+	if s.cipher == nil {
+		return "", fmt.Errorf("totp secret of user %d is envelope-encrypted but key management is not configured", record.UserID)
+	}
+
+	secret, err := s.cipher.Open(ctx, totpEnvelope(record))
+	if err != nil {
+		return "", fmt.Errorf("failed to open totp secret for user %d: %w", record.UserID, err)
+	}
+
+	return string(secret), nil
+}
+
+// totpEnvelope 从 TOTP 记录组装信封
+func totpEnvelope(record *model.UserTOTP) *crypto.Envelope {
+	return &crypto.Envelope{
+		Ciphertext: record.Secret,
+		WrappedKey: record.WrappedDataKey,
+		KeyID:      record.MasterKeyID,
+	}
+}
+
+// isTOTPCode 判断 code 是否为 6 位数字验证码
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRecoveryCodes 生成一组恢复码，返回展示给用户的明文与入库的哈希
+func newRecoveryCodes(userID uint) ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	buf := make([]byte, recoveryCodeBytes)
+	for range recoveryCodeCount {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		raw := recoveryCodeEncoding.EncodeToString(buf)[:recoveryCodeChars]
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12])
+		hashes = append(hashes, hashRecoveryCode(userID, raw))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 计算恢复码的 SHA-256（忽略大小写与分隔符），以用户 ID 为前缀避免跨用户碰撞
+func hashRecoveryCode(userID uint, code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, normalized)))
+	return hex.EncodeToString(sum[:])
+}
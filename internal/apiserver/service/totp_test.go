@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/totp"
+)
+
+func TestValidateCodeRejectsReplay(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	s := &totpService{}
+	record := &model.UserTOTP{UserID: 1, Secret: secret}
+
+	current := totp.Step(time.Now())
+	code, err := totp.Code(secret, current)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	step, err := s.validateCode(context.Background(), record, code)
+	if err != nil {
+		t.Fatalf("first validateCode: %v", err)
+	}
+	if step != current {
+		t.Fatalf("validateCode step = %d, want %d", step, current)
+	}
+
+	// 使用成功后记录时间步，同一验证码及更早时间步的验证码均被拒绝
+	record.LastUsedStep = step
+	if _, err := s.validateCode(context.Background(), record, code); !errors.Is(err, ErrSecondFactorInvalid) {
+		t.Errorf("replayed validateCode error = %v, want ErrSecondFactorInvalid", err)
+	}
+
+	previous, err := totp.Code(secret, current-1)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if _, err := s.validateCode(context.Background(), record, previous); !errors.Is(err, ErrSecondFactorInvalid) {
+		t.Errorf("earlier step validateCode error = %v, want ErrSecondFactorInvalid", err)
+	}
+}
+
+func TestValidateCodeRejectsMalformedCode(t *testing.T) {
+	s := &totpService{}
+	record := &model.UserTOTP{UserID: 1, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"}
+
+	for _, code := range []string{"", "12345", "1234567", "12a456"} {
+		if _, err := s.validateCode(context.Background(), record, code); !errors.Is(err, ErrSecondFactorInvalid) {
+			t.Errorf("validateCode(%q) error = %v, want ErrSecondFactorInvalid", code, err)
+		}
+	}
+}
+
+// fakeTOTPStore 只实现 Verify 使用的 TOTPStore 方法
+type fakeTOTPStore struct {
+	TOTPStore
+
+	record *model.UserTOTP
+}
+
+func (f *fakeTOTPStore) GetTOTP(context.Context, uint) (*model.UserTOTP, error) {
+	return f.record, nil
+}
+
+func (f *fakeTOTPStore) UseTOTPStep(_ context.Context, _ uint, step int64) (bool, error) {
+	if step <= f.record.LastUsedStep {
+		return false, nil
+	}
+	f.record.LastUsedStep = step
+	return true, nil
+}
+
+func (f *fakeTOTPStore) UseRecoveryCode(context.Context, uint, string, time.Time) (bool, error) {
+	return false, nil
+}
+
+func TestVerifyThrottlesFailedCodes(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	limiter, err := NewAttemptLimiter(newMemoryAttemptStore(), config.LockoutConfig{})
+	if err != nil {
+		t.Fatalf("NewAttemptLimiter: %v", err)
+	}
+
+	confirmedAt := time.Now()
+	s := &totpService{
+		store:   &fakeTOTPStore{record: &model.UserTOTP{UserID: 1, Secret: secret, ConfirmedAt: &confirmedAt}},
+		limiter: limiter,
+	}
+	ctx := context.Background()
+
+	// 验证码与恢复码的错误尝试共用同一计数
+	for _, code := range []string{"000000", "wrong-recovery-code", "999999"} {
+		if err := s.Verify(ctx, 1, code); !errors.Is(err, ErrSecondFactorInvalid) {
+			t.Fatalf("Verify(%q) error = %v, want ErrSecondFactorInvalid", code, err)
+		}
+	}
+
+	// 进入退避期后，正确的验证码同样被拒绝
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if err := s.Verify(ctx, 1, code); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Verify during backoff error = %v, want ErrTooManyAttempts", err)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+const (
+	defaultTxPageSize = 20
+	maxTxPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor   = errors.New("invalid pagination cursor")
+	ErrInvalidTxFilter = errors.New("invalid transaction filter")
+
+	// 加速 / 取消交易专用错误
+	ErrTxNotFound             = errors.New("transaction not found or insufficient permission")
+	ErrTxNotReplaceable       = errors.New("transaction is no longer pending or has already been replaced")
+	ErrReplacementUnderpriced = errors.New("replacement fee must exceed the original by at least 10%")
+)
+
+// TransactionStore 定义了交易记录存储的接口 (DIP: 由 service 层定义)
+type TransactionStore interface {
+	// CreateTransaction 写入一笔新广播的交易
+	CreateTransaction(ctx context.Context, tx *model.Transaction) error
+
+	// GetTransactionByHash 根据交易哈希查询，不存在时返回 nil, nil
+	GetTransactionByHash(ctx context.Context, hash string) (*model.Transaction, error)
+
+	// LinkReplacement 记录原交易已被 replacementHash 替换
+	LinkReplacement(ctx context.Context, originalID uint, replacementHash string) error
+
+	// ListTransactions 按 ID 倒序分页查询交易
+	ListTransactions(ctx context.Context, filter *TransactionFilter) ([]model.Transaction, error)
+
+	// ListTrackableTransactions 按 ID 正序返回 ID 大于 afterID 且尚未最终确认的交易 (pending / mined)
+	ListTrackableTransactions(ctx context.Context, afterID uint, limit int) ([]model.Transaction, error)
+
+	// UpdateTransactionStatus 更新交易状态及回执字段
+	UpdateTransactionStatus(ctx context.Context, tx *model.Transaction) error
+}
+
+// TransactionFilter 定义了 store 层的交易查询条件
+type TransactionFilter struct {
+	Address  string     // 匹配 from_address 或 to_address
+	ChainID  uint       // 0 表示不限
+	Status   string     // 空表示不限
+	Since    *time.Time // created_at >= Since
+	Until    *time.Time // created_at < Until
+	BeforeID uint       // 游标：只返回 id < BeforeID 的记录，0 表示从最新开始
+	Limit    int
+}
+
+// TransactionQuery 定义了交易列表接口的查询参数
+type TransactionQuery struct {
+	ChainID uint
+	Status  string
+	Since   *time.Time
+	Until   *time.Time
+	Cursor  string // 上一页返回的 next_cursor
+	Limit   int
+}
+
+// TransactionPage 定义了一页交易查询结果
+type TransactionPage struct {
+	Items      []model.Transaction `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"` // 为空表示没有更多数据
+}
+
+// encodeIDCursor 将记录 ID 编码为不透明的分页游标
+func encodeIDCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// decodeIDCursor 解析分页游标，空字符串返回 0
+func decodeIDCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return uint(id), nil
+}
+
+// buildTxFilter 校验查询参数并转换为 store 层的查询条件
+func buildTxFilter(address string, query *TransactionQuery) (*TransactionFilter, error) {
+	if query.Status != "" && !model.TxStatuses[query.Status] {
+		return nil, ErrInvalidTxFilter
+	}
+	if query.Since != nil && query.Until != nil && !query.Since.Before(*query.Until) {
+		return nil, ErrInvalidTxFilter
+	}
+
+	beforeID, err := decodeIDCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTxPageSize
+	}
+	if limit > maxTxPageSize {
+		limit = maxTxPageSize
+	}
+
+	return &TransactionFilter{
+		Address:  address,
+		ChainID:  query.ChainID,
+		Status:   query.Status,
+		Since:    query.Since,
+		Until:    query.Until,
+		BeforeID: beforeID,
+		Limit:    limit,
+	}, nil
+}
+
+// tokenTransfer 描述一笔 ERC-20 转账在交易历史中的展示信息
+type tokenTransfer struct {
+	token     common.Address
+	recipient common.Address
+	amount    *big.Int // 代币最小单位
+}
+
+// newTransactionRecord 根据已签名交易构建一条 pending 状态的交易历史记录。
+// transfer 非空时，to_address / value 记录代币收款方与代币金额，而非交易的 to（代币合约）与原生币金额。
+func newTransactionRecord(
+	userID uint,
+	chainID uint,
+	from common.Address,
+	signedTx *types.Transaction,
+	transfer *tokenTransfer,
+) (*model.Transaction, error) {
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+
+	record := &model.Transaction{
+		UserID:      userID,
+		ChainID:     chainID,
+		FromAddress: from.Hex(),
+		ToAddress:   signedTx.To().Hex(),
+		Value:       signedTx.Value().String(),
+		Nonce:       signedTx.Nonce(),
+		GasLimit:    signedTx.Gas(),
+		Hash:        signedTx.Hash().Hex(),
+		RawTx:       hexutil.Encode(raw),
+		Status:      model.TxStatusPending,
+	}
+
+	if transfer != nil {
+		tokenAddress := transfer.token.Hex()
+		record.TokenAddress = &tokenAddress
+		record.ToAddress = transfer.recipient.Hex()
+		record.Value = transfer.amount.String()
+	}
+
+	if signedTx.Type() == types.LegacyTxType {
+		gasPrice := signedTx.GasPrice().String()
+		record.GasPrice = &gasPrice
+	} else {
+		maxFee := signedTx.GasFeeCap().String()
+		maxPriorityFee := signedTx.GasTipCap().String()
+		record.MaxFeePerGas = &maxFee
+		record.MaxPriorityFeePerGas = &maxPriorityFee
+	}
+
+	return record, nil
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -14,12 +17,14 @@ import (
 
 var (
 	ErrUserAlreadyExists  = errors.New("username already exists")
+	ErrUsernameReserved   = errors.New("ethereum address usernames are reserved for sign-in with ethereum")
 	ErrInvalidCredentials = errors.New("invalid username or password")
 
 	ErrStoreOperationFailed = errors.New("store operation failed")
 	ErrPasswordHashFailed   = errors.New("password hashing failed")
 
 	ErrUserNotFound = errors.New("user not found")
+	ErrUserDisabled = errors.New("user account is disabled")
 )
 
 // UserStore 定义了用户数据访问的接口（在 service 层定义，由 store 层实现）
@@ -27,32 +32,60 @@ type UserStore interface {
 	CreateUser(user *model.User) error
 	FindByUsername(username string) (*model.User, error)
 	FindByID(id uint) (*model.User, error)
+
+	// FindByEthereumAddress 通过 SIWE 关联的以太坊地址查找用户
+	FindByEthereumAddress(address string) (*model.User, error)
+
+	// LinkEthereumAddress 为尚未关联地址的用户关联以太坊地址，用户已关联其他地址时返回 false
+	LinkEthereumAddress(userID uint, address string) (bool, error)
+
+	// ListUsers 按 ID 倒序分页查询用户
+	ListUsers(ctx context.Context, filter *UserFilter) ([]model.User, error)
+
+	// UpdateUserRole 更新用户角色，用户不存在时返回 false
+	UpdateUserRole(ctx context.Context, userID uint, role string) (bool, error)
+
+	// SetUserDisabledAt 设置用户停用时间，disabledAt 为 nil 表示启用，用户不存在时返回 false
+	SetUserDisabledAt(ctx context.Context, userID uint, disabledAt *time.Time) (bool, error)
+
+	// UpdatePasswordHash 更新用户的密码哈希，用户不存在时返回 false
+	UpdatePasswordHash(ctx context.Context, userID uint, passwordHash string) (bool, error)
+
+	// SetInitialPasswordHash 仅在用户尚无密码时设置密码哈希，用户不存在或已设置密码时返回 false
+	SetInitialPasswordHash(ctx context.Context, userID uint, passwordHash string) (bool, error)
 }
 
 // UserService 定义了用户相关的业务逻辑接口
 type UserService interface {
 	Register(username string, password string) (*model.User, error)
-	Login(username string, password string) (string, error)
+
+	// Login 校验用户名和密码，同一用户名连续失败时按 AttemptLimiter 退避或锁定，返回 *AttemptLockedError
+	Login(ctx context.Context, username string, password string) (*model.User, error)
 	FindUserByUsername(username string) (*model.User, error)
 }
 
 type userService struct {
-	userStore  UserStore
-	jwtService JWTService
+	userStore UserStore
+	limiter   AttemptLimiter
 }
 
 var _ UserService = (*userService)(nil)
 
 // NewUserService 创建并返回新的 UserService 实例（依赖注入）
-func NewUserService(userStore UserStore, jwtService JWTService) UserService {
+func NewUserService(userStore UserStore, limiter AttemptLimiter) UserService {
 	return &userService{
-		userStore:  userStore,
-		jwtService: jwtService,
+		userStore: userStore,
+		limiter:   limiter,
 	}
 }
 
 // Register 处理用户注册业务逻辑
 func (s *userService) Register(username string, password string) (*model.User, error) {
+	// 地址格式的用户名保留给 SIWE 自动创建的用户
+	if common.IsHexAddress(username) {
+		return nil, ErrUsernameReserved
+	}
+
 	_, err := s.userStore.FindByUsername(username)
 
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -77,6 +110,7 @@ func (s *userService) Register(username string, password string) (*model.User, e
 	user := &model.User{
 		Username:     username,
 		PasswordHash: string(hashedPassword),
+		Role:         model.RoleUser,
 	}
 
 	// 4. 持久化存储
@@ -91,34 +125,40 @@ func (s *userService) Register(username string, password string) (*model.User, e
 	return user, nil
 }
 
-// Login 处理用户登录业务逻辑
-func (s *userService) Login(username string, password string) (string, error) {
-	// 1. 查找用户
+// Login 处理用户登录业务逻辑，校验用户名和密码，成功后由 SessionService 签发令牌
+func (s *userService) Login(ctx context.Context, username string, password string) (*model.User, error) {
+	// 1. 校验密码前先将本次尝试计为失败，处于退避或锁定期的用户名直接拒绝，不再校验密码
+	if err := s.limiter.Reserve(ctx, model.AttemptKindLogin, username); err != nil {
+		return nil, err
+	}
+
+	// 2. 查找用户
 	user, err := s.userStore.FindByUsername(username)
 
 	// 检查用户不存在或数据库返回的 RecordNotFound 错误
 	if errors.Is(err, gorm.ErrRecordNotFound) || user == nil {
-		return "", ErrInvalidCredentials // 隐藏用户不存在的细节
+		// 不存在的用户名同样计数（已在 Reserve 中计入），隐藏用户不存在的细节
+		return nil, ErrInvalidCredentials
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("%w: failed to retrieve user: %w", ErrStoreOperationFailed, err)
+		return nil, fmt.Errorf("%w: failed to retrieve user: %w", ErrStoreOperationFailed, err)
 	}
 
-	// 2. 密码验证
+	// 3. 密码验证
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
 		logger.Logger.Error("Error comparing password hash", zap.Error(err))
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
+	s.limiter.Succeed(ctx, model.AttemptKindLogin, username)
 
-	// 3. 登录成功，生成 JWT Token
-	token, err := s.jwtService.GenerateToken(user)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+	// 4. 密码正确后才提示账户已停用，避免泄露账户状态
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
 	}
 
-	return token, nil
+	return user, nil
 }
 
 // FindUserByUsername 实现 UserService 接口
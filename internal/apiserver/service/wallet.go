@@ -4,11 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
 
 	"github.com/bwmspring/go-web3-wallet-backend/config"
 	"github.com/bwmspring/go-web3-wallet-backend/model"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/conversion"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/web3client"
 )
 
@@ -17,11 +26,37 @@ var (
 	ErrWalletNotFound    = errors.New("wallet not found or insufficient permission")
 	ErrPasswordIncorrect = errors.New("wallet password incorrect")
 	ErrChainNotSupported = errors.New("chain ID is not supported")
+	ErrInvalidAddress    = errors.New("invalid ethereum address")
 
 	// Transfer 专用错误
 	ErrInvalidAmount   = errors.New("invalid or malformed transfer amount")
 	ErrInsufficientGas = errors.New("insufficient balance to cover gas fee")
 	ErrInsufficientBal = errors.New("insufficient balance for transfer amount")
+	ErrInvalidFee      = errors.New("invalid fee tier or fee caps")
+	ErrTokenNotFound   = errors.New("token is not supported on this chain")
+
+	// ImportWallet 专用错误
+	ErrWalletAlreadyExists   = errors.New("wallet address already exists")
+	ErrInvalidImportType     = errors.New("unsupported wallet import type")
+	ErrInvalidMnemonic       = errors.New("invalid BIP-39 mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrInvalidPrivateKey     = errors.New("invalid private key")
+	ErrInvalidKeystore       = errors.New("invalid keystore JSON")
+
+	// 签名相关错误
+	ErrSignerUnavailable = errors.New("remote signer is not configured or does not hold this account")
+	ErrKeyNotCustodied   = errors.New("wallet key is held by a remote signer")
+
+	// ErrRemoteAccountNotAssigned 表示远程签名账户未在 signer.accounts 中分配给当前用户
+	ErrRemoteAccountNotAssigned = errors.New("remote signer account is not assigned to this user")
+
+	// ErrMnemonicSeedExists 表示并发请求已为用户创建了系统生成的助记词，需重新读取后使用已有助记词
+	ErrMnemonicSeedExists = errors.New("generated mnemonic seed already exists")
+
+	// Shamir 备份与恢复专用错误
+	ErrInvalidShamirOptions = errors.New("shamir threshold must be between 2 and the number of shares")
+	ErrShamirSeedExists     = errors.New("shamir backup is only available when the mnemonic is first generated")
+	ErrInvalidShares        = errors.New("invalid, mismatched or insufficient shamir shares")
 )
 
 // WalletStore 定义了钱包数据存储的接口 (DIP: 由 service 层定义)
@@ -34,32 +69,149 @@ type WalletStore interface {
 
 	// FindEncryptedKeyByAddress 根据地址查找加密后的私钥（Keystore）
 	FindEncryptedKeyByAddress(ctx context.Context, address string) (string, error)
+
+	// GetWalletByAddress 根据地址获取钱包，不存在时返回 nil, nil
+	GetWalletByAddress(ctx context.Context, address string) (*model.Wallet, error)
+
+	// GetMnemonicSeedByUserID 获取用户的助记词记录，不存在时返回 nil, nil
+	GetMnemonicSeedByUserID(ctx context.Context, userID uint) (*model.MnemonicSeed, error)
+
+	// ListMnemonicSeedsByUserID 按 ID 升序返回用户的全部助记词记录（含导入与 Shamir 恢复的助记词）
+	ListMnemonicSeedsByUserID(ctx context.Context, userID uint) ([]*model.MnemonicSeed, error)
+
+	// GetMnemonicSeedByID 根据 ID 获取助记词记录，不存在时返回 nil, nil
+	GetMnemonicSeedByID(ctx context.Context, id uint) (*model.MnemonicSeed, error)
+
+	// ListMnemonicSeeds 按 ID 升序分页列出 ID 大于 afterID 的助记词记录，用于主密钥轮换
+	ListMnemonicSeeds(ctx context.Context, afterID uint, limit int) ([]*model.MnemonicSeed, error)
+
+	// UpdateMnemonicSeedEnvelope 更新助记词的信封字段，仅当当前 master_key_id 仍为 previousKeyID 时生效
+	UpdateMnemonicSeedEnvelope(ctx context.Context, seed *model.MnemonicSeed, previousKeyID string) (bool, error)
+
+	// CreateDerivedWallet 在同一事务中写入助记词（seed.ID 为零时）并锁定该记录 (SELECT ... FOR UPDATE)，
+	// 以已派生的钱包数量（含已软删除记录）作为账户索引调用 derive 构造钱包并写入。
+	// 用户已有系统生成的助记词时不写入新助记词，返回 ErrMnemonicSeedExists
+	CreateDerivedWallet(
+		ctx context.Context,
+		seed *model.MnemonicSeed,
+		derive func(index uint32) (*model.Wallet, error),
+	) (*model.Wallet, error)
+
+	// WalletAddressExists 检查地址是否已被任何钱包占用（含已软删除记录）
+	WalletAddressExists(ctx context.Context, address string) (bool, error)
+
+	// ListWalletsByUserID 按 ID 升序返回用户的全部钱包
+	ListWalletsByUserID(ctx context.Context, userID uint) ([]model.Wallet, error)
+
+	// ReEncryptWalletKeys 在同一事务中替换用户钱包的 Keystore 与助记词密文，
+	// 任一记录已不是更新前的密文（被并发修改）时回滚并返回 false
+	ReEncryptWalletKeys(ctx context.Context, userID uint, wallets []WalletKeyUpdate, seeds []MnemonicSeedUpdate) (bool, error)
 }
 
 // WalletService 定义了钱包模块的业务逻辑接口
 type WalletService interface {
-	// CreateHDWallet 生成助记词、派生地址、创建Keystore并存储
-	CreateHDWallet(ctx context.Context, userID uint, password string, chainID uint) (*model.Wallet, string, error)
-
-	// Transfer 发起一笔链上交易
-	Transfer(
+	// CreateHDWallet 生成助记词、派生地址、创建Keystore并存储。
+	// 仅在新生成助记词时返回备份内容，shamir 非空时以 Shamir 份额代替助记词明文返回
+	CreateHDWallet(
 		ctx context.Context,
-		fromAddress string,
-		toAddress string,
-		amount string,
+		userID uint,
 		password string,
 		chainID uint,
-	) (string, error) // 返回 txHash
+		shamir *ShamirOptions,
+	) (*model.Wallet, *MnemonicBackup, error)
+
+	// RecoverWallet 使用达到阈值数量的 Shamir 份额重建助记词并恢复钱包
+	RecoverWallet(ctx context.Context, params *RecoverParams) (*model.Wallet, error)
+
+	// ImportWallet 导入助记词、私钥或 V3 Keystore，并使用钱包密码重新加密存储
+	ImportWallet(ctx context.Context, params *ImportParams) (*model.Wallet, error)
+
+	// Transfer 发起一笔链上交易，params.FromAddress 必须属于 params.UserID
+	Transfer(ctx context.Context, params *TransferParams) (string, error) // 返回 txHash
+
+	// GetBalance 查询指定地址在指定链上的原生币余额，token 非空时查询对应 ERC-20 代币余额
+	GetBalance(ctx context.Context, address string, chainID uint, token string) (*Balance, error)
+
+	// GetPortfolio 并发查询地址在所有已配置链上的原生币与已注册代币余额
+	GetPortfolio(ctx context.Context, address string) (*Portfolio, error)
+
+	// ListTransactions 分页查询用户钱包地址的交易历史
+	ListTransactions(ctx context.Context, userID uint, address string, query *TransactionQuery) (*TransactionPage, error)
+
+	// SpeedUpTransaction 以更高的手续费重新签名同一 nonce 的交易，返回替换交易的 txHash
+	SpeedUpTransaction(ctx context.Context, params *ReplaceParams) (string, error)
+
+	// CancelTransaction 在同一 nonce 上发送 0 金额的自转账以取消原交易，返回替换交易的 txHash
+	CancelTransaction(ctx context.Context, params *ReplaceParams) (string, error)
+
+	// SignMessage 使用用户钱包对 EIP-191 personal 消息或 EIP-712 结构化数据签名
+	SignMessage(ctx context.Context, params *SignMessageParams) (*MessageSignature, error)
+
+	// VerifyMessage 从消息签名中恢复签名者地址
+	VerifyMessage(ctx context.Context, params *VerifyMessageParams) (*MessageVerification, error)
+
+	// ChangeWalletPassword 使用原钱包密码解密用户的全部 Keystore 与助记词，并以新密码重新加密
+	ChangeWalletPassword(ctx context.Context, params *ChangeWalletPasswordParams) (*WalletPasswordChange, error)
+}
+
+// TransferParams 封装一次转账请求的参数
+type TransferParams struct {
+	UserID      uint
+	FromAddress string
+	ToAddress   string
+	Amount      string // 人类可读的金额，如 "1.5"
+	Password    string
+	ChainID     uint
+	Token       string // ERC-20 代币符号或合约地址，为空表示转账原生币
+
+	SecondFactorCode string // 用户已绑定第二因子且请求不是通过 API Key 认证时必填
+
+	// ViaAPIKey 表示请求通过 API Key 认证：创建密钥时已校验第二因子，
+	// 逐笔转账不再要求验证码（每个时间步只能使用一次，无法支持脚本化转账）
+	ViaAPIKey bool
 
-	// GetBalance 查询指定地址在指定链上的余额
-	GetBalance(ctx context.Context, address string, chainID uint) (string, error)
+	// 手续费策略：显式指定的 fee cap (Gwei) 优先于档位，均为空时使用 standard 档位。
+	// legacy 链上 MaxFeePerGasGwei 作为 gasPrice 使用，MaxPriorityFeePerGasGwei 被忽略。
+	FeeTier                  string
+	MaxFeePerGasGwei         string
+	MaxPriorityFeePerGasGwei string
+}
+
+// ReplaceParams 封装一次加速 / 取消交易请求的参数
+type ReplaceParams struct {
+	UserID   uint
+	TxHash   string // 待替换的原交易哈希
+	Password string
+
+	// 手续费策略同 TransferParams；最终手续费不低于原交易的 110%
+	FeeTier                  string
+	MaxFeePerGasGwei         string
+	MaxPriorityFeePerGasGwei string
+}
+
+// Balance 描述某地址持有的一种资产的余额
+type Balance struct {
+	Symbol       string `json:"symbol"`
+	TokenAddress string `json:"token_address,omitempty"` // 原生币为空
+	Decimals     uint8  `json:"decimals"`
+	Amount       string `json:"amount,omitempty"` // 按 decimals 换算后的人类可读金额
+	Error        string `json:"error,omitempty"`  // 仅在 portfolio 中单个代币查询失败时出现
 }
 
 // walletService 实现了 WalletService 接口
 type walletService struct {
 	store         WalletStore
+	nonceStore    NonceStore
+	txStore       TransactionStore
 	keyManager    crypto.KeyManager
+	seedCipher    crypto.EnvelopeCipher    // 助记词信封加密，未配置 key_management 时为 nil
+	remoteSigner  crypto.AccountSigner     // 远程签名进程，未配置 signer.remote_url 时为 nil
 	clientManager web3client.ClientManager // 依赖注入的 Web3 客户端管理器
+	feeOracle     web3client.FeeOracle
+	tokenRegistry web3client.TokenRegistry
+	balanceReader web3client.BalanceReader
+	secondFactor  SecondFactorVerifier // 转账的第二因子校验
+	limiter       AttemptLimiter       // 按钱包地址限制钱包密码的错误尝试
 	cfg           *config.Config
 }
 
@@ -68,50 +220,754 @@ var _ WalletService = (*walletService)(nil)
 // NewWalletService 创建并返回一个新的 WalletService 实例
 func NewWalletService(
 	store WalletStore,
+	nonceStore NonceStore,
+	txStore TransactionStore,
 	keyManager crypto.KeyManager,
+	seedCipher crypto.EnvelopeCipher,
+	remoteSigner crypto.AccountSigner,
 	clientManager web3client.ClientManager,
+	feeOracle web3client.FeeOracle,
+	tokenRegistry web3client.TokenRegistry,
+	balanceReader web3client.BalanceReader,
+	secondFactor SecondFactorVerifier,
+	limiter AttemptLimiter,
 	cfg *config.Config,
 ) WalletService {
 	return &walletService{
 		store:         store,
+		nonceStore:    nonceStore,
+		txStore:       txStore,
 		keyManager:    keyManager,
+		seedCipher:    seedCipher,
+		remoteSigner:  remoteSigner,
 		clientManager: clientManager,
+		feeOracle:     feeOracle,
+		tokenRegistry: tokenRegistry,
+		balanceReader: balanceReader,
+		secondFactor:  secondFactor,
+		limiter:       limiter,
 		cfg:           cfg,
 	}
 }
 
 // CreateHDWallet implements WalletService.
+// 每个用户只持有一份助记词：首次调用时生成并加密存储，后续调用复用该助记词，
+// 按已派生的账户数量递增 BIP-44 账户索引 (m/44'/60'/0'/0/n)。
+// 返回的备份仅在首次生成时非空，调用方应提示用户立即备份；已有助记词时请求 Shamir 备份返回 ErrShamirSeedExists。
 func (s *walletService) CreateHDWallet(
 	ctx context.Context,
 	userID uint,
 	password string,
 	chainID uint,
-) (*model.Wallet, string, error) {
-	panic("unimplemented")
+	shamir *ShamirOptions,
+) (*model.Wallet, *MnemonicBackup, error) {
+	// 1. 校验链是否受支持及 Shamir 参数
+	if err := s.ensureChainSupported(chainID); err != nil {
+		return nil, nil, err
+	}
+	if err := shamir.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	// 2. 获取用户已有的助记词，不存在则生成新的
+	seed, err := s.store.GetMnemonicSeedByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load mnemonic seed: %w", err)
+	}
+
+	var (
+		mnemonic string
+		backup   *MnemonicBackup // 仅在新生成助记词时返回
+	)
+
+	if seed == nil {
+		mnemonic, err = s.keyManager.GenerateMnemonic()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate mnemonic: %w", err)
+		}
+
+		encryptedSeed, err := s.keyManager.EncryptMnemonic(mnemonic, password)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encrypt mnemonic: %w", err)
+		}
+
+		seed = &model.MnemonicSeed{
+			UserID: userID,
+			Source: model.MnemonicSourceGenerated,
+		}
+		if err := sealMnemonicSeed(ctx, s.seedCipher, seed, encryptedSeed); err != nil {
+			return nil, nil, err
+		}
+		backup, err = s.mnemonicBackup(mnemonic, shamir)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		// 已有助记词不会再次拆分，不能静默忽略调用方请求的 Shamir 参数
+		if shamir != nil {
+			return nil, nil, ErrShamirSeedExists
+		}
+
+		mnemonic, err = s.decryptMnemonicSeed(ctx, seed, password)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 3. 锁定助记词记录后确定账户索引，派生 BIP-44 私钥并加密为 Keystore，与助记词（如为新生成）一并持久化
+	wallet, err := s.store.CreateDerivedWallet(ctx, seed, s.hdWalletDeriver(userID, chainID, mnemonic, password))
+	if errors.Is(err, ErrMnemonicSeedExists) {
+		// 并发请求已创建助记词：丢弃本次生成的助记词（未返回备份），改用已有助记词派生；
+		// 请求了 Shamir 备份时无法满足，与已有助记词的情形一致返回错误
+		if shamir != nil {
+			return nil, nil, ErrShamirSeedExists
+		}
+
+		seed, err = s.store.GetMnemonicSeedByUserID(ctx, userID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load mnemonic seed: %w", err)
+		}
+		if seed == nil {
+			return nil, nil, fmt.Errorf("generated mnemonic seed of user %d not found after conflict", userID)
+		}
+
+		mnemonic, err = s.decryptMnemonicSeed(ctx, seed, password)
+		if err != nil {
+			return nil, nil, err
+		}
+		backup = nil
+
+		wallet, err = s.store.CreateDerivedWallet(ctx, seed, s.hdWalletDeriver(userID, chainID, mnemonic, password))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to persist wallet: %w", err)
+	}
+
+	logger.Logger.Info("HD wallet created",
+		zap.Uint("user_id", userID),
+		zap.Uint("chain_id", chainID),
+		zap.String("address", wallet.Address),
+		zap.String("derivation_path", wallet.DerivationPath),
+	)
+
+	return wallet, backup, nil
+}
+
+// decryptMnemonicSeed 复用已有助记词：先解开信封，再使用创建时的钱包密码解密
+func (s *walletService) decryptMnemonicSeed(ctx context.Context, seed *model.MnemonicSeed, password string) (string, error) {
+	encryptedSeed, err := openMnemonicSeed(ctx, s.seedCipher, seed)
+	if err != nil {
+		return "", err
+	}
+
+	mnemonic, err := s.keyManager.DecryptMnemonic(encryptedSeed, password)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidPassword) {
+			return "", ErrPasswordIncorrect
+		}
+		return "", fmt.Errorf("failed to decrypt mnemonic seed: %w", err)
+	}
+
+	return mnemonic, nil
+}
+
+// hdWalletDeriver 返回在助记词记录锁内调用的派生函数：按账户索引派生私钥与地址，并使用钱包密码加密为 Keystore
+func (s *walletService) hdWalletDeriver(
+	userID uint,
+	chainID uint,
+	mnemonic string,
+	password string,
+) func(index uint32) (*model.Wallet, error) {
+	return func(index uint32) (*model.Wallet, error) {
+		path := crypto.DerivationPath(index)
+		privateKeyHex, address, err := s.keyManager.DeriveKeyFromMnemonic(mnemonic, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+
+		keystoreJSON, err := s.keyManager.EncryptPrivateKey(privateKeyHex, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt private key: %w", err)
+		}
+
+		return &model.Wallet{
+			UserID:         userID,
+			ChainID:        chainID,
+			Name:           fmt.Sprintf("Account %d", index+1),
+			Address:        address,
+			EncryptedKey:   keystoreJSON,
+			DerivationPath: path,
+			Source:         model.WalletSourceGenerated,
+			SignerType:     crypto.SignerTypeKeystore,
+		}, nil
+	}
 }
 
 // GetBalance implements WalletService.
-func (s *walletService) GetBalance(ctx context.Context, address string, chainID uint) (string, error) {
-	// 1. 调用 ClientManager 获取余额 (ClientManager 封装了 RPC 连接和重试)
-	balanceWei, err := s.clientManager.GetBalanceByAddress(ctx, chainID, address)
+func (s *walletService) GetBalance(ctx context.Context, address string, chainID uint, token string) (*Balance, error) {
+	if !common.IsHexAddress(address) {
+		return nil, ErrInvalidAddress
+	}
+	if err := s.ensureChainSupported(chainID); err != nil {
+		return nil, err
+	}
+
+	// 1. 原生币余额 (ClientManager 封装了 RPC 连接和重试)
+	if token == "" {
+		balanceWei, err := s.clientManager.GetBalanceByAddress(ctx, chainID, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch balance from blockchain: %w", err)
+		}
+
+		return &Balance{
+			Symbol:   s.tokenRegistry.NativeSymbol(chainID),
+			Decimals: conversion.EtherDecimals,
+			Amount:   conversion.WeiToEther(balanceWei).String(),
+		}, nil
+	}
+
+	// 2. ERC-20 代币余额，按代币自身的 decimals 换算
+	tokenInfo, err := s.lookupToken(ctx, chainID, token)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch balance from blockchain: %w", err)
+		return nil, err
 	}
 
-	// 2. 转换为人类可读格式 (ETH)
-	balanceETH := conversion.WeiToEther(balanceWei)
+	amount, err := s.clientManager.BalanceOf(ctx, chainID, tokenInfo.Address, common.HexToAddress(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token balance from blockchain: %w", err)
+	}
 
-	return balanceETH.String(), nil
+	return &Balance{
+		Symbol:       tokenInfo.Symbol,
+		TokenAddress: tokenInfo.Address.Hex(),
+		Decimals:     tokenInfo.Decimals,
+		Amount:       conversion.FromBaseUnits(amount, tokenInfo.Decimals).String(),
+	}, nil
 }
 
 // Transfer implements WalletService.
-func (w *walletService) Transfer(
+// 流程：归属校验 -> 金额解析 -> 余额校验 -> 第二因子 -> 获取签名者 -> 确定手续费 -> 分配 nonce -> 签名 -> 广播。
+// params.Token 非空时发送 ERC-20 transfer 调用：交易的 to 为代币合约，金额按代币 decimals 换算。
+func (w *walletService) Transfer(ctx context.Context, params *TransferParams) (string, error) {
+	chainID := params.ChainID
+
+	// 1. 校验链与地址格式
+	if err := w.ensureChainSupported(chainID); err != nil {
+		return "", err
+	}
+	if !common.IsHexAddress(params.FromAddress) {
+		return "", ErrWalletNotFound
+	}
+	if !common.IsHexAddress(params.ToAddress) {
+		return "", ErrInvalidAddress
+	}
+	from := common.HexToAddress(params.FromAddress)
+	to := common.HexToAddress(params.ToAddress)
+
+	// 2. 归属校验：from_address 必须属于当前认证用户
+	wallet, err := w.store.GetWalletByAddress(ctx, from.Hex())
+	if err != nil {
+		return "", fmt.Errorf("failed to load wallet: %w", err)
+	}
+	if wallet == nil || wallet.UserID != params.UserID {
+		return "", ErrWalletNotFound
+	}
+
+	// 3. 解析转账金额并校验资产余额
+	call, err := w.buildTransferCall(ctx, chainID, from, to, params.Token, params.Amount)
+	if err != nil {
+		return "", err
+	}
+
+	// 4. 原生币余额校验（原生币转账金额部分）
+	balance, err := w.clientManager.GetBalanceByAddress(ctx, chainID, from.Hex())
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch balance: %w", err)
+	}
+	if balance.Cmp(call.value) < 0 {
+		return "", ErrInsufficientBal
+	}
+
+	// 4.1 第二因子：用户已绑定时必须提交有效验证码，先于钱包密码校验，避免在无第二因子时探测密码；
+	// API Key 请求在创建密钥时已完成第二因子校验
+	if !params.ViaAPIKey {
+		if err := checkSecondFactor(ctx, w.secondFactor, params.UserID, params.SecondFactorCode, false); err != nil {
+			return "", err
+		}
+	}
+
+	// 5. 获取钱包的签名者（keystore 钱包在此解锁，密码错误时提前返回）
+	signer, err := w.signerFor(ctx, wallet, params.Password)
+	if err != nil {
+		return "", err
+	}
+
+	// 6. 准备交易参数：手续费、gasLimit
+	fees, err := w.resolveFees(ctx, chainID, params.FeeTier, params.MaxFeePerGasGwei, params.MaxPriorityFeePerGasGwei)
+	if err != nil {
+		return "", err
+	}
+
+	gasLimit, err := w.clientManager.EstimateGas(ctx, chainID, ethereum.CallMsg{
+		From:  from,
+		To:    &call.to,
+		Value: call.value,
+		Data:  call.data,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	// 7. 余额校验（原生币转账金额 + 最高矿工费）
+	fee := new(big.Int).Mul(fees.feeCap(), new(big.Int).SetUint64(gasLimit))
+	if balance.Cmp(new(big.Int).Add(call.value, fee)) < 0 {
+		return "", ErrInsufficientGas
+	}
+
+	// 8. 分配 nonce：广播成功前的任何失败都会释放该 nonce
+	nonce, err := w.reserveNonce(ctx, chainID, from)
+	if err != nil {
+		return "", err
+	}
+	broadcast := false
+	defer func() {
+		if !broadcast {
+			w.releaseNonce(ctx, chainID, from, nonce)
+		}
+	}()
+
+	// 9. 构建、签名并广播交易
+	chainIDBig := new(big.Int).SetUint64(uint64(chainID))
+	tx := fees.newTx(chainIDBig, nonce, call.to, call.value, gasLimit, call.data)
+
+	signedTx, err := w.signAndSend(ctx, chainID, from, tx, signer)
+	if err != nil {
+		return "", err
+	}
+	broadcast = true
+
+	if err := w.nonceStore.MarkNonceBroadcast(context.WithoutCancel(ctx), chainID, from.Hex(), nonce); err != nil {
+		// 交易已上链广播，标记失败不影响结果，仅记录日志
+		logger.Logger.Error("Failed to mark nonce as broadcast",
+			zap.Uint("chain_id", chainID),
+			zap.String("address", from.Hex()),
+			zap.Uint64("nonce", nonce),
+			zap.Error(err),
+		)
+	}
+
+	// 10. 写入交易历史
+	w.recordTransaction(ctx, params.UserID, chainID, from, signedTx, call.token, nil)
+
+	logger.Logger.Info("Transaction broadcast",
+		zap.Uint("user_id", params.UserID),
+		zap.Uint("chain_id", chainID),
+		zap.String("from", from.Hex()),
+		zap.String("to", to.Hex()),
+		zap.String("token", params.Token),
+		zap.String("tx_hash", signedTx.Hash().Hex()),
+		zap.Uint64("nonce", nonce),
+		zap.Bool("legacy", fees.legacy),
+	)
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// ListTransactions implements WalletService.
+func (s *walletService) ListTransactions(
+	ctx context.Context,
+	userID uint,
+	address string,
+	query *TransactionQuery,
+) (*TransactionPage, error) {
+	// 1. 归属校验：只能查询自己名下钱包的交易
+	if !common.IsHexAddress(address) {
+		return nil, ErrWalletNotFound
+	}
+	addr := common.HexToAddress(address).Hex()
+
+	wallet, err := s.store.GetWalletByAddress(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+	if wallet == nil || wallet.UserID != userID {
+		return nil, ErrWalletNotFound
+	}
+
+	// 2. 构建查询条件，多取一条用于判断是否还有下一页
+	filter, err := buildTxFilter(addr, query)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := filter.Limit
+	filter.Limit++
+
+	txs, err := s.txStore.ListTransactions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	page := &TransactionPage{Items: txs}
+	if len(txs) > pageSize {
+		page.Items = txs[:pageSize]
+		page.NextCursor = encodeIDCursor(page.Items[pageSize-1].ID)
+	}
+
+	return page, nil
+}
+
+// ensureChainSupported 校验链 ID 是否已在 ClientManager 中配置并成功连接
+func (s *walletService) ensureChainSupported(chainID uint) error {
+	if _, err := s.clientManager.GetClient(chainID); err != nil {
+		return fmt.Errorf("%w: %d", ErrChainNotSupported, chainID)
+	}
+	return nil
+}
+
+// signerFor 按钱包的签名方式返回 Signer：keystore 钱包使用钱包密码解锁，
+// remote 钱包交由远程签名进程签名，password 不参与签名。
+// 同一钱包地址连续输错密码时按 AttemptLimiter 退避或锁定，返回 *AttemptLockedError。
+func (s *walletService) signerFor(ctx context.Context, wallet *model.Wallet, password string) (crypto.Signer, error) {
+	if wallet.SignerType == crypto.SignerTypeRemote {
+		if s.remoteSigner == nil {
+			return nil, ErrSignerUnavailable
+		}
+		// 签名时再次核对归属，分配关系变更后已登记的钱包随即失效
+		if !s.remoteAccountAssigned(wallet.UserID, common.HexToAddress(wallet.Address)) {
+			return nil, ErrRemoteAccountNotAssigned
+		}
+		return s.remoteSigner, nil
+	}
+
+	if err := s.limiter.Reserve(ctx, model.AttemptKindWallet, wallet.Address); err != nil {
+		return nil, err
+	}
+
+	signer, err := crypto.UnlockKeystoreSigner(s.keyManager, wallet.EncryptedKey, password)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidPassword) {
+			return nil, ErrPasswordIncorrect
+		}
+		return nil, fmt.Errorf("failed to unlock keystore: %w", err)
+	}
+	s.limiter.Succeed(ctx, model.AttemptKindWallet, wallet.Address)
+
+	return signer, nil
+}
+
+// remoteAccountAssigned 判断远程签名账户是否在 signer.accounts 中分配给该用户
+func (s *walletService) remoteAccountAssigned(userID uint, address common.Address) bool {
+	for _, account := range s.cfg.Signer.Accounts {
+		if account.UserID == userID && common.IsHexAddress(account.Address) &&
+			common.HexToAddress(account.Address) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// transferCall 描述一笔转账在链上的实际调用
+type transferCall struct {
+	to    common.Address // 交易的 to：原生币转账为收款方，ERC-20 转账为代币合约
+	value *big.Int       // 交易携带的原生币金额 (Wei)，ERC-20 转账为 0
+	data  []byte         // ERC-20 transfer calldata，原生币转账为空
+	token *tokenTransfer // ERC-20 转账时非空，用于交易历史展示
+}
+
+// buildTransferCall 解析转账金额并校验资产余额，构建原生币或 ERC-20 转账调用。
+// 原生币余额需与手续费一并校验，由调用方完成；ERC-20 余额在此校验。
+func (s *walletService) buildTransferCall(
 	ctx context.Context,
-	fromAddress string,
-	toAddress string,
+	chainID uint,
+	from common.Address,
+	to common.Address,
+	token string,
 	amount string,
-	password string,
+) (*transferCall, error) {
+	if token == "" {
+		value, err := parseAmount(amount, conversion.EtherDecimals)
+		if err != nil {
+			return nil, err
+		}
+		return &transferCall{to: to, value: value}, nil
+	}
+
+	tokenInfo, err := s.lookupToken(ctx, chainID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenAmount, err := parseAmount(amount, tokenInfo.Decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenBalance, err := s.clientManager.BalanceOf(ctx, chainID, tokenInfo.Address, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token balance: %w", err)
+	}
+	if tokenBalance.Cmp(tokenAmount) < 0 {
+		return nil, ErrInsufficientBal
+	}
+
+	data, err := web3client.PackERC20Transfer(to, tokenAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transferCall{
+		to:    tokenInfo.Address,
+		value: new(big.Int),
+		data:  data,
+		token: &tokenTransfer{
+			token:     tokenInfo.Address,
+			recipient: to,
+			amount:    tokenAmount,
+		},
+	}, nil
+}
+
+// lookupToken 在代币注册表中查找代币，未注册时返回 ErrTokenNotFound
+func (s *walletService) lookupToken(ctx context.Context, chainID uint, token string) (*web3client.Token, error) {
+	tokenInfo, err := s.tokenRegistry.Lookup(ctx, chainID, token)
+	if err != nil {
+		if errors.Is(err, web3client.ErrTokenNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, token)
+		}
+		return nil, fmt.Errorf("failed to resolve token: %w", err)
+	}
+	return tokenInfo, nil
+}
+
+// parseAmount 将人类可读的金额字符串（如 "1.5"）按 decimals 转换为最小单位，拒绝非正数和超出精度的输入
+func parseAmount(amount string, decimals uint8) (*big.Int, error) {
+	amountDecimal, err := decimal.NewFromString(amount)
+	if err != nil || !amountDecimal.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+
+	value, err := conversion.ToBaseUnits(amountDecimal, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAmount, err.Error())
+	}
+
+	return value, nil
+}
+
+// reserveNonce 基于链上 pending nonce 从持久化分配器中预留一个 nonce
+func (s *walletService) reserveNonce(ctx context.Context, chainID uint, from common.Address) (uint64, error) {
+	chainPendingNonce, err := s.clientManager.PendingNonceAt(ctx, chainID, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+
+	nonce, err := s.nonceStore.ReserveNonce(ctx, chainID, from.Hex(), chainPendingNonce)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve nonce: %w", err)
+	}
+
+	return nonce, nil
+}
+
+// releaseNonce 释放未成功广播的 nonce。请求上下文可能已取消，因此使用 WithoutCancel 保证释放执行。
+func (s *walletService) releaseNonce(ctx context.Context, chainID uint, from common.Address, nonce uint64) {
+	if err := s.nonceStore.ReleaseNonce(context.WithoutCancel(ctx), chainID, from.Hex(), nonce); err != nil {
+		logger.Logger.Error("Failed to release nonce",
+			zap.Uint("chain_id", chainID),
+			zap.String("address", from.Hex()),
+			zap.Uint64("nonce", nonce),
+			zap.Error(err),
+		)
+	}
+}
+
+// signAndSend 通过 signer 签名并广播交易
+func (s *walletService) signAndSend(
+	ctx context.Context,
+	chainID uint,
+	from common.Address,
+	tx *types.Transaction,
+	signer crypto.Signer,
+) (*types.Transaction, error) {
+	signedTx, err := signer.SignTx(ctx, new(big.Int).SetUint64(uint64(chainID)), from, tx)
+	if err != nil {
+		if errors.Is(err, crypto.ErrSignerAccountMismatch) {
+			return nil, fmt.Errorf("%w: %w", ErrSignerUnavailable, err)
+		}
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	if err := s.clientManager.SendTransaction(ctx, chainID, signedTx); err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "insufficient funds"):
+			// 余额在校验后可能被其他交易消耗，节点会返回 insufficient funds
+			return nil, fmt.Errorf("%w: %s", ErrInsufficientGas, msg)
+		case strings.Contains(msg, "replacement transaction underpriced"):
+			return nil, fmt.Errorf("%w: %s", ErrReplacementUnderpriced, msg)
+		case strings.Contains(msg, "nonce too low"):
+			// 同 nonce 的交易已上链，替换交易无法再被接受
+			return nil, fmt.Errorf("%w: %s", ErrTxNotReplaceable, msg)
+		}
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	return signedTx, nil
+}
+
+// recordTransaction 将已广播的交易写入交易历史；replaces 非空时同时记录替换关系。
+// 交易此时已经广播，写入失败不能回滚链上状态，因此只记录日志而不向调用方返回错误。
+func (s *walletService) recordTransaction(
+	ctx context.Context,
+	userID uint,
+	chainID uint,
+	from common.Address,
+	signedTx *types.Transaction,
+	transfer *tokenTransfer,
+	replaces *model.Transaction,
+) {
+	ctx = context.WithoutCancel(ctx)
+
+	record, err := newTransactionRecord(userID, chainID, from, signedTx, transfer)
+	if err == nil {
+		if replaces != nil {
+			record.ReplacesHash = &replaces.Hash
+		}
+		err = s.txStore.CreateTransaction(ctx, record)
+	}
+	if err == nil && replaces != nil {
+		err = s.txStore.LinkReplacement(ctx, replaces.ID, record.Hash)
+	}
+
+	if err != nil {
+		logger.Logger.Error("Failed to record broadcast transaction",
+			zap.Uint("chain_id", chainID),
+			zap.String("tx_hash", signedTx.Hash().Hex()),
+			zap.Error(err),
+		)
+	}
+}
+
+// txFees 描述一笔交易最终采用的手续费参数
+type txFees struct {
+	legacy               bool
+	gasPrice             *big.Int // legacy 交易使用
+	maxFeePerGas         *big.Int // EIP-1559 交易使用
+	maxPriorityFeePerGas *big.Int // EIP-1559 交易使用
+}
+
+// feeCap 返回每单位 gas 的最高支付价格，用于余额校验
+func (f *txFees) feeCap() *big.Int {
+	if f.legacy {
+		return f.gasPrice
+	}
+	return f.maxFeePerGas
+}
+
+// newTx 根据手续费类型构建未签名的 legacy 或 EIP-1559 交易
+func (f *txFees) newTx(
+	chainID *big.Int,
+	nonce uint64,
+	to common.Address,
+	value *big.Int,
+	gasLimit uint64,
+	data []byte,
+) *types.Transaction {
+	if f.legacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: f.gasPrice,
+			Gas:      gasLimit,
+			To:       &to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: f.maxPriorityFeePerGas,
+		GasFeeCap: f.maxFeePerGas,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+}
+
+// resolveFees 根据显式 fee cap 或档位确定交易手续费
+func (s *walletService) resolveFees(
+	ctx context.Context,
 	chainID uint,
-) (string, error) {
-	panic("unimplemented")
+	tier string,
+	maxFeeGwei string,
+	maxPriorityFeeGwei string,
+) (*txFees, error) {
+	feeTier, err := web3client.ParseFeeTier(tier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFee, err.Error())
+	}
+
+	maxFee, err := parseGweiAmount(maxFeeGwei)
+	if err != nil {
+		return nil, err
+	}
+	maxPriorityFee, err := parseGweiAmount(maxPriorityFeeGwei)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := s.feeOracle.SuggestFees(ctx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate fees: %w", err)
+	}
+	suggestion := estimate.Tier(feeTier)
+
+	if estimate.Legacy {
+		fees := &txFees{legacy: true, gasPrice: suggestion.GasPrice}
+		if maxFee != nil {
+			fees.gasPrice = maxFee
+		}
+		return fees, nil
+	}
+
+	fees := &txFees{
+		maxFeePerGas:         suggestion.MaxFeePerGas,
+		maxPriorityFeePerGas: suggestion.MaxPriorityFeePerGas,
+	}
+	if maxFee != nil {
+		fees.maxFeePerGas = maxFee
+	}
+	if maxPriorityFee != nil {
+		fees.maxPriorityFeePerGas = maxPriorityFee
+	} else if fees.maxPriorityFeePerGas.Cmp(fees.maxFeePerGas) > 0 {
+		// 仅指定 maxFee 时，将建议小费收敛到 maxFee 以内
+		fees.maxPriorityFeePerGas = new(big.Int).Set(fees.maxFeePerGas)
+	}
+
+	if fees.maxPriorityFeePerGas.Cmp(fees.maxFeePerGas) > 0 {
+		return nil, fmt.Errorf("%w: max priority fee exceeds max fee", ErrInvalidFee)
+	}
+
+	return fees, nil
+}
+
+// parseGweiAmount 将 Gwei 字符串转换为 Wei，空字符串返回 nil 表示未指定
+func parseGweiAmount(amount string) (*big.Int, error) {
+	if amount == "" {
+		return nil, nil
+	}
+
+	amountDecimal, err := decimal.NewFromString(amount)
+	if err != nil || !amountDecimal.IsPositive() {
+		return nil, ErrInvalidFee
+	}
+
+	wei, err := conversion.GweiToWei(amountDecimal)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFee, err.Error())
+	}
+
+	return wei, nil
 }
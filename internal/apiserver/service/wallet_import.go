@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// 钱包导入类型
+const (
+	ImportTypeMnemonic   = "mnemonic"
+	ImportTypePrivateKey = "private_key"
+	ImportTypeKeystore   = "keystore"
+	ImportTypeRemote     = "remote" // 登记由远程签名进程持有私钥的地址
+)
+
+// defaultImportedWalletName 是未指定名称时导入钱包的默认名称
+const defaultImportedWalletName = "Imported Account"
+
+// ImportParams 封装一次钱包导入请求的参数
+type ImportParams struct {
+	UserID   uint
+	ChainID  uint
+	Password string // 钱包密码，导入的私钥将以此加密为 Keystore；remote 类型不使用
+	Name     string // 可选，钱包名称
+	Type     string // mnemonic / private_key / keystore
+
+	// Type = mnemonic
+	Mnemonic       string
+	Passphrase     string // 可选，BIP-39 passphrase
+	DerivationPath string // 可选，默认 m/44'/60'/0'/0/0
+
+	// Type = private_key
+	PrivateKey string
+
+	// Type = keystore
+	Keystore         string // V3 Keystore JSON
+	KeystorePassword string // 可选，Keystore 原密码，为空时使用 Password
+
+	// Type = remote
+	Address string
+}
+
+// importedKey 是解析导入内容得到的私钥及其元数据
+type importedKey struct {
+	privateKeyHex  string
+	address        string
+	derivationPath string
+	source         string
+	signerType     string
+	mnemonic       string // 仅助记词导入时非空
+}
+
+// ImportWallet implements WalletService.
+// 流程：解析并校验导入内容 -> 地址去重 -> 使用钱包密码加密私钥（及助记词） -> 持久化。
+// BIP-39 passphrase 不会被存储，用户需自行保管。
+func (s *walletService) ImportWallet(ctx context.Context, params *ImportParams) (*model.Wallet, error) {
+	// 1. 校验链是否受支持
+	if err := s.ensureChainSupported(params.ChainID); err != nil {
+		return nil, err
+	}
+
+	// 2. 解析导入内容，得到私钥和地址
+	key, err := s.parseImport(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.persistImportedKey(ctx, params, key)
+}
+
+// persistImportedKey 对解析得到的私钥执行地址去重、加密和持久化，导入与份额恢复共用
+func (s *walletService) persistImportedKey(
+	ctx context.Context,
+	params *ImportParams,
+	key *importedKey,
+) (*model.Wallet, error) {
+	// 3. 地址去重（唯一索引覆盖已软删除的钱包，这里同样包含它们）
+	exists, err := s.store.WalletAddressExists(ctx, key.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check wallet existence: %w", err)
+	}
+	if exists {
+		return nil, ErrWalletAlreadyExists
+	}
+
+	// 4. 使用钱包密码加密私钥（远程签名的钱包不保存私钥）；助记词导入同时加密保存助记词
+	var keystoreJSON string
+	if key.signerType == crypto.SignerTypeKeystore {
+		keystoreJSON, err = s.keyManager.EncryptPrivateKey(key.privateKeyHex, params.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt private key: %w", err)
+		}
+	}
+
+	var seed *model.MnemonicSeed
+	if key.mnemonic != "" {
+		encryptedSeed, err := s.keyManager.EncryptMnemonic(key.mnemonic, params.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt mnemonic: %w", err)
+		}
+
+		seed = &model.MnemonicSeed{
+			UserID: params.UserID,
+			Source: model.MnemonicSourceImported,
+		}
+		if err := sealMnemonicSeed(ctx, s.seedCipher, seed, encryptedSeed); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. 持久化，并发导入同一地址时由唯一索引兜底返回 ErrWalletAlreadyExists
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		name = defaultImportedWalletName
+	}
+
+	wallet := &model.Wallet{
+		UserID:         params.UserID,
+		ChainID:        params.ChainID,
+		Name:           name,
+		Address:        key.address,
+		EncryptedKey:   keystoreJSON,
+		DerivationPath: key.derivationPath,
+		Source:         key.source,
+		SignerType:     key.signerType,
+	}
+
+	if err := s.store.CreateWallet(ctx, wallet, seed); err != nil {
+		if errors.Is(err, ErrWalletAlreadyExists) {
+			return nil, ErrWalletAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to persist wallet: %w", err)
+	}
+
+	logger.Logger.Info("Wallet imported",
+		zap.Uint("user_id", params.UserID),
+		zap.Uint("chain_id", params.ChainID),
+		zap.String("address", key.address),
+		zap.String("source", key.source),
+	)
+
+	return wallet, nil
+}
+
+// parseImport 根据导入类型解析私钥和地址
+func (s *walletService) parseImport(ctx context.Context, params *ImportParams) (*importedKey, error) {
+	switch params.Type {
+	case ImportTypeMnemonic:
+		mnemonic := normalizeMnemonic(params.Mnemonic)
+
+		path := strings.TrimSpace(params.DerivationPath)
+		if path == "" {
+			path = crypto.DerivationPath(0)
+		}
+
+		privateKeyHex, address, err := s.keyManager.DeriveKeyFromMnemonicWithPassphrase(mnemonic, params.Passphrase, path)
+		if err != nil {
+			return nil, mapImportError(err)
+		}
+
+		return &importedKey{
+			privateKeyHex:  privateKeyHex,
+			address:        address,
+			derivationPath: path,
+			source:         model.WalletSourceMnemonic,
+			signerType:     crypto.SignerTypeKeystore,
+			mnemonic:       mnemonic,
+		}, nil
+
+	case ImportTypePrivateKey:
+		privateKeyHex, address, err := s.keyManager.ParsePrivateKey(params.PrivateKey)
+		if err != nil {
+			return nil, mapImportError(err)
+		}
+
+		return &importedKey{
+			privateKeyHex: privateKeyHex,
+			address:       address,
+			source:        model.WalletSourcePrivateKey,
+			signerType:    crypto.SignerTypeKeystore,
+		}, nil
+
+	case ImportTypeKeystore:
+		keystorePassword := params.KeystorePassword
+		if keystorePassword == "" {
+			keystorePassword = params.Password
+		}
+
+		decrypted, err := s.keyManager.DecryptKeystore(params.Keystore, keystorePassword)
+		if err != nil {
+			return nil, mapImportError(err)
+		}
+
+		// 以私钥推导的地址为准，忽略 Keystore 中可能被篡改的 address 字段
+		privateKeyHex, address, err := s.keyManager.ParsePrivateKey(decrypted)
+		if err != nil {
+			return nil, mapImportError(err)
+		}
+
+		return &importedKey{
+			privateKeyHex: privateKeyHex,
+			address:       address,
+			source:        model.WalletSourceKeystore,
+			signerType:    crypto.SignerTypeKeystore,
+		}, nil
+
+	case ImportTypeRemote:
+		if !common.IsHexAddress(params.Address) {
+			return nil, ErrInvalidAddress
+		}
+		address := common.HexToAddress(params.Address)
+
+		// 只允许登记分配给当前用户、且远程签名进程确实持有的地址
+		if s.remoteSigner == nil {
+			return nil, ErrSignerUnavailable
+		}
+		if !s.remoteAccountAssigned(params.UserID, address) {
+			return nil, ErrRemoteAccountNotAssigned
+		}
+		held, err := s.remoteSigner.HasAccount(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query remote signer accounts: %w", err)
+		}
+		if !held {
+			return nil, ErrSignerUnavailable
+		}
+
+		return &importedKey{
+			address:    address.Hex(),
+			source:     model.WalletSourceRemote,
+			signerType: crypto.SignerTypeRemote,
+		}, nil
+
+	default:
+		return nil, ErrInvalidImportType
+	}
+}
+
+// normalizeMnemonic 统一助记词格式：小写、单词之间以单个空格分隔
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// mapImportError 将 KeyManager 的校验错误映射为业务层错误
+func mapImportError(err error) error {
+	switch {
+	case errors.Is(err, crypto.ErrInvalidMnemonic):
+		return ErrInvalidMnemonic
+	case errors.Is(err, crypto.ErrInvalidDerivationPath):
+		return ErrInvalidDerivationPath
+	case errors.Is(err, crypto.ErrInvalidPrivateKey):
+		return ErrInvalidPrivateKey
+	case errors.Is(err, crypto.ErrInvalidKeystore):
+		return ErrInvalidKeystore
+	case errors.Is(err, crypto.ErrInvalidPassword):
+		return ErrPasswordIncorrect
+	}
+	return fmt.Errorf("failed to parse imported key: %w", err)
+}
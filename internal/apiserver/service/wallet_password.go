@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// ErrWalletKeysChanged 表示重新加密期间钱包或助记词被并发修改，整个操作已回滚，可重试
+var ErrWalletKeysChanged = errors.New("wallet keys changed during re-encryption, retry")
+
+// ChangeWalletPasswordParams 封装修改钱包密码的参数
+type ChangeWalletPasswordParams struct {
+	UserID           uint
+	OldPassword      string
+	NewPassword      string
+	SecondFactorCode string // 用户已绑定第二因子时必填
+}
+
+// WalletPasswordChange 是修改钱包密码的结果
+type WalletPasswordChange struct {
+	Wallets   int `json:"wallets"`   // 重新加密的 Keystore 数量
+	Mnemonics int `json:"mnemonics"` // 重新加密的助记词数量（含导入与 Shamir 恢复的助记词）
+}
+
+// WalletKeyUpdate 描述一个钱包 Keystore 的替换，PreviousKey 用于检测并发修改
+type WalletKeyUpdate struct {
+	WalletID     uint
+	PreviousKey  string
+	EncryptedKey string
+}
+
+// MnemonicSeedUpdate 描述助记词记录的替换：Seed 已写入新的信封字段，PreviousSeed 为原 encrypted_seed
+type MnemonicSeedUpdate struct {
+	Seed         *model.MnemonicSeed
+	PreviousSeed string
+}
+
+// ChangeWalletPassword implements WalletService.
+// 用户全部 Keystore 钱包及助记词必须使用同一个原钱包密码，任一解密失败则不做任何修改；
+// 远程签名钱包不持有 Keystore，不受影响。新的密文在同一事务中写入。
+func (s *walletService) ChangeWalletPassword(
+	ctx context.Context,
+	params *ChangeWalletPasswordParams,
+) (*WalletPasswordChange, error) {
+	if err := checkSecondFactor(ctx, s.secondFactor, params.UserID, params.SecondFactorCode, false); err != nil {
+		return nil, err
+	}
+	if params.NewPassword == params.OldPassword {
+		return nil, ErrPasswordUnchanged
+	}
+
+	wallets, err := s.store.ListWalletsByUserID(ctx, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+
+	// 1. 每个钱包先计入一次尝试，处于退避或锁定期的钱包直接拒绝
+	keystoreWallets := make([]model.Wallet, 0, len(wallets))
+	for _, wallet := range wallets {
+		if wallet.SignerType == crypto.SignerTypeRemote {
+			continue
+		}
+		if err := s.limiter.Reserve(ctx, model.AttemptKindWallet, wallet.Address); err != nil {
+			return nil, err
+		}
+		keystoreWallets = append(keystoreWallets, wallet)
+	}
+
+	seeds, err := s.store.ListMnemonicSeedsByUserID(ctx, params.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mnemonic seeds: %w", err)
+	}
+	if len(keystoreWallets) == 0 && len(seeds) == 0 {
+		return nil, ErrWalletNotFound
+	}
+
+	// 助记词同样以原密码解密，单独计入一次尝试，不持有 Keystore 钱包的用户也受退避与锁定约束
+	if len(seeds) > 0 {
+		if err := s.limiter.Reserve(ctx, model.AttemptKindMnemonic, userAttemptSubject(params.UserID)); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 使用原密码解密并以新密码重新加密全部 Keystore
+	updates := make([]WalletKeyUpdate, 0, len(keystoreWallets))
+	for _, wallet := range keystoreWallets {
+		privateKeyHex, err := s.keyManager.DecryptKeystore(wallet.EncryptedKey, params.OldPassword)
+		if err != nil {
+			if errors.Is(err, crypto.ErrInvalidPassword) {
+				return nil, ErrPasswordIncorrect
+			}
+			return nil, fmt.Errorf("failed to decrypt keystore of wallet %d: %w", wallet.ID, err)
+		}
+
+		keystoreJSON, err := s.keyManager.EncryptPrivateKey(privateKeyHex, params.NewPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt keystore of wallet %d: %w", wallet.ID, err)
+		}
+
+		updates = append(updates, WalletKeyUpdate{
+			WalletID:     wallet.ID,
+			PreviousKey:  wallet.EncryptedKey,
+			EncryptedKey: keystoreJSON,
+		})
+	}
+
+	// 3. 助记词（系统生成、导入及 Shamir 恢复）同样使用钱包密码加密，需全部替换，
+	// 否则后续派生新钱包或查看助记词时仍需原密码
+	seedUpdates := make([]MnemonicSeedUpdate, 0, len(seeds))
+	for _, seed := range seeds {
+		seedUpdate, err := s.reEncryptMnemonicSeed(ctx, seed, params.OldPassword, params.NewPassword)
+		if err != nil {
+			return nil, err
+		}
+		seedUpdates = append(seedUpdates, *seedUpdate)
+	}
+
+	// 4. 同一事务中写入，任一记录被并发修改则全部回滚
+	replaced, err := s.store.ReEncryptWalletKeys(ctx, params.UserID, updates, seedUpdates)
+	if err != nil {
+		return nil, err
+	}
+	if !replaced {
+		return nil, ErrWalletKeysChanged
+	}
+
+	for _, wallet := range keystoreWallets {
+		s.limiter.Succeed(ctx, model.AttemptKindWallet, wallet.Address)
+	}
+	if len(seeds) > 0 {
+		s.limiter.Succeed(ctx, model.AttemptKindMnemonic, userAttemptSubject(params.UserID))
+	}
+
+	logger.Logger.Info("Wallet password changed",
+		zap.Uint("user_id", params.UserID),
+		zap.Int("wallets", len(updates)),
+		zap.Int("mnemonics", len(seedUpdates)),
+	)
+
+	return &WalletPasswordChange{Wallets: len(updates), Mnemonics: len(seedUpdates)}, nil
+}
+
+// reEncryptMnemonicSeed 解开信封并以原密码解密助记词，再以新密码加密、重新做信封加密
+func (s *walletService) reEncryptMnemonicSeed(
+	ctx context.Context,
+	seed *model.MnemonicSeed,
+	oldPassword string,
+	newPassword string,
+) (*MnemonicSeedUpdate, error) {
+	encryptedSeed, err := openMnemonicSeed(ctx, s.seedCipher, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonic, err := s.keyManager.DecryptMnemonic(encryptedSeed, oldPassword)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidPassword) {
+			return nil, ErrPasswordIncorrect
+		}
+		return nil, fmt.Errorf("failed to decrypt mnemonic seed: %w", err)
+	}
+
+	reEncrypted, err := s.keyManager.EncryptMnemonic(mnemonic, newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encrypt mnemonic: %w", err)
+	}
+
+	update := &MnemonicSeedUpdate{PreviousSeed: seed.EncryptedSeed}
+	updated := *seed
+	updated.WrappedDataKey, updated.MasterKeyID = "", ""
+	if err := sealMnemonicSeed(ctx, s.seedCipher, &updated, reEncrypted); err != nil {
+		return nil, err
+	}
+	update.Seed = &updated
+
+	return update, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// ShamirOptions 指定将新生成的助记词拆分为 Shares 份，任意 Threshold 份即可恢复
+type ShamirOptions struct {
+	Shares    int
+	Threshold int
+}
+
+// validate 校验份额参数，nil 表示不使用 Shamir 备份
+func (o *ShamirOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Threshold < 2 || o.Threshold > o.Shares || o.Shares > crypto.ShamirMaxShares {
+		return ErrInvalidShamirOptions
+	}
+	return nil
+}
+
+// MnemonicBackup 新生成助记词的备份内容：
+// 未请求 Shamir 时 Mnemonic 为助记词明文，否则 Mnemonic 为空，Shares 为编码后的份额
+type MnemonicBackup struct {
+	Mnemonic  string
+	Shares    []string
+	Threshold int
+}
+
+// RecoverParams 封装一次 Shamir 份额恢复请求的参数
+type RecoverParams struct {
+	UserID         uint
+	ChainID        uint
+	Password       string   // 钱包密码，恢复的私钥和助记词将以此加密存储
+	Name           string   // 可选，钱包名称
+	Shares         []string // 至少达到阈值数量的编码份额
+	DerivationPath string   // 可选，默认 m/44'/60'/0'/0/0
+}
+
+// mnemonicBackup 根据 Shamir 参数生成助记词的备份内容
+func (s *walletService) mnemonicBackup(mnemonic string, shamir *ShamirOptions) (*MnemonicBackup, error) {
+	if shamir == nil {
+		return &MnemonicBackup{Mnemonic: mnemonic}, nil
+	}
+
+	shares, err := s.keyManager.SplitMnemonic(mnemonic, shamir.Shares, shamir.Threshold)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidShamirParams) {
+			return nil, ErrInvalidShamirOptions
+		}
+		return nil, fmt.Errorf("failed to split mnemonic: %w", err)
+	}
+
+	return &MnemonicBackup{Shares: shares, Threshold: shamir.Threshold}, nil
+}
+
+// RecoverWallet implements WalletService.
+// 流程：合并份额重建助记词 -> 按助记词导入流程派生私钥 -> 地址去重、加密并持久化。
+func (s *walletService) RecoverWallet(ctx context.Context, params *RecoverParams) (*model.Wallet, error) {
+	// 1. 校验链是否受支持
+	if err := s.ensureChainSupported(params.ChainID); err != nil {
+		return nil, err
+	}
+
+	// 2. 合并份额，份额不足、校验失败或来自不同拆分时统一返回 ErrInvalidShares
+	mnemonic, err := s.keyManager.CombineMnemonic(params.Shares)
+	if err != nil {
+		if isShamirShareError(err) {
+			return nil, ErrInvalidShares
+		}
+		return nil, fmt.Errorf("failed to combine shares: %w", err)
+	}
+
+	// 3. 复用助记词导入的解析逻辑派生私钥和地址
+	importParams := &ImportParams{
+		UserID:         params.UserID,
+		ChainID:        params.ChainID,
+		Password:       params.Password,
+		Name:           params.Name,
+		Type:           ImportTypeMnemonic,
+		Mnemonic:       mnemonic,
+		DerivationPath: params.DerivationPath,
+	}
+
+	key, err := s.parseImport(ctx, importParams)
+	if err != nil {
+		return nil, err
+	}
+	key.source = model.WalletSourceShamir
+
+	// 4. 地址去重、加密并持久化
+	wallet, err := s.persistImportedKey(ctx, importParams, key)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Wallet recovered from shamir shares",
+		zap.Uint("user_id", params.UserID),
+		zap.String("address", wallet.Address),
+		zap.Int("shares", len(params.Shares)),
+	)
+
+	return wallet, nil
+}
+
+// isShamirShareError 判断是否为份额本身不合法导致的错误
+func isShamirShareError(err error) bool {
+	return errors.Is(err, crypto.ErrInvalidShare) ||
+		errors.Is(err, crypto.ErrInsufficientShares) ||
+		errors.Is(err, crypto.ErrShareMismatch)
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// apiKeys 实现了 service.APIKeyStore 接口
+type apiKeys struct {
+	db *gorm.DB
+}
+
+var _ service.APIKeyStore = (*apiKeys)(nil)
+
+// NewAPIKeys 实例化 APIKeyStore，并返回 service.APIKeyStore 接口类型
+func NewAPIKeys(db *gorm.DB) service.APIKeyStore {
+	return &apiKeys{db: db}
+}
+
+// CreateAPIKey 保存新创建的 API Key
+func (r *apiKeys) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash 根据完整密钥的哈希查找记录（含已吊销、已过期的记录），不存在时返回 nil, nil
+func (r *apiKeys) GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var key model.APIKey
+
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// GetAPIKeyByID 根据 ID 查找记录，不存在时返回 nil, nil
+func (r *apiKeys) GetAPIKeyByID(ctx context.Context, keyID uint) (*model.APIKey, error) {
+	var key model.APIKey
+
+	err := r.db.WithContext(ctx).First(&key, keyID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListAPIKeys 按创建时间倒序返回用户的全部 API Key
+func (r *apiKeys) ListAPIKeys(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	var result []model.APIKey
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&result).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	return result, nil
+}
+
+// CountActiveAPIKeys 统计用户未吊销且未过期的 API Key 数量
+func (r *apiKeys) CountActiveAPIKeys(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count api keys: %w", err)
+	}
+
+	return count, nil
+}
+
+// RevokeAPIKey 吊销用户的 API Key，记录不存在、不属于该用户或已吊销时返回 false
+func (r *apiKeys) RevokeAPIKey(ctx context.Context, userID uint, keyID uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", now)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// TouchAPIKey 更新最近使用时间，仅当上次记录早于 staleBefore 时写入，避免每个请求都更新
+func (r *apiKeys) TouchAPIKey(ctx context.Context, keyID uint, now time.Time, staleBefore time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyID, staleBefore).
+		Update("last_used_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+	return nil
+}
+
+// UseRequestNonce 原子地记录签名请求的 nonce，同一密钥的 nonce 已存在（重放）时返回 false
+func (r *apiKeys) UseRequestNonce(ctx context.Context, nonce *model.APIRequestNonce) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(nonce)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record request nonce: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpiredRequestNonces 清理 before 之前过期的 nonce
+func (r *apiKeys) DeleteExpiredRequestNonces(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at <= ?", before).
+		Delete(&model.APIRequestNonce{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired request nonces: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ListAPIKeySigningSecrets 按 ID 升序分页列出带有签名密钥的记录（keyset 分页），供主密钥轮换使用
+func (r *apiKeys) ListAPIKeySigningSecrets(ctx context.Context, afterID uint, limit int) ([]*model.APIKey, error) {
+	var records []*model.APIKey
+
+	err := r.db.WithContext(ctx).
+		Where("id > ? AND signing_secret IS NOT NULL AND signing_secret <> ''", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&records).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api key signing secrets: %w", err)
+	}
+
+	return records, nil
+}
+
+// UpdateAPIKeyEnvelope 以原 master_key_id 为条件更新签名密钥的信封字段，记录已被并发修改时返回 false
+func (r *apiKeys) UpdateAPIKeyEnvelope(ctx context.Context, key *model.APIKey, previousKeyID string) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", key.ID)
+	if previousKeyID == "" {
+		query = query.Where("master_key_id IS NULL OR master_key_id = ''")
+	} else {
+		query = query.Where("master_key_id = ?", previousKeyID)
+	}
+
+	result := query.Updates(map[string]any{
+		"signing_secret":   key.SigningSecret,
+		"wrapped_data_key": key.WrappedDataKey,
+		"master_key_id":    key.MasterKeyID,
+	})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update api key envelope: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
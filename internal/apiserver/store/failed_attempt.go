@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// failedAttempts 实现了 service.FailedAttemptStore 接口
+type failedAttempts struct {
+	db *gorm.DB
+}
+
+var _ service.FailedAttemptStore = (*failedAttempts)(nil)
+
+// NewFailedAttempts 实例化 FailedAttemptStore，并返回 service.FailedAttemptStore 接口类型
+func NewFailedAttempts(db *gorm.DB) service.FailedAttemptStore {
+	return &failedAttempts{db: db}
+}
+
+// GetFailedAttempt 查找计数记录，不存在时返回 nil, nil
+func (r *failedAttempts) GetFailedAttempt(ctx context.Context, kind string, subject string) (*model.FailedAttempt, error) {
+	var attempt model.FailedAttempt
+
+	err := r.db.WithContext(ctx).Where("kind = ? AND subject = ?", kind, subject).First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query failed attempt: %w", err)
+	}
+
+	return &attempt, nil
+}
+
+// ReserveFailedAttempt 以 INSERT ... ON CONFLICT DO UPDATE ... WHERE ... RETURNING 在一条语句中完成
+// "未锁定则累加并按退避策略设置 locked_until"，并发请求在行锁上串行，无法在锁定写入前同时通过。
+// 已过期的记录从 1 重新计数；当前处于锁定期时 WHERE 不成立，不更新也不返回行，此时返回 false。
+func (r *failedAttempts) ReserveFailedAttempt(
+	ctx context.Context,
+	attempt *model.FailedAttempt,
+	policy service.BackoffPolicy,
+) (bool, error) {
+	expired := "failed_attempts.expires_at <= EXCLUDED.last_failed_at"
+	failures := "(CASE WHEN " + expired + " THEN 1 ELSE failed_attempts.failures + 1 END)"
+
+	// 与 service.BackoffPolicy.Delay 相同的计算：毫秒数，未达到退避次数时为 NULL
+	delay := "(CASE WHEN " + failures + " >= CAST(@max_failures AS INTEGER) THEN CAST(@lockout_ms AS DOUBLE PRECISION)" +
+		" WHEN " + failures + " < CAST(@backoff_after AS INTEGER) THEN NULL" +
+		" ELSE LEAST(CAST(@base_ms AS DOUBLE PRECISION) * POWER(2, LEAST(" + failures + " - CAST(@backoff_after AS INTEGER), 62))," +
+		" CAST(@lockout_ms AS DOUBLE PRECISION)) END)"
+	lockedUntil := "(EXCLUDED.last_failed_at + " + delay + " * INTERVAL '1 millisecond')"
+
+	args := map[string]any{
+		"max_failures":  policy.MaxFailures,
+		"backoff_after": policy.BackoffAfter,
+		"base_ms":       policy.BackoffBase.Milliseconds(),
+		"lockout_ms":    policy.LockoutDuration.Milliseconds(),
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "kind"}, {Name: "subject"}},
+				DoUpdates: clause.Set{
+					{Column: clause.Column{Name: "failures"}, Value: gorm.Expr(failures)},
+					{Column: clause.Column{Name: "locked_until"}, Value: clause.NamedExpr{SQL: lockedUntil, Vars: []any{args}}},
+					{
+						Column: clause.Column{Name: "expires_at"},
+						Value: clause.NamedExpr{
+							SQL: "GREATEST(CASE WHEN " + expired + " THEN EXCLUDED.expires_at ELSE failed_attempts.expires_at END, " +
+								"EXCLUDED.expires_at, " + lockedUntil + ")",
+							Vars: []any{args},
+						},
+					},
+					{Column: clause.Column{Name: "last_failed_at"}, Value: gorm.Expr("EXCLUDED.last_failed_at")},
+				},
+				// 处于退避或锁定期时不更新
+				Where: clause.Where{Exprs: []clause.Expression{
+					gorm.Expr("failed_attempts.locked_until IS NULL OR failed_attempts.locked_until <= EXCLUDED.last_failed_at"),
+				}},
+			},
+			clause.Returning{Columns: []clause.Column{
+				{Name: "failures"},
+				{Name: "locked_until"},
+				{Name: "expires_at"},
+			}},
+		).
+		Create(attempt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reserve failed attempt: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteFailedAttempts 删除计数记录（清除锁定），返回删除的记录数
+func (r *failedAttempts) DeleteFailedAttempts(ctx context.Context, kind string, subjects []string) (int64, error) {
+	if len(subjects) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("kind = ? AND subject IN ?", kind, subjects).
+		Delete(&model.FailedAttempt{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete failed attempts: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteExpiredFailedAttempts 清理 before 之前过期的记录
+func (r *failedAttempts) DeleteExpiredFailedAttempts(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.FailedAttempt{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired failed attempts: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
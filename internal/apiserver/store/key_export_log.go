@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// keyExportLogs 实现了 service.KeyExportLogStore 接口
+type keyExportLogs struct {
+	db *gorm.DB
+}
+
+var _ service.KeyExportLogStore = (*keyExportLogs)(nil)
+
+// NewKeyExportLogs 实例化 KeyExportLogStore，并返回 service.KeyExportLogStore 接口类型
+func NewKeyExportLogs(db *gorm.DB) service.KeyExportLogStore {
+	return &keyExportLogs{db: db}
+}
+
+// CreateKeyExportLog 写入一条导出审计记录
+func (r *keyExportLogs) CreateKeyExportLog(ctx context.Context, log *model.KeyExportLog) error {
+	if err := r.db.WithContext(ctx).Create(log).Error; err != nil {
+		return fmt.Errorf("failed to create key export log: %w", err)
+	}
+	return nil
+}
+
+// CountKeyExportAttempts 统计用户自 since 起的导出尝试次数（含失败），被限流拒绝的记录不计入
+func (r *keyExportLogs) CountKeyExportAttempts(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.KeyExportLog{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Where("failure_reason IS NULL OR failure_reason <> ?", service.KeyExportReasonRateLimited).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count key export attempts: %w", err)
+	}
+
+	return count, nil
+}
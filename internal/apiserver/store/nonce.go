@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+const (
+	// staleReservationAfter 超过该时间仍处于 reserved 的预留视为进程异常遗留，可被回收
+	staleReservationAfter = 5 * time.Minute
+
+	// broadcastGracePeriod 已广播的交易在该时间内即使未出现在链上 pending nonce 中也不视为空洞，
+	// 以容忍 RPC 节点之间的 mempool 同步延迟
+	broadcastGracePeriod = 2 * time.Minute
+)
+
+// nonces 实现了 service.NonceStore 接口
+type nonces struct {
+	db *gorm.DB
+}
+
+var _ service.NonceStore = (*nonces)(nil)
+
+// NewNonces 实例化 NonceStore，并返回 service.NonceStore 接口类型
+func NewNonces(db *gorm.DB) service.NonceStore {
+	return &nonces{db: db}
+}
+
+// ReserveNonce 在行锁保护下为 (chainID, address) 分配一个 nonce
+// chainPendingNonce 为链上 pending nonce，用于检测并修复数据库游标与链上状态的偏差：
+//   - 链上 nonce 超过游标：说明存在外部发送，游标直接前移；
+//   - 已释放的 nonce：优先复用，填补广播失败留下的空洞；
+//   - 链上 pending nonce 处无活跃预留：说明已广播的交易被丢弃，重新分配该 nonce。
+func (r *nonces) ReserveNonce(
+	ctx context.Context,
+	chainID uint,
+	address string,
+	chainPendingNonce uint64,
+) (uint64, error) {
+	var nonce uint64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 确保游标行存在（并发插入由唯一索引兜底）
+		cursor := model.NonceCursor{
+			ChainID:   chainID,
+			Address:   address,
+			NextNonce: chainPendingNonce,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&cursor).Error; err != nil {
+			return fmt.Errorf("failed to init nonce cursor: %w", err)
+		}
+
+		// 2. 对游标行加锁，串行化同一地址的分配
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("chain_id = ? AND address = ?", chainID, address).
+			First(&cursor).Error; err != nil {
+			return fmt.Errorf("failed to lock nonce cursor: %w", err)
+		}
+
+		now := time.Now()
+		scope := tx.Model(&model.NonceReservation{}).
+			Where("chain_id = ? AND address = ?", chainID, address).
+			Session(&gorm.Session{})
+
+		// 3. 低于链上 pending nonce 的预留已被链上消耗，清理掉
+		if err := scope.
+			Where("nonce < ?", chainPendingNonce).
+			Delete(&model.NonceReservation{}).Error; err != nil {
+			return fmt.Errorf("failed to prune consumed reservations: %w", err)
+		}
+
+		// 4. 回收长期未广播的遗留预留
+		if err := scope.
+			Where("status = ? AND updated_at < ?", model.NonceStatusReserved, now.Add(-staleReservationAfter)).
+			Update("status", model.NonceStatusReleased).Error; err != nil {
+			return fmt.Errorf("failed to recycle stale reservations: %w", err)
+		}
+
+		// 5. 链上已超前：前移游标
+		if chainPendingNonce > cursor.NextNonce {
+			logger.Logger.Info("Nonce cursor behind chain, resyncing",
+				zap.Uint("chain_id", chainID),
+				zap.String("address", address),
+				zap.Uint64("cursor", cursor.NextNonce),
+				zap.Uint64("chain_pending", chainPendingNonce),
+			)
+			cursor.NextNonce = chainPendingNonce
+		}
+
+		// 6. 选出待分配的 nonce，并持久化游标
+		picked, err := r.pickNonce(scope, &cursor, chainPendingNonce, now)
+		if err != nil {
+			return err
+		}
+		nonce = picked
+
+		if err := tx.Save(&cursor).Error; err != nil {
+			return fmt.Errorf("failed to save nonce cursor: %w", err)
+		}
+
+		return r.upsertReservation(tx, chainID, address, nonce)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return nonce, nil
+}
+
+// pickNonce 按优先级选出待分配的 nonce：
+// 已释放的 nonce > 链上 pending nonce 处的空洞 > 游标值（并前移游标）
+func (r *nonces) pickNonce(
+	scope *gorm.DB,
+	cursor *model.NonceCursor,
+	chainPendingNonce uint64,
+	now time.Time,
+) (uint64, error) {
+	// 1. 优先复用已释放的 nonce
+	var released model.NonceReservation
+	err := scope.
+		Where("status = ?", model.NonceStatusReleased).
+		Order("nonce ASC").
+		First(&released).Error
+	switch {
+	case err == nil:
+		return released.Nonce, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return 0, fmt.Errorf("failed to query released nonces: %w", err)
+	}
+
+	// 2. 空洞检测：链上 pending nonce 低于游标，且该 nonce 没有活跃预留
+	if chainPendingNonce < cursor.NextNonce {
+		var active int64
+		if err := scope.
+			Where("nonce = ?", chainPendingNonce).
+			Where("(status = ? OR (status = ? AND updated_at >= ?))",
+				model.NonceStatusReserved, model.NonceStatusBroadcast, now.Add(-broadcastGracePeriod)).
+			Count(&active).Error; err != nil {
+			return 0, fmt.Errorf("failed to check nonce gap: %w", err)
+		}
+
+		if active == 0 {
+			logger.Logger.Warn("Nonce gap detected, reassigning dropped nonce",
+				zap.Uint("chain_id", cursor.ChainID),
+				zap.String("address", cursor.Address),
+				zap.Uint64("nonce", chainPendingNonce),
+				zap.Uint64("cursor", cursor.NextNonce),
+			)
+			return chainPendingNonce, nil
+		}
+	}
+
+	// 3. 正常分配：取游标值并前移
+	nonce := cursor.NextNonce
+	cursor.NextNonce++
+	return nonce, nil
+}
+
+// upsertReservation 将指定 nonce 标记为 reserved（已存在的记录会被覆盖）
+func (r *nonces) upsertReservation(tx *gorm.DB, chainID uint, address string, nonce uint64) error {
+	reservation := model.NonceReservation{
+		ChainID: chainID,
+		Address: address,
+		Nonce:   nonce,
+		Status:  model.NonceStatusReserved,
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "address"}, {Name: "nonce"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
+	}).Create(&reservation).Error
+	if err != nil {
+		return fmt.Errorf("failed to reserve nonce %d: %w", nonce, err)
+	}
+
+	return nil
+}
+
+// ReleaseNonce 释放一个尚未广播的 nonce，使其可被下一次分配复用
+func (r *nonces) ReleaseNonce(ctx context.Context, chainID uint, address string, nonce uint64) error {
+	return r.updateStatus(ctx, chainID, address, nonce, model.NonceStatusReserved, model.NonceStatusReleased)
+}
+
+// MarkNonceBroadcast 将 nonce 标记为已广播
+func (r *nonces) MarkNonceBroadcast(ctx context.Context, chainID uint, address string, nonce uint64) error {
+	return r.updateStatus(ctx, chainID, address, nonce, model.NonceStatusReserved, model.NonceStatusBroadcast)
+}
+
+// updateStatus 在预留处于 from 状态时将其更新为 to 状态
+func (r *nonces) updateStatus(
+	ctx context.Context,
+	chainID uint,
+	address string,
+	nonce uint64,
+	from string,
+	to string,
+) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.NonceReservation{}).
+		Where("chain_id = ? AND address = ? AND nonce = ? AND status = ?", chainID, address, nonce, from).
+		Update("status", to).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark nonce %d as %s: %w", nonce, to, err)
+	}
+
+	return nil
+}
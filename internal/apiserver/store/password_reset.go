@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// passwordResets 实现了 service.PasswordResetStore 接口
+type passwordResets struct {
+	db *gorm.DB
+}
+
+var _ service.PasswordResetStore = (*passwordResets)(nil)
+
+// NewPasswordResets 实例化 PasswordResetStore，并返回 service.PasswordResetStore 接口类型
+func NewPasswordResets(db *gorm.DB) service.PasswordResetStore {
+	return &passwordResets{db: db}
+}
+
+// CreatePasswordResetToken 保存新令牌，并在同一事务中作废该用户此前未使用的令牌
+func (r *passwordResets) CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return nil
+}
+
+// CountPasswordResetTokensSince 统计用户自 since 起申请的令牌数量
+func (r *passwordResets) CountPasswordResetTokensSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count password reset tokens: %w", err)
+	}
+
+	return count, nil
+}
+
+// ResetPassword 在同一事务中消费令牌、更新密码哈希并作废该用户的其他令牌，
+// 令牌不存在、已使用或已过期时返回 false
+func (r *passwordResets) ResetPassword(
+	ctx context.Context,
+	tokenHash string,
+	passwordHash string,
+	now time.Time,
+) (uint, bool, error) {
+	var token model.PasswordResetToken
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 条件更新保证并发提交同一令牌时只有一个请求成功
+		result := tx.Model(&token).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "user_id"}}}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.User{}).
+			Where("id = ?", token.UserID).
+			Update("password_hash", passwordHash).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	return token.UserID, token.UserID != 0, nil
+}
+
+// DeleteExpiredPasswordResetTokens 清理 before 之前过期的令牌
+func (r *passwordResets) DeleteExpiredPasswordResetTokens(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.PasswordResetToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired password reset tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
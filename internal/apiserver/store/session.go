@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// sessions 实现了 service.SessionStore 接口
+type sessions struct {
+	db *gorm.DB
+}
+
+var _ service.SessionStore = (*sessions)(nil)
+
+// NewSessions 实例化 SessionStore，并返回 service.SessionStore 接口类型
+func NewSessions(db *gorm.DB) service.SessionStore {
+	return &sessions{db: db}
+}
+
+// CreateSession 保存新签发的刷新令牌
+func (r *sessions) CreateSession(ctx context.Context, session *model.Session) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetSessionByTokenHash 根据刷新令牌哈希查找记录（含已轮换、已注销的记录），不存在时返回 nil, nil
+func (r *sessions) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	var session model.Session
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RotateSession 在同一事务中将 current 标记为已轮换并写入 next。
+// current 已被轮换或注销（并发使用同一令牌）时返回 false，且不写入 next。
+func (r *sessions) RotateSession(ctx context.Context, current *model.Session, next *model.Session, now time.Time) (bool, error) {
+	rotated := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Session{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	return rotated, nil
+}
+
+// RevokeSessionFamily 注销用户某个会话下的全部刷新令牌，返回被注销的记录数
+func (r *sessions) RevokeSessionFamily(ctx context.Context, userID uint, familyID string, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Session{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Update("revoked_at", now)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RevokeSessionsStartedBefore 注销用户在 startedBefore 之前登录的全部会话，返回被注销的记录数
+func (r *sessions) RevokeSessionsStartedBefore(ctx context.Context, userID uint, startedBefore time.Time, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Session{}).
+		Where("user_id = ? AND started_at <= ? AND revoked_at IS NULL", userID, startedBefore).
+		Update("revoked_at", now)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ListActiveSessions 返回用户每个有效会话当前可用的刷新令牌记录，按最近活跃时间倒序
+func (r *sessions) ListActiveSessions(ctx context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	var result []model.Session
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("created_at DESC").
+		Find(&result).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteExpiredSessions 删除 before 之前已过期的刷新令牌记录，返回删除的记录数
+func (r *sessions) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.Session{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
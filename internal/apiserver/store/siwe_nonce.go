@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// siweNonces 实现了 service.SIWENonceStore 接口
+type siweNonces struct {
+	db *gorm.DB
+}
+
+var _ service.SIWENonceStore = (*siweNonces)(nil)
+
+// NewSIWENonces 实例化 SIWENonceStore，并返回 service.SIWENonceStore 接口类型
+func NewSIWENonces(db *gorm.DB) service.SIWENonceStore {
+	return &siweNonces{db: db}
+}
+
+// CreateSIWENonce 保存新签发的 nonce
+func (r *siweNonces) CreateSIWENonce(ctx context.Context, nonce *model.SIWENonce) error {
+	if err := r.db.WithContext(ctx).Create(nonce).Error; err != nil {
+		return fmt.Errorf("failed to create siwe nonce: %w", err)
+	}
+	return nil
+}
+
+// ConsumeSIWENonce 原子地删除未过期的 nonce，返回 false 表示 nonce 不存在、已使用或已过期
+func (r *siweNonces) ConsumeSIWENonce(ctx context.Context, nonce string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("nonce = ? AND expires_at > ?", nonce, now).
+		Delete(&model.SIWENonce{})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume siwe nonce: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpiredSIWENonces 清理 before 之前过期的 nonce
+func (r *siweNonces) DeleteExpiredSIWENonces(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at <= ?", before).
+		Delete(&model.SIWENonce{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired siwe nonces: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// tokenRevocations 实现了 service.TokenRevocationStore 接口
+type tokenRevocations struct {
+	db *gorm.DB
+}
+
+var _ service.TokenRevocationStore = (*tokenRevocations)(nil)
+
+// NewTokenRevocations 实例化 TokenRevocationStore，并返回 service.TokenRevocationStore 接口类型
+func NewTokenRevocations(db *gorm.DB) service.TokenRevocationStore {
+	return &tokenRevocations{db: db}
+}
+
+// RevokeToken 将单个访问令牌加入吊销列表，令牌此前已被吊销时返回 false
+func (r *tokenRevocations) RevokeToken(ctx context.Context, token *model.RevokedToken) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(token)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to revoke token: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsTokenRevoked 检查访问令牌 (jti) 是否已被单独吊销
+func (r *tokenRevocations) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.RevokedToken{}).
+		Where("jti = ?", jti).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to query revoked token: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpiredRevokedTokens 清理 before 之前已自然过期的吊销记录
+func (r *tokenRevocations) DeleteExpiredRevokedTokens(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.RevokedToken{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired revoked tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RevokeUserTokensBefore 设置用户的令牌吊销时间点，已有更晚的时间点时保持不变，返回生效后的时间点
+func (r *tokenRevocations) RevokeUserTokensBefore(ctx context.Context, userID uint, before time.Time) (time.Time, error) {
+	revocation := model.UserTokenRevocation{
+		UserID:        userID,
+		RevokedBefore: before,
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}},
+				DoUpdates: clause.Set{
+					{
+						Column: clause.Column{Name: "revoked_before"},
+						Value: gorm.Expr(
+							"GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)",
+						),
+					},
+					{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("EXCLUDED.updated_at")},
+				},
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "revoked_before"}}},
+		).
+		Create(&revocation).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	return revocation.RevokedBefore, nil
+}
+
+// GetUserTokensRevokedBefore 返回用户的令牌吊销时间点，未设置时返回 nil, nil
+func (r *tokenRevocations) GetUserTokensRevokedBefore(ctx context.Context, userID uint) (*time.Time, error) {
+	var revocation model.UserTokenRevocation
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&revocation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query user token revocation: %w", err)
+	}
+
+	return &revocation.RevokedBefore, nil
+}
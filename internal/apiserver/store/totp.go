@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// totps 实现了 service.TOTPStore 接口
+type totps struct {
+	db *gorm.DB
+}
+
+var _ service.TOTPStore = (*totps)(nil)
+
+// NewTOTPs 实例化 TOTPStore，并返回 service.TOTPStore 接口类型
+func NewTOTPs(db *gorm.DB) service.TOTPStore {
+	return &totps{db: db}
+}
+
+// GetTOTP 查找用户的 TOTP 记录（含未确认的记录），不存在时返回 nil, nil
+func (r *totps) GetTOTP(ctx context.Context, userID uint) (*model.UserTOTP, error) {
+	var record model.UserTOTP
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query totp: %w", err)
+	}
+
+	return &record, nil
+}
+
+// SavePendingTOTP 用新的未确认记录替换用户未确认的记录，用户已确认绑定时返回 false
+func (r *totps) SavePendingTOTP(ctx context.Context, record *model.UserTOTP) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND confirmed_at IS NULL", record.UserID).
+			Delete(&model.UserTOTP{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to save totp: %w", err)
+	}
+
+	return true, nil
+}
+
+// ConfirmTOTP 在同一事务中确认绑定、记录已使用的时间步并写入恢复码，记录不存在或已确认时返回 false
+func (r *totps) ConfirmTOTP(
+	ctx context.Context,
+	userID uint,
+	step int64,
+	codeHashes []string,
+	now time.Time,
+) (bool, error) {
+	confirmed := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.UserTOTP{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]any{
+				"confirmed_at":   now,
+				"last_used_step": step,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+			return err
+		}
+		confirmed = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm totp: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// UseTOTPStep 原子地记录已使用的时间步，step 不大于上次使用的时间步（重放）时返回 false
+func (r *totps) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.UserTOTP{}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update totp step: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode 原子地将未使用的恢复码标记为已使用，不存在或已使用时返回 false
+func (r *totps) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes 删除用户现有的全部恢复码并写入新的恢复码
+func (r *totps) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+// DeleteTOTP 删除用户的 TOTP 记录及全部恢复码
+func (r *totps) DeleteTOTP(ctx context.Context, userID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserTOTP{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete totp: %w", err)
+	}
+	return nil
+}
+
+// ListTOTPs 按 ID 升序分页列出 TOTP 记录（keyset 分页），供主密钥轮换使用
+func (r *totps) ListTOTPs(ctx context.Context, afterID uint, limit int) ([]*model.UserTOTP, error) {
+	var records []*model.UserTOTP
+
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&records).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list totps: %w", err)
+	}
+
+	return records, nil
+}
+
+// UpdateTOTPEnvelope 以原 master_key_id 为条件更新信封字段，记录已被并发修改时返回 false
+func (r *totps) UpdateTOTPEnvelope(ctx context.Context, record *model.UserTOTP, previousKeyID string) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&model.UserTOTP{}).Where("id = ?", record.ID)
+	if previousKeyID == "" {
+		query = query.Where("master_key_id IS NULL OR master_key_id = ''")
+	} else {
+		query = query.Where("master_key_id = ?", previousKeyID)
+	}
+
+	result := query.Updates(map[string]any{
+		"secret":           record.Secret,
+		"wrapped_data_key": record.WrappedDataKey,
+		"master_key_id":    record.MasterKeyID,
+	})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update totp envelope: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// replaceRecoveryCodes 在事务 tx 中替换用户的全部恢复码
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]model.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+
+	return tx.Create(&codes).Error
+}
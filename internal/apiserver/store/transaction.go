@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
+)
+
+// transactions 实现了 service.TransactionStore 接口
+type transactions struct {
+	db *gorm.DB
+}
+
+var _ service.TransactionStore = (*transactions)(nil)
+
+// NewTransactions 实例化 TransactionStore，并返回 service.TransactionStore 接口类型
+func NewTransactions(db *gorm.DB) service.TransactionStore {
+	return &transactions{db: db}
+}
+
+// CreateTransaction 写入一笔新广播的交易记录
+func (r *transactions) CreateTransaction(ctx context.Context, tx *model.Transaction) error {
+	if err := r.db.WithContext(ctx).Create(tx).Error; err != nil {
+		return fmt.Errorf("failed to create transaction record: %w", err)
+	}
+	return nil
+}
+
+// GetTransactionByHash 根据交易哈希查询，不存在时返回 nil, nil
+func (r *transactions) GetTransactionByHash(ctx context.Context, hash string) (*model.Transaction, error) {
+	var tx model.Transaction
+
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&tx).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query transaction %s: %w", hash, err)
+	}
+
+	return &tx, nil
+}
+
+// LinkReplacement 记录原交易已被 replacementHash 替换
+func (r *transactions) LinkReplacement(ctx context.Context, originalID uint, replacementHash string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.Transaction{}).
+		Where("id = ?", originalID).
+		Update("replaced_by_hash", replacementHash).Error
+	if err != nil {
+		return fmt.Errorf("failed to link replacement transaction %s: %w", replacementHash, err)
+	}
+
+	return nil
+}
+
+// ListTransactions 按 ID 倒序分页查询与地址相关的交易（作为发送方或接收方）
+func (r *transactions) ListTransactions(
+	ctx context.Context,
+	filter *service.TransactionFilter,
+) ([]model.Transaction, error) {
+	query := r.db.WithContext(ctx).
+		Where("(from_address = ? OR to_address = ?)", filter.Address, filter.Address)
+
+	if filter.ChainID != 0 {
+		query = query.Where("chain_id = ?", filter.ChainID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+
+	var txs []model.Transaction
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&txs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return txs, nil
+}
+
+// ListTrackableTransactions 按 ID 正序返回 ID 大于 afterID 且尚未最终确认的交易，供 ReceiptTracker 分页轮询
+func (r *transactions) ListTrackableTransactions(ctx context.Context, afterID uint, limit int) ([]model.Transaction, error) {
+	var txs []model.Transaction
+
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND id > ?", []string{model.TxStatusPending, model.TxStatusMined}, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&txs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trackable transactions: %w", err)
+	}
+
+	return txs, nil
+}
+
+// UpdateTransactionStatus 更新交易状态及回执字段（显式 Select 以便将回执字段清空为 NULL）
+func (r *transactions) UpdateTransactionStatus(ctx context.Context, tx *model.Transaction) error {
+	err := r.db.WithContext(ctx).
+		Model(tx).
+		Select("status", "block_number", "block_hash", "gas_used", "effective_gas_price", "confirmed_at").
+		Updates(tx).Error
+	if err != nil {
+		return fmt.Errorf("failed to update transaction %s: %w", tx.Hash, err)
+	}
+
+	return nil
+}
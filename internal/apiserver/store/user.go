@@ -1,7 +1,10 @@
 package store
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -56,3 +59,112 @@ func (r *users) FindByID(id uint) (*model.User, error) {
 	}
 	return &user, nil
 }
+
+// FindByEthereumAddress 通过 SIWE 关联的以太坊地址查找用户
+func (r *users) FindByEthereumAddress(address string) (*model.User, error) {
+	var user model.User
+	result := r.db.Where("ethereum_address = ?", address).First(&user)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// LinkEthereumAddress 为尚未关联地址的用户关联以太坊地址。
+// 用户已关联其他地址时返回 false；地址已被其他用户关联时返回 gorm.ErrDuplicatedKey。
+func (r *users) LinkEthereumAddress(userID uint, address string) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND (ethereum_address IS NULL OR ethereum_address = ?)", userID, address).
+		Update("ethereum_address", address)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ListUsers 按 ID 倒序分页查询用户
+func (r *users) ListUsers(ctx context.Context, filter *service.UserFilter) ([]model.User, error) {
+	query := r.db.WithContext(ctx)
+
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+
+	var result []model.User
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&result).Error; err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateUserRole 更新用户角色，用户不存在时返回 false
+func (r *users) UpdateUserRole(ctx context.Context, userID uint, role string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("role", role)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update user role: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// SetUserDisabledAt 设置用户停用时间，disabledAt 为 nil 表示启用，用户不存在时返回 false
+func (r *users) SetUserDisabledAt(ctx context.Context, userID uint, disabledAt *time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("disabled_at", disabledAt)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update user status: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UpdatePasswordHash 更新用户的密码哈希，用户不存在时返回 false
+func (r *users) UpdatePasswordHash(ctx context.Context, userID uint, passwordHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("password_hash", passwordHash)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update password: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// SetInitialPasswordHash 仅在用户尚无密码（通过 SIWE 创建）时设置密码哈希，条件更新避免覆盖并发设置的密码
+func (r *users) SetInitialPasswordHash(ctx context.Context, userID uint, passwordHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND password_hash = ''", userID).
+		Update("password_hash", passwordHash)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to set initial password: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
 	"github.com/bwmspring/go-web3-wallet-backend/model"
@@ -24,6 +25,8 @@ func NewWallets(db *gorm.DB) service.WalletStore {
 }
 
 // CreateWallet 在数据库中创建一个新的钱包记录和助记词记录（使用事务确保一致性）
+// 如果 mnemonic.ID 非零，说明助记词记录已存在（同一助记词派生的后续账户），此时只创建钱包记录。
+// 导入私钥或 Keystore 时 mnemonic 为 nil，钱包不关联助记词。
 func (r *wallets) CreateWallet(ctx context.Context, wallet *model.Wallet, mnemonic *model.MnemonicSeed) error {
 	// 启动数据库事务
 	tx := r.db.WithContext(ctx).Begin()
@@ -31,16 +34,20 @@ func (r *wallets) CreateWallet(ctx context.Context, wallet *model.Wallet, mnemon
 		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
 	}
 
-	// 1. 创建 MnemonicSeed 记录
-	if err := tx.Create(mnemonic).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to create mnemonic seed record: %w", err)
-	}
+	if mnemonic != nil {
+		// 1. 创建 MnemonicSeed 记录（仅当其尚未持久化时）
+		if mnemonic.ID == 0 {
+			if err := tx.Create(mnemonic).Error; err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to create mnemonic seed record: %w", err)
+			}
+		}
 
-	// 2. 关联 Wallet 和 MnemonicSeed (设置外键)
-	// 在 service 层必须确保 wallet.MnemonicID = mnemonic.ID 已经设置，
-	// 确保外键关系正确建立。
-	wallet.MnemonicID = mnemonic.ID
+		// 2. 关联 Wallet 和 MnemonicSeed (设置外键)
+		// 在 service 层必须确保 wallet.MnemonicID = mnemonic.ID 已经设置，
+		// 确保外键关系正确建立。
+		wallet.MnemonicID = &mnemonic.ID
+	}
 
 	// 3. 创建 Wallet 记录
 	if err := tx.Create(wallet).Error; err != nil {
@@ -48,8 +55,8 @@ func (r *wallets) CreateWallet(ctx context.Context, wallet *model.Wallet, mnemon
 
 		// 检查是否是地址重复错误 (假设 Address 字段有 unique 约束)
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			// 虽然理论上 KeyManager 应该生成唯一的地址，但数据库层必须处理这个技术错误
-			return fmt.Errorf("wallet address already exists: %w", err)
+			// 导入已存在的钱包，或并发导入同一地址时由唯一索引兜底
+			return fmt.Errorf("%w: %w", service.ErrWalletAlreadyExists, err)
 		}
 
 		return fmt.Errorf("failed to create wallet record: %w", err)
@@ -105,3 +112,263 @@ func (r *wallets) FindEncryptedKeyByAddress(ctx context.Context, address string)
 
 	return wallet.EncryptedKey, nil
 }
+
+// GetMnemonicSeedByUserID 获取用户最早创建的系统生成助记词记录（不含导入的助记词）
+// 作用：CreateHDWallet 复用同一助记词派生后续账户，而不是每次生成新的助记词。
+func (r *wallets) GetMnemonicSeedByUserID(ctx context.Context, userID uint) (*model.MnemonicSeed, error) {
+	seed := &model.MnemonicSeed{}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND source = ?", userID, model.MnemonicSourceGenerated).
+		Order("id ASC").
+		First(seed).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // 用户尚未创建助记词
+		}
+		return nil, fmt.Errorf("failed to query mnemonic seed by user ID: %w", err)
+	}
+
+	return seed, nil
+}
+
+// ListMnemonicSeedsByUserID 按 ID 升序返回用户的全部助记词记录，用于修改钱包密码时统一重新加密
+func (r *wallets) ListMnemonicSeedsByUserID(ctx context.Context, userID uint) ([]*model.MnemonicSeed, error) {
+	var seeds []*model.MnemonicSeed
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&seeds).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mnemonic seeds by user ID: %w", err)
+	}
+
+	return seeds, nil
+}
+
+// GetMnemonicSeedByID 根据 ID 获取助记词记录（含导入的助记词），用于助记词查看
+func (r *wallets) GetMnemonicSeedByID(ctx context.Context, id uint) (*model.MnemonicSeed, error) {
+	seed := &model.MnemonicSeed{}
+
+	err := r.db.WithContext(ctx).First(seed, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query mnemonic seed by ID: %w", err)
+	}
+
+	return seed, nil
+}
+
+// ListMnemonicSeeds 按 ID 升序分页列出助记词记录（keyset 分页，避免大表 OFFSET）
+func (r *wallets) ListMnemonicSeeds(ctx context.Context, afterID uint, limit int) ([]*model.MnemonicSeed, error) {
+	var seeds []*model.MnemonicSeed
+
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&seeds).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mnemonic seeds: %w", err)
+	}
+
+	return seeds, nil
+}
+
+// UpdateMnemonicSeedEnvelope 以原 master_key_id 为条件更新信封字段，记录已被并发修改时返回 false
+func (r *wallets) UpdateMnemonicSeedEnvelope(
+	ctx context.Context,
+	seed *model.MnemonicSeed,
+	previousKeyID string,
+) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&model.MnemonicSeed{}).Where("id = ?", seed.ID)
+	if previousKeyID == "" {
+		// 迁移前的历史记录该列为 NULL
+		query = query.Where("master_key_id IS NULL OR master_key_id = ''")
+	} else {
+		query = query.Where("master_key_id = ?", previousKeyID)
+	}
+
+	result := query.Updates(map[string]any{
+		"encrypted_seed":   seed.EncryptedSeed,
+		"wrapped_data_key": seed.WrappedDataKey,
+		"master_key_id":    seed.MasterKeyID,
+	})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update mnemonic seed envelope: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// CreateDerivedWallet 在同一事务中写入助记词（seed.ID 为零时）并锁定该记录，再以已派生的钱包数量作为账户索引
+// 调用 derive 构造钱包并写入。并发请求在行锁上串行，不会派生出相同的路径；derive 在锁内执行 Keystore 加密，
+// 同一助记词的派生请求因此排队。系统生成的助记词受 (user_id) WHERE source = 'generated' 部分唯一索引约束，
+// 冲突时不写入并返回 service.ErrMnemonicSeedExists。
+// 注意：计数使用 Unscoped 包含已软删除的钱包，因为地址唯一索引同样覆盖这些记录，复用其索引会导致派生出重复地址。
+func (r *wallets) CreateDerivedWallet(
+	ctx context.Context,
+	seed *model.MnemonicSeed,
+	derive func(index uint32) (*model.Wallet, error),
+) (*model.Wallet, error) {
+	var wallet *model.Wallet
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 新生成的助记词：与其他并发创建的请求只保留一条
+		if seed.ID == 0 {
+			result := tx.Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "user_id"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "source", Value: model.MnemonicSourceGenerated}}},
+				DoNothing:   true,
+			}).Create(seed)
+			if result.Error != nil {
+				return fmt.Errorf("failed to create mnemonic seed record: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return service.ErrMnemonicSeedExists
+			}
+		}
+
+		// 2. 锁定助记词记录，确定下一个账户索引
+		var locked model.MnemonicSeed
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, seed.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock mnemonic seed: %w", err)
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&model.Wallet{}).Where("mnemonic_id = ?", seed.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count wallets by mnemonic ID: %w", err)
+		}
+
+		// 3. 派生并写入钱包
+		derived, err := derive(uint32(count))
+		if err != nil {
+			return err
+		}
+		derived.MnemonicID = &seed.ID
+
+		if err := tx.Create(derived).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("%w: %w", service.ErrWalletAlreadyExists, err)
+			}
+			return fmt.Errorf("failed to create wallet record: %w", err)
+		}
+
+		wallet = derived
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// GetWalletByAddress 根据地址获取钱包（含 Keystore），用于转账前的归属校验与解锁
+func (r *wallets) GetWalletByAddress(ctx context.Context, address string) (*model.Wallet, error) {
+	wallet := &model.Wallet{}
+
+	err := r.db.WithContext(ctx).
+		Where("address = ?", address).
+		First(wallet).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // 地址未找到
+		}
+		return nil, fmt.Errorf("failed to query wallet by address: %w", err)
+	}
+
+	return wallet, nil
+}
+
+// WalletAddressExists 检查地址是否已被任何钱包占用
+// 注意：使用 Unscoped 包含已软删除的钱包，因为地址唯一索引同样覆盖这些记录。
+func (r *wallets) WalletAddressExists(ctx context.Context, address string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Wallet{}).
+		Where("address = ?", address).
+		Count(&count).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check wallet address existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// ListWalletsByUserID 按 ID 升序返回用户的全部钱包
+func (r *wallets) ListWalletsByUserID(ctx context.Context, userID uint) ([]model.Wallet, error) {
+	var result []model.Wallet
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&result).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+
+	return result, nil
+}
+
+// errWalletKeysChanged 用于在事务中检测到并发修改时回滚
+var errWalletKeysChanged = errors.New("wallet keys changed")
+
+// ReEncryptWalletKeys 在同一事务中替换用户钱包的 Keystore 与助记词密文，
+// 任一记录已不是更新前的密文（被并发修改）时回滚并返回 false
+func (r *wallets) ReEncryptWalletKeys(
+	ctx context.Context,
+	userID uint,
+	wallets []service.WalletKeyUpdate,
+	seeds []service.MnemonicSeedUpdate,
+) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, update := range wallets {
+			result := tx.Model(&model.Wallet{}).
+				Where("id = ? AND user_id = ? AND encrypted_key = ?", update.WalletID, userID, update.PreviousKey).
+				Update("encrypted_key", update.EncryptedKey)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errWalletKeysChanged
+			}
+		}
+
+		for _, update := range seeds {
+			result := tx.Model(&model.MnemonicSeed{}).
+				Where("id = ? AND user_id = ? AND encrypted_seed = ?", update.Seed.ID, userID, update.PreviousSeed).
+				Updates(map[string]any{
+					"encrypted_seed":   update.Seed.EncryptedSeed,
+					"wrapped_data_key": update.Seed.WrappedDataKey,
+					"master_key_id":    update.Seed.MasterKeyID,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errWalletKeysChanged
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errWalletKeysChanged) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to re-encrypt wallet keys: %w", err)
+	}
+
+	return true, nil
+}
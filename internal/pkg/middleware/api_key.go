@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
+	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// APIKeyHeader 是携带 API Key 的请求头
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyIDKey 是在 Gin Context 中存储当前 API Key ID 的 Key，仅 API Key 认证的请求存在
+const APIKeyIDKey = "apiKeyID"
+
+// APIKeyScopesKey 是在 Gin Context 中存储当前 API Key 权限范围的 Key，仅 API Key 认证的请求存在
+const APIKeyScopesKey = "apiKeyScopes"
+
+// APIKeyRequireSignatureKey 是在 Gin Context 中存储当前 API Key 是否强制签名的 Key，仅 API Key 认证的请求存在
+const APIKeyRequireSignatureKey = "apiKeyRequireSignature"
+
+// 签名请求使用的请求头，签名内容见 service.CanonicalRequest
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Timestamp"
+	NonceHeader     = "X-Nonce"
+)
+
+// maxSignedBodyBytes 是签名请求计算哈希时允许的最大请求体
+const maxSignedBodyBytes = 1 << 20
+
+// APIKeyAuth 返回 API Key 认证中间件，认证成功后与 JWTAuth 一样设置 UserIDKey，控制器无需区分认证方式
+func APIKeyAuth(authenticator service.APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "请求头缺少 X-API-Key 认证信息")
+			c.Abort()
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), rawKey, c.ClientIP())
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrAPIKeyInvalid):
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "API Key 无效、已过期或已被吊销")
+			case errors.Is(err, service.ErrAPIKeyIPNotAllowed):
+				response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "请求来源 IP 不在 API Key 白名单内")
+			case errors.Is(err, service.ErrUserDisabled):
+				response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "账户已被停用")
+			default:
+				logger.Logger.Error("Failed to authenticate api key", zap.Error(err))
+				response.Error(c, http.StatusServiceUnavailable, response.CodeInternalError, "认证服务暂时不可用，请稍后重试")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(UserIDKey, principal.UserID)
+		c.Set(APIKeyIDKey, principal.KeyID)
+		c.Set(APIKeyScopesKey, principal.Scopes)
+		c.Set(APIKeyRequireSignatureKey, principal.RequireSignature)
+		c.Next()
+	}
+}
+
+// JWTOrAPIKeyAuth 返回同时接受两种凭证的认证中间件：携带 X-API-Key 时使用 API Key 认证，否则使用 JWT
+func JWTOrAPIKeyAuth(
+	jwtService service.JWTService,
+	denylist service.TokenDenylist,
+	authenticator service.APIKeyAuthenticator,
+) gin.HandlerFunc {
+	jwtAuth := JWTAuth(jwtService, denylist)
+	apiKeyAuth := APIKeyAuth(authenticator)
+
+	return func(c *gin.Context) {
+		if c.GetHeader(APIKeyHeader) != "" {
+			apiKeyAuth(c)
+			return
+		}
+		jwtAuth(c)
+	}
+}
+
+// RequireScope 要求 API Key 认证的请求具有 scope 权限范围，JWT 认证的请求不受限制。需挂在认证中间件之后
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := GetAPIKeyScopes(c)
+		if ok && !slices.Contains(scopes, scope) {
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "API Key 缺少所需的权限范围: "+scope)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// VerifySignature 校验 API Key 请求的签名 (X-Signature / X-Timestamp / X-Nonce)，需挂在认证中间件之后。
+// JWT 认证的请求不受影响；未携带签名的 API Key 请求仅在密钥未强制签名时放行。
+func VerifySignature(verifier service.RequestSignatureVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID, ok := c.Get(APIKeyIDKey)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		signature := c.GetHeader(SignatureHeader)
+		if signature == "" {
+			if c.GetBool(APIKeyRequireSignatureKey) {
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "该 API Key 要求请求签名，请求头缺少 X-Signature")
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodyBytes))
+		if err != nil {
+			response.Error(c, http.StatusRequestEntityTooLarge, response.CodeInvalidParam, "请求体过大或读取失败")
+			c.Abort()
+			return
+		}
+		// 恢复请求体供后续处理器读取
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = verifier.VerifyRequestSignature(c.Request.Context(), keyID.(uint), &service.SignedRequest{
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.RequestURI(),
+			Timestamp: c.GetHeader(TimestampHeader),
+			Nonce:     c.GetHeader(NonceHeader),
+			BodyHash:  service.HashRequestBody(body),
+			Signature: signature,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrRequestSignatureInvalid):
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "请求签名无效")
+			case errors.Is(err, service.ErrRequestSignatureExpired):
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "请求时间戳超出允许的时钟偏差")
+			case errors.Is(err, service.ErrRequestReplayed):
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "请求 nonce 已被使用")
+			default:
+				logger.Logger.Error("Failed to verify request signature", zap.Error(err))
+				response.Error(c, http.StatusServiceUnavailable, response.CodeInternalError, "认证服务暂时不可用，请稍后重试")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsAPIKeyRequest 返回请求是否通过 API Key 认证
+func IsAPIKeyRequest(c *gin.Context) bool {
+	_, exists := c.Get(APIKeyIDKey)
+	return exists
+}
+
+// GetAPIKeyScopes 返回当前 API Key 的权限范围，请求不是通过 API Key 认证时第二个返回值为 false
+func GetAPIKeyScopes(c *gin.Context) ([]string, bool) {
+	val, exists := c.Get(APIKeyScopesKey)
+	if !exists {
+		return nil, false
+	}
+
+	scopes, ok := val.([]string)
+	return scopes, ok
+}
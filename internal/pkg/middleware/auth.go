@@ -10,14 +10,21 @@ import (
 
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/response"
 	"github.com/bwmspring/go-web3-wallet-backend/internal/apiserver/service"
+	"github.com/bwmspring/go-web3-wallet-backend/model"
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
 )
 
 // UserIDKey 是在 Gin Context 中存储用户 ID 的 Key
 const UserIDKey = "userID"
 
-// JWTAuth 返回 JWT 认证中间件
-func JWTAuth(jwtService service.JWTService) gin.HandlerFunc {
+// SessionIDKey 是在 Gin Context 中存储当前会话 ID 的 Key
+const SessionIDKey = "sessionID"
+
+// RoleKey 是在 Gin Context 中存储当前用户角色的 Key
+const RoleKey = "role"
+
+// JWTAuth 返回 JWT 认证中间件，denylist 不为空时拒绝已被吊销的访问令牌
+func JWTAuth(jwtService service.JWTService, denylist service.TokenDenylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -26,16 +33,13 @@ func JWTAuth(jwtService service.JWTService) gin.HandlerFunc {
 			return
 		}
 
-		parts := strings.SplitN(authHeader, " ", 2)
-		// 检查格式是否是 "Bearer <token>"
-		if !(len(parts) == 2 && strings.ToLower(parts[0]) == "bearer") {
+		tokenString, ok := BearerToken(c)
+		if !ok {
 			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "认证格式错误，应为 'Bearer <token>'")
 			c.Abort()
 			return
 		}
 
-		tokenString := parts[1]
-
 		// 验证 Token
 		claims, err := jwtService.ValidateToken(tokenString)
 		if err != nil {
@@ -45,8 +49,68 @@ func JWTAuth(jwtService service.JWTService) gin.HandlerFunc {
 			return
 		}
 
+		// 检查令牌是否已被吊销；吊销列表不可用时拒绝请求，而不是放行
+		if denylist != nil {
+			revoked, err := denylist.IsRevoked(c.Request.Context(), claims)
+			if err != nil {
+				logger.Logger.Error("Failed to check token revocation",
+					zap.Uint("user_id", claims.UserID), zap.Error(err))
+				response.Error(c, http.StatusServiceUnavailable, response.CodeInternalError, "认证服务暂时不可用，请稍后重试")
+				c.Abort()
+				return
+			}
+			if revoked {
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "认证令牌已被吊销，请重新登录")
+				c.Abort()
+				return
+			}
+		}
+
 		// 核心：将 UserID 存入 Gin Context
 		c.Set(UserIDKey, claims.UserID)
+		c.Set(SessionIDKey, claims.SessionID)
+
+		// 未携带角色的令牌（引入角色前签发）视为普通用户
+		role := claims.Role
+		if role == "" {
+			role = model.RoleUser
+		}
+		c.Set(RoleKey, role)
+
+		c.Next()
+	}
+}
+
+// BearerToken 从 Authorization 请求头中提取 "Bearer <token>" 格式的令牌
+func BearerToken(c *gin.Context) (string, bool) {
+	parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+	if !(len(parts) == 2 && strings.ToLower(parts[0]) == "bearer") || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// RequireRole 返回基于角色的授权中间件，仅允许角色在 roles 中的用户访问，需挂在 JWTAuth 之后
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		role := GetRole(c)
+		if role == "" {
+			response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "用户未登录或认证信息无效")
+			c.Abort()
+			return
+		}
+
+		if _, ok := allowed[role]; !ok {
+			response.Error(c, http.StatusForbidden, response.CodeUnauthorized, "当前角色无权访问该接口")
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -67,3 +131,13 @@ func GetUserID(c *gin.Context) (uint, error) {
 
 	return userID, nil
 }
+
+// GetSessionID 从 Gin Context 中提取签发当前访问令牌的会话 ID，不存在时返回空字符串
+func GetSessionID(c *gin.Context) string {
+	return c.GetString(SessionIDKey)
+}
+
+// GetRole 从 Gin Context 中提取当前认证用户的角色，未认证时返回空字符串
+func GetRole(c *gin.Context) string {
+	return c.GetString(RoleKey)
+}
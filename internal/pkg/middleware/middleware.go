@@ -48,13 +48,13 @@ func DefaultMiddlewares(opts Options) []gin.HandlerFunc {
 	return middlewares
 }
 
-// AuthMiddleware 返回认证中间件，它需要一个 JWTService 实例
-func AuthMiddleware(jwtService interface{}) gin.HandlerFunc {
+// AuthMiddleware 返回认证中间件，它需要一个 JWTService 实例，denylist 用于检查令牌吊销
+func AuthMiddleware(jwtService interface{}, denylist service.TokenDenylist) gin.HandlerFunc {
 	// 确保传入的是 JWTService
 	// 这是一个设计上的取舍，为了保持中间件的通用性，这里进行断言
 	if svc, ok := jwtService.(service.JWTService); ok {
 		// 7. AuthN/AuthZ: 身份验证
-		return JWTAuth(svc)
+		return JWTAuth(svc, denylist)
 	}
 	// 如果传入的不是 service.JWTService，返回一个拒绝所有请求的中间件
 	return func(c *gin.Context) {
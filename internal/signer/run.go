@@ -0,0 +1,96 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// defaultListenAddr 默认只监听本机回环地址
+const defaultListenAddr = "127.0.0.1:9100"
+
+// Run 启动远程签名进程：加载 keystore_dir 下的 Keystore 文件，使用 password_env 中的密码全部解锁，
+// 然后通过 HTTP/JSON 协议对外提供签名服务。
+func Run(configPath string) error {
+	// 1. 加载配置并初始化日志
+	cfg, err := config.LoadConfigFromFile(configPath)
+	if err != nil {
+		return fmt.Errorf("配置加载失败: %w", err)
+	}
+
+	logger.InitLogger(cfg.Server.Environment)
+	defer logger.Logger.Sync()
+
+	serverCfg := cfg.Signer.Server
+	if cfg.Signer.AuthToken == "" {
+		return errors.New("signer.auth_token must be set")
+	}
+	if serverCfg.KeystoreDir == "" {
+		return errors.New("signer.server.keystore_dir must be set")
+	}
+
+	// 2. 加载并解锁 Keystore
+	password, ok := os.LookupEnv(serverCfg.PasswordEnv)
+	if serverCfg.PasswordEnv == "" || !ok {
+		return fmt.Errorf("keystore password environment variable %q is not set", serverCfg.PasswordEnv)
+	}
+
+	keyStore := keystore.NewKeyStore(serverCfg.KeystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	for _, account := range keyStore.Accounts() {
+		if err := keyStore.Unlock(account, password); err != nil {
+			return fmt.Errorf("failed to unlock account %s: %w", account.Address.Hex(), err)
+		}
+	}
+	logger.Logger.Info("Signer keystore unlocked",
+		zap.String("keystore_dir", serverCfg.KeystoreDir),
+		zap.Int("accounts", len(keyStore.Accounts())),
+	)
+
+	// 3. 启动 HTTP 服务
+	listen := serverCfg.Listen
+	if listen == "" {
+		listen = defaultListenAddr
+	}
+
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           NewServer(keyStore, cfg.Signer.AuthToken).Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Logger.Info("Signer is starting", zap.String("listen", listen))
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Logger.Fatal("Signer listen error", zap.Error(err))
+		}
+	}()
+
+	// 4. 优雅关闭
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-quit
+	logger.Logger.Info("Received signal. Shutting down signer", zap.String("signal", sig.String()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("signer forced to shutdown: %w", err)
+	}
+
+	logger.Logger.Info("Signer exiting gracefully")
+	return nil
+}
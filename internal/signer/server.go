@@ -0,0 +1,140 @@
+package signer
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
+)
+
+// Server 是远程签名进程的 HTTP 服务，私钥以 geth Keystore 文件形式保存在本进程中
+type Server struct {
+	keyStore  *keystore.KeyStore
+	authToken string
+}
+
+// NewServer 创建签名服务，keyStore 中的账户需已解锁
+func NewServer(keyStore *keystore.KeyStore, authToken string) *Server {
+	return &Server{
+		keyStore:  keyStore,
+		authToken: authToken,
+	}
+}
+
+// Handler 返回签名服务的路由
+func (s *Server) Handler() http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery(), s.authenticate)
+
+	r.GET(crypto.RemoteSignerPathAccounts, s.listAccounts)
+	r.POST(crypto.RemoteSignerPathSignTx, s.signTx)
+	r.POST(crypto.RemoteSignerPathSignHash, s.signHash)
+
+	return r
+}
+
+// authenticate 校验 Authorization: Bearer <token>，使用常量时间比较
+func (s *Server) authenticate(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+		logger.Logger.Warn("Rejected unauthenticated signer request",
+			zap.String("ip", c.ClientIP()),
+			zap.String("path", c.Request.URL.Path),
+		)
+		abortWithError(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	c.Next()
+}
+
+// listAccounts 处理 GET /v1/accounts
+func (s *Server) listAccounts(c *gin.Context) {
+	resp := crypto.AccountsResponse{Accounts: []common.Address{}}
+	for _, account := range s.keyStore.Accounts() {
+		resp.Accounts = append(resp.Accounts, account.Address)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// signTx 处理 POST /v1/sign/tx
+func (s *Server) signTx(c *gin.Context) {
+	var req crypto.SignTxRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ChainID == nil || len(req.Tx) == 0 {
+		abortWithError(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(req.Tx); err != nil {
+		abortWithError(c, http.StatusBadRequest, "invalid transaction encoding")
+		return
+	}
+
+	account := accounts.Account{Address: req.From}
+	signedTx, err := s.keyStore.SignTx(account, tx, req.ChainID.ToInt())
+	if err != nil {
+		s.signFailed(c, "tx", req.From, err)
+		return
+	}
+
+	encoded, err := signedTx.MarshalBinary()
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, "failed to encode signed transaction")
+		return
+	}
+
+	logger.Logger.Info("Transaction signed",
+		zap.String("from", req.From.Hex()),
+		zap.String("chain_id", req.ChainID.String()),
+		zap.Uint64("nonce", tx.Nonce()),
+		zap.String("tx_hash", signedTx.Hash().Hex()),
+	)
+	c.JSON(http.StatusOK, crypto.SignTxResponse{SignedTx: encoded})
+}
+
+// signHash 处理 POST /v1/sign/hash
+func (s *Server) signHash(c *gin.Context) {
+	var req crypto.SignHashRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Hash) != common.HashLength {
+		abortWithError(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	signature, err := s.keyStore.SignHash(accounts.Account{Address: req.From}, req.Hash)
+	if err != nil {
+		s.signFailed(c, "hash", req.From, err)
+		return
+	}
+
+	logger.Logger.Info("Hash signed", zap.String("from", req.From.Hex()))
+	c.JSON(http.StatusOK, crypto.SignHashResponse{Signature: signature})
+}
+
+// signFailed 将 Keystore 错误映射为响应：未持有或未解锁的账户返回 404
+func (s *Server) signFailed(c *gin.Context, kind string, from common.Address, err error) {
+	if errors.Is(err, accounts.ErrUnknownAccount) || errors.Is(err, keystore.ErrLocked) {
+		abortWithError(c, http.StatusNotFound, "account not available: "+from.Hex())
+		return
+	}
+
+	logger.Logger.Error("Failed to sign",
+		zap.String("kind", kind),
+		zap.String("from", from.Hex()),
+		zap.Error(err),
+	)
+	abortWithError(c, http.StatusInternalServerError, "signing failed")
+}
+
+func abortWithError(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, crypto.RemoteSignerError{Error: message})
+}
@@ -67,4 +67,367 @@ CREATE TABLE wallets (
 );
 
 -- 为常用查询字段创建索引
-CREATE INDEX idx_wallets_chain_id ON wallets (chain_id);
\ No newline at end of file
+CREATE INDEX idx_wallets_chain_id ON wallets (chain_id);
+
+---
+
+
+-- 创建 nonce_cursors 表：记录每个 (chain_id, address) 下一个待分配的 nonce
+CREATE TABLE nonce_cursors (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMP WITH TIME ZONE,
+    updated_at       TIMESTAMP WITH TIME ZONE,
+
+    chain_id         BIGINT NOT NULL,
+    address          VARCHAR(42) NOT NULL,
+    next_nonce       BIGINT NOT NULL,
+
+    -- 分配时对该行加 FOR UPDATE 行锁
+    CONSTRAINT idx_nonce_cursors_chain_address UNIQUE (chain_id, address)
+);
+
+-- 创建 nonce_reservations 表：记录单个 nonce 的分配状态 (reserved / broadcast / released)
+CREATE TABLE nonce_reservations (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMP WITH TIME ZONE,
+    updated_at       TIMESTAMP WITH TIME ZONE,
+
+    chain_id         BIGINT NOT NULL,
+    address          VARCHAR(42) NOT NULL,
+    nonce            BIGINT NOT NULL,
+    status           VARCHAR(20) NOT NULL,
+
+    CONSTRAINT idx_nonce_reservations_chain_address_nonce UNIQUE (chain_id, address, nonce)
+);
+
+
+---
+
+
+-- 创建 transactions 表：记录由本系统签名并广播的交易
+CREATE TABLE transactions (
+    id                        BIGSERIAL PRIMARY KEY,
+    created_at                TIMESTAMP WITH TIME ZONE,
+    updated_at                TIMESTAMP WITH TIME ZONE,
+
+    user_id                   BIGINT NOT NULL,
+    chain_id                  BIGINT NOT NULL,
+
+    -- 交易内容（金额与手续费单位均为 Wei）
+    from_address              VARCHAR(42) NOT NULL,
+    to_address                VARCHAR(42) NOT NULL,
+    value                     NUMERIC(78, 0) NOT NULL,
+    nonce                     BIGINT NOT NULL,
+    gas_limit                 BIGINT NOT NULL,
+    gas_price                 NUMERIC(78, 0),           -- legacy 交易
+    max_fee_per_gas           NUMERIC(78, 0),           -- EIP-1559 交易
+    max_priority_fee_per_gas  NUMERIC(78, 0),           -- EIP-1559 交易
+
+    -- 链上标识
+    hash                      VARCHAR(66) NOT NULL,
+    raw_tx                    TEXT NOT NULL,            -- 已签名交易字节 (0x hex)
+    status                    VARCHAR(20) NOT NULL,
+
+    CONSTRAINT idx_transactions_hash UNIQUE (hash)
+);
+
+CREATE INDEX idx_transactions_user_id ON transactions (user_id);
+CREATE INDEX idx_transactions_from_address ON transactions (from_address);
+CREATE INDEX idx_transactions_to_address ON transactions (to_address);
+CREATE INDEX idx_transactions_chain_status ON transactions (chain_id, status);
+CREATE INDEX idx_transactions_created_at ON transactions (created_at);
+
+
+---
+
+
+-- transactions 表增加回执字段：由后台 ReceiptTracker 填充，发生重组时会被清空
+ALTER TABLE transactions
+    ADD COLUMN block_number         BIGINT,
+    ADD COLUMN block_hash           VARCHAR(66),
+    ADD COLUMN gas_used             BIGINT,
+    ADD COLUMN effective_gas_price  NUMERIC(78, 0),
+    ADD COLUMN confirmed_at         TIMESTAMP WITH TIME ZONE;
+
+
+---
+
+
+-- transactions 表增加替换关系字段：加速/取消交易与原交易使用同一 nonce，通过哈希双向关联
+ALTER TABLE transactions
+    ADD COLUMN replaces_hash     VARCHAR(66),
+    ADD COLUMN replaced_by_hash  VARCHAR(66);
+
+
+---
+
+
+-- transactions 表增加 ERC-20 代币合约地址：代币转账时 to_address / value 记录代币收款方与代币金额
+ALTER TABLE transactions
+    ADD COLUMN token_address  VARCHAR(42);
+
+CREATE INDEX idx_transactions_token_address ON transactions (token_address);
+
+
+---
+
+
+-- 支持导入钱包：私钥 / Keystore 导入的钱包不关联助记词，mnemonic_id 允许为 NULL
+ALTER TABLE wallets
+    ALTER COLUMN mnemonic_id DROP NOT NULL,
+    ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'generated'; -- generated / imported_mnemonic / imported_private_key / imported_keystore
+
+ALTER TABLE mnemonic_seeds
+    ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'generated'; -- generated / imported
+
+
+---
+
+
+-- 创建 key_export_logs 表：Keystore 导出 / 助记词查看的审计记录（含失败尝试），同时用于按用户限流
+CREATE TABLE key_export_logs (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMP WITH TIME ZONE,
+
+    user_id          BIGINT NOT NULL,
+    wallet_address   VARCHAR(42) NOT NULL,
+    export_type      VARCHAR(20) NOT NULL,  -- keystore / mnemonic
+
+    success          BOOLEAN NOT NULL,
+    failure_reason   VARCHAR(64),           -- rate_limited / invalid_account_password / ...
+
+    client_ip        VARCHAR(64),
+    user_agent       VARCHAR(255)
+);
+
+CREATE INDEX idx_key_export_logs_user_id_created_at ON key_export_logs (user_id, created_at);
+
+
+---
+
+
+-- mnemonic_seeds 表增加信封加密字段：encrypted_seed 由每条记录独立的数据密钥加密，数据密钥由主密钥包装
+-- master_key_id 为 NULL 表示启用信封加密前写入的记录，可通过 `wallet-backend rotate-master-key` 补做信封加密
+ALTER TABLE mnemonic_seeds
+    ADD COLUMN wrapped_data_key  TEXT,
+    ADD COLUMN master_key_id     VARCHAR(64);
+
+CREATE INDEX idx_mnemonic_seeds_master_key_id ON mnemonic_seeds (master_key_id);
+
+
+---
+
+
+-- wallets 表增加签名方式：keystore 由服务端解密 Keystore 签名，remote 由独立的签名进程签名（encrypted_key 为空）
+ALTER TABLE wallets
+    ADD COLUMN signer_type VARCHAR(20) NOT NULL DEFAULT 'keystore';
+
+
+---
+
+
+-- users 表增加 SIWE 关联地址：通过 Sign-In with Ethereum 创建的用户以地址为用户名，password_hash 为空
+ALTER TABLE users
+    ADD COLUMN ethereum_address VARCHAR(42);
+
+CREATE UNIQUE INDEX idx_users_ethereum_address ON users (ethereum_address);
+
+
+-- 创建 siwe_nonces 表：服务端签发的一次性 nonce，校验通过后立即删除
+CREATE TABLE siwe_nonces (
+    id          BIGSERIAL PRIMARY KEY,
+    nonce       VARCHAR(64) NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_siwe_nonces_nonce ON siwe_nonces (nonce);
+CREATE INDEX idx_siwe_nonces_expires_at ON siwe_nonces (expires_at);
+
+
+---
+
+
+-- 创建 sessions 表：每条记录对应一个刷新令牌（只保存 SHA-256），同一次登录的令牌共享 family_id
+-- 刷新时轮换：旧令牌写入 rotated_at 并新增一条记录；已轮换的令牌再次使用即注销整个 family
+CREATE TABLE sessions (
+    id           BIGSERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE,
+
+    family_id    VARCHAR(64) NOT NULL,
+    user_id      BIGINT NOT NULL,
+    token_hash   VARCHAR(64) NOT NULL,
+    started_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    rotated_at   TIMESTAMP WITH TIME ZONE,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+
+    client_ip    VARCHAR(64),
+    user_agent   VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX idx_sessions_token_hash ON sessions (token_hash);
+CREATE INDEX idx_sessions_family_id ON sessions (family_id);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+
+
+---
+
+
+-- 创建 revoked_tokens 表：被单独吊销的访问令牌 (jti)，expires_at 之后可清理
+CREATE TABLE revoked_tokens (
+    jti         VARCHAR(64) PRIMARY KEY,
+    user_id     BIGINT NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_revoked_tokens_user_id ON revoked_tokens (user_id);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- 创建 user_token_revocations 表：签发时间不晚于 revoked_before 的访问令牌全部失效
+CREATE TABLE user_token_revocations (
+    user_id         BIGINT PRIMARY KEY,
+    revoked_before  TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at      TIMESTAMP WITH TIME ZONE
+);
+
+
+---
+
+
+-- 创建 user_totps 表：用户绑定的 TOTP 密钥，confirmed_at 为空表示尚未确认绑定
+-- 配置 key_management 时 secret 为信封加密的密文
+CREATE TABLE user_totps (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           BIGINT NOT NULL,
+
+    secret            TEXT NOT NULL,
+    wrapped_data_key  TEXT,
+    master_key_id     VARCHAR(64),
+
+    confirmed_at      TIMESTAMP WITH TIME ZONE,
+    last_used_step    BIGINT NOT NULL DEFAULT 0,
+
+    created_at        TIMESTAMP WITH TIME ZONE,
+    updated_at        TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_user_totps_user_id ON user_totps (user_id);
+CREATE INDEX idx_user_totps_master_key_id ON user_totps (master_key_id);
+
+-- 创建 recovery_codes 表：一次性恢复码（只保存加用户 ID 前缀的 SHA-256），重新生成时整体替换
+CREATE TABLE recovery_codes (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL,
+    code_hash   VARCHAR(64) NOT NULL,
+    used_at     TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
+
+
+---
+
+
+-- users 表增加角色与停用字段：role 为 user / operator / admin / auditor，disabled_at 非空表示账户已被停用
+ALTER TABLE users
+    ADD COLUMN role         VARCHAR(20) NOT NULL DEFAULT 'user',
+    ADD COLUMN disabled_at  TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_role ON users (role);
+
+
+---
+
+
+-- 创建 api_keys 表：服务端集成使用的 API Key，只保存完整密钥的 SHA-256 与可见前缀
+CREATE TABLE api_keys (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+
+    prefix        VARCHAR(16) NOT NULL,
+    key_hash      VARCHAR(64) NOT NULL,
+
+    scopes        TEXT NOT NULL,          -- 空格分隔：wallet:read / wallet:transfer / tx:read
+    allowed_ips   TEXT,                   -- 逗号分隔的 IP / CIDR，为空表示不限
+
+    expires_at    TIMESTAMP WITH TIME ZONE,
+    revoked_at    TIMESTAMP WITH TIME ZONE,
+    last_used_at  TIMESTAMP WITH TIME ZONE,
+
+    created_at    TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys (prefix);
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
+
+
+---
+
+
+-- api_keys 表增加请求签名字段：signing_secret 为 HMAC 密钥（配置 key_management 时为信封加密的密文）
+ALTER TABLE api_keys
+    ADD COLUMN signing_secret     TEXT,
+    ADD COLUMN wrapped_data_key   TEXT,
+    ADD COLUMN master_key_id      VARCHAR(64),
+    ADD COLUMN require_signature  BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_api_keys_master_key_id ON api_keys (master_key_id);
+
+-- 创建 api_request_nonces 表：签名请求已使用的 nonce，超出时钟偏差窗口后可清理
+CREATE TABLE api_request_nonces (
+    api_key_id  BIGINT NOT NULL,
+    nonce       VARCHAR(64) NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (api_key_id, nonce)
+);
+
+CREATE INDEX idx_api_request_nonces_expires_at ON api_request_nonces (expires_at);
+
+
+---
+
+
+-- 创建 failed_attempts 表：按用户名 (login) 与钱包地址 (wallet) 记录连续失败次数，用于指数退避与临时锁定
+CREATE TABLE failed_attempts (
+    kind            VARCHAR(20) NOT NULL,
+    subject         VARCHAR(100) NOT NULL,
+    failures        INTEGER NOT NULL DEFAULT 0,
+    locked_until    TIMESTAMP WITH TIME ZONE,
+    last_failed_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (kind, subject)
+);
+
+CREATE INDEX idx_failed_attempts_expires_at ON failed_attempts (expires_at);
+
+
+---
+
+
+-- 创建 password_reset_tokens 表：找回密码的一次性令牌，token_hash 为令牌的 SHA-256
+CREATE TABLE password_reset_tokens (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL,
+    token_hash  VARCHAR(64) NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at     TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_password_reset_tokens_token_hash ON password_reset_tokens (token_hash);
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
+CREATE INDEX idx_password_reset_tokens_expires_at ON password_reset_tokens (expires_at);
+
+
+---
+
+
+-- mnemonic_seeds：每个用户最多一条系统生成的助记词，防止并发创建 HD 钱包时生成多条助记词
+-- 执行前需确认不存在重复记录：SELECT user_id FROM mnemonic_seeds WHERE source = 'generated' GROUP BY user_id HAVING COUNT(*) > 1;
+CREATE UNIQUE INDEX idx_mnemonic_seeds_user_generated ON mnemonic_seeds (user_id) WHERE source = 'generated';
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// API Key 权限范围
+const (
+	ScopeWalletRead     = "wallet:read"     // 查询余额与资产
+	ScopeWalletTransfer = "wallet:transfer" // 发起转账，加速 / 取消交易
+	ScopeTxRead         = "tx:read"         // 查询交易记录
+)
+
+// Scopes 是全部合法的 API Key 权限范围
+var Scopes = map[string]bool{
+	ScopeWalletRead:     true,
+	ScopeWalletTransfer: true,
+	ScopeTxRead:         true,
+}
+
+// APIKey 是供服务端集成使用的长期凭证，只保存完整密钥的 SHA-256。严格对应 'api_keys' 数据库表。
+type APIKey struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;index"`
+	Name   string `gorm:"size:100;not null"`
+
+	// Prefix 是密钥开头的可见部分，用于在列表和日志中识别密钥；KeyHash 是完整密钥的 SHA-256
+	Prefix  string `gorm:"size:16;not null;uniqueIndex"`
+	KeyHash string `gorm:"size:64;not null;uniqueIndex"`
+
+	// Scopes 为空格分隔的权限范围，见 Scope*；AllowedIPs 为逗号分隔的 IP / CIDR，为空表示不限
+	Scopes     string `gorm:"type:text;not null"`
+	AllowedIPs string `gorm:"type:text"`
+
+	// SigningSecret 是请求签名 (HMAC-SHA256) 使用的密钥，配置 key_management 时为信封加密的密文，
+	// WrappedDataKey / MasterKeyID 含义同 UserTOTP；RequireSignature 为 true 时该密钥的请求必须签名
+	SigningSecret    string `gorm:"type:text"`
+	WrappedDataKey   string `gorm:"type:text"`
+	MasterKeyID      string `gorm:"size:64;index"`
+	RequireSignature bool   `gorm:"not null;default:false"`
+
+	// ExpiresAt 为空表示永不过期；RevokedAt 非空表示已被用户吊销
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	LastUsedAt *time.Time
+
+	CreatedAt time.Time
+}
+
+// APIRequestNonce 记录签名请求已使用的 nonce，过期前再次出现即视为重放。严格对应 'api_request_nonces' 数据库表。
+type APIRequestNonce struct {
+	APIKeyID  uint      `gorm:"primaryKey"`
+	Nonce     string    `gorm:"primaryKey;size:64"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
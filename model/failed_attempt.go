@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// 失败尝试计数的类型
+const (
+	AttemptKindLogin    = "login"    // 用户名密码登录，Subject 为用户名
+	AttemptKindWallet   = "wallet"   // 钱包密码解锁 Keystore，Subject 为钱包地址
+	AttemptKindMnemonic = "mnemonic" // 钱包密码解密助记词，Subject 为用户 ID
+	AttemptKindTOTP     = "totp"     // 第二因子验证码（TOTP 与恢复码），Subject 为用户 ID
+)
+
+// FailedAttempt 记录某个用户名或钱包地址的连续失败次数与锁定时间，成功后删除。
+// ExpiresAt 之后计数重新开始，记录可清理。严格对应 'failed_attempts' 数据库表。
+type FailedAttempt struct {
+	Kind         string     `gorm:"primaryKey;size:20"`
+	Subject      string     `gorm:"primaryKey;size:100"`
+	Failures     int        `gorm:"not null;default:0"`
+	LockedUntil  *time.Time // 在此之前拒绝尝试（指数退避或锁定）
+	LastFailedAt time.Time  `gorm:"not null"`
+	ExpiresAt    time.Time  `gorm:"not null;index"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// 密钥导出类型
+const (
+	KeyExportTypeKeystore = "keystore"
+	KeyExportTypeMnemonic = "mnemonic"
+)
+
+// KeyExportLog 记录每一次 Keystore 导出 / 助记词查看的尝试（含失败），用于审计与频率限制。
+// 严格对应 'key_export_logs' 数据库表，只追加不修改。
+type KeyExportLog struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index"`
+
+	UserID        uint   `gorm:"not null;index"`
+	WalletAddress string `gorm:"size:42;not null"`
+	ExportType    string `gorm:"size:20;not null"` // 见 KeyExportType*
+
+	// 结果：失败时 FailureReason 记录失败原因（如 invalid_password、rate_limited），不含任何敏感内容
+	Success       bool   `gorm:"not null"`
+	FailureReason string `gorm:"size:64"`
+
+	// 请求来源
+	ClientIP  string `gorm:"size:64"`
+	UserAgent string `gorm:"size:255"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Nonce 预留状态
+const (
+	NonceStatusReserved  = "reserved"  // 已分配，交易尚未广播
+	NonceStatusBroadcast = "broadcast" // 交易已成功广播
+	NonceStatusReleased  = "released"  // 广播失败后释放，可被再次分配
+)
+
+// NonceCursor 记录某地址在某条链上下一个待分配的 nonce。严格对应 'nonce_cursors' 数据库表。
+// 分配时对该行加 FOR UPDATE 行锁，以串行化同一地址的并发发送。
+type NonceCursor struct {
+	ID        uint   `gorm:"primaryKey"`
+	ChainID   uint   `gorm:"not null;uniqueIndex:idx_nonce_cursors_chain_address"`
+	Address   string `gorm:"size:42;not null;uniqueIndex:idx_nonce_cursors_chain_address"`
+	NextNonce uint64 `gorm:"not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NonceReservation 记录单个 nonce 的分配状态。严格对应 'nonce_reservations' 数据库表。
+type NonceReservation struct {
+	ID      uint   `gorm:"primaryKey"`
+	ChainID uint   `gorm:"not null;uniqueIndex:idx_nonce_reservations_chain_address_nonce"`
+	Address string `gorm:"size:42;not null;uniqueIndex:idx_nonce_reservations_chain_address_nonce"`
+	Nonce   uint64 `gorm:"not null;uniqueIndex:idx_nonce_reservations_chain_address_nonce"`
+	Status  string `gorm:"size:20;not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// PasswordResetToken 是找回密码的一次性令牌，只保存令牌的 SHA-256。严格对应 'password_reset_tokens' 数据库表。
+type PasswordResetToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time // 已使用或因签发新令牌、密码已重置而作废
+	CreatedAt time.Time
+}
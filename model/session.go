@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Session 记录一个刷新令牌。同一次登录产生的令牌在每次轮换时于同一 FamilyID 下新增一条记录，
+// FamilyID 即对用户可见的会话（设备）ID。严格对应 'sessions' 数据库表。
+type Session struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time // 本条令牌的签发时间，即会话最近一次活跃时间
+
+	FamilyID  string    `gorm:"size:64;not null;index"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"` // 刷新令牌的 SHA-256，不保存明文
+	StartedAt time.Time `gorm:"not null"`                     // 会话首次登录时间，轮换时保持不变
+	ExpiresAt time.Time `gorm:"not null"`
+
+	// RotatedAt 非空表示该令牌已被使用并轮换，再次出现即视为重放；RevokedAt 非空表示会话已注销
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+
+	// 登录 / 最近一次刷新的请求来源
+	ClientIP  string `gorm:"size:64"`
+	UserAgent string `gorm:"size:255"`
+}
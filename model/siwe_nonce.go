@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// SIWENonce 是服务端签发的 Sign-In with Ethereum nonce，校验通过后立即删除以保证只能使用一次。
+// 严格对应 'siwe_nonces' 数据库表。
+type SIWENonce struct {
+	ID        uint      `gorm:"primaryKey"`
+	Nonce     string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
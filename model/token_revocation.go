@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// RevokedToken 记录被单独吊销的访问令牌 (jti)，或以 "session:" 前缀记录被注销的会话（该会话的访问令牌全部失效），
+// 令牌自然过期后即可清理。严格对应 'revoked_tokens' 数据库表。
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:64"`
+	UserID    uint      `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"` // 访问令牌原本的过期时间
+	CreatedAt time.Time
+}
+
+// UserTokenRevocation 记录用户的令牌吊销时间点：签发时间不晚于 RevokedBefore 的访问令牌全部失效。
+// 每个用户一条记录，时间点只会前移。严格对应 'user_token_revocations' 数据库表。
+type UserTokenRevocation struct {
+	UserID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	RevokedBefore time.Time `gorm:"not null"`
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// UserTOTP 记录用户绑定的 TOTP (RFC 6238) 密钥，每个用户最多一条。严格对应 'user_totps' 数据库表。
+type UserTOTP struct {
+	ID     uint `gorm:"primaryKey"`
+	UserID uint `gorm:"not null;uniqueIndex"`
+
+	// Secret 为 Base32 密钥；启用信封加密时为密文，WrappedDataKey 为主密钥 MasterKeyID 包装后的数据密钥。
+	// MasterKeyID 为空表示未做信封加密。
+	Secret         string `gorm:"type:text;not null"`
+	WrappedDataKey string `gorm:"type:text"`
+	MasterKeyID    string `gorm:"size:64;index"`
+
+	// ConfirmedAt 为空表示用户尚未使用验证码确认绑定，此时不要求第二因子
+	ConfirmedAt *time.Time
+	// LastUsedStep 是最近一次通过校验的时间步，不大于该值的验证码视为重放
+	LastUsedStep int64 `gorm:"not null;default:0"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RecoveryCode 是 TOTP 设备丢失时使用的一次性恢复码，只保存加用户 ID 前缀的 SHA-256。严格对应 'recovery_codes' 数据库表。
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	CodeHash  string     `gorm:"size:64;not null"`
+	UsedAt    *time.Time // 非空表示已使用
+	CreatedAt time.Time
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// 交易状态
+const (
+	TxStatusPending   = "pending"   // 已广播，等待上链
+	TxStatusMined     = "mined"     // 已打包，确认数尚未达到链配置的 confirmations
+	TxStatusConfirmed = "confirmed" // 已达到确认深度且执行成功
+	TxStatusFailed    = "failed"    // 已达到确认深度但执行失败 (receipt.status = 0)
+	TxStatusDropped   = "dropped"   // 被节点丢弃，或 nonce 已被其他交易占用
+	TxStatusReplaced  = "replaced"  // 已被加速/取消交易替换，且同 nonce 的替换交易已上链
+)
+
+// TxStatuses 列出所有合法的交易状态，用于校验查询参数
+var TxStatuses = map[string]bool{
+	TxStatusPending:   true,
+	TxStatusMined:     true,
+	TxStatusConfirmed: true,
+	TxStatusFailed:    true,
+	TxStatusDropped:   true,
+	TxStatusReplaced:  true,
+}
+
+// Transaction 代表一笔由本系统签名并广播的链上交易。严格对应 'transactions' 数据库表。
+// 金额与手续费均以 Wei 为单位、使用十进制字符串存储，避免大整数精度丢失。
+type Transaction struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+
+	// 归属信息
+	UserID  uint `gorm:"not null;index"                               json:"-"`
+	ChainID uint `gorm:"not null;index:idx_transactions_chain_status" json:"chain_id"`
+
+	// 交易内容
+	FromAddress string `gorm:"size:42;not null;index"      json:"from_address"`
+	ToAddress   string `gorm:"size:42;not null;index"      json:"to_address"`
+	Value       string `gorm:"type:numeric(78,0);not null" json:"value"` // Wei；ERC-20 转账为代币最小单位
+	Nonce       uint64 `gorm:"not null"                    json:"nonce"`
+	GasLimit    uint64 `gorm:"not null"                    json:"gas_limit"`
+
+	// ERC-20 转账的代币合约地址，原生币转账为空；非空时 ToAddress 为代币收款方而非交易的 to
+	TokenAddress *string `gorm:"size:42;index" json:"token_address,omitempty"`
+
+	// 手续费：legacy 交易只有 GasPrice，EIP-1559 交易只有 MaxFeePerGas/MaxPriorityFeePerGas
+	GasPrice             *string `gorm:"type:numeric(78,0)" json:"gas_price,omitempty"`
+	MaxFeePerGas         *string `gorm:"type:numeric(78,0)" json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas *string `gorm:"type:numeric(78,0)" json:"max_priority_fee_per_gas,omitempty"`
+
+	// 链上标识
+	Hash   string `gorm:"size:66;uniqueIndex;not null"                         json:"hash"`
+	RawTx  string `gorm:"type:text;not null"                                   json:"raw_tx"` // 0x 前缀的已签名交易字节
+	Status string `gorm:"size:20;not null;index:idx_transactions_chain_status" json:"status"`
+
+	// 替换关系：加速/取消交易与原交易使用同一 nonce，通过哈希双向关联
+	ReplacesHash   *string `gorm:"size:66" json:"replaces_hash,omitempty"`    // 本交易替换的原交易
+	ReplacedByHash *string `gorm:"size:66" json:"replaced_by_hash,omitempty"` // 替换本交易的最新交易
+
+	// 回执信息，由后台 ReceiptTracker 填充；发生重组时会被清空
+	BlockNumber       *uint64    `                          json:"block_number,omitempty"`
+	BlockHash         *string    `gorm:"size:66"            json:"block_hash,omitempty"`
+	GasUsed           *uint64    `                          json:"gas_used,omitempty"`
+	EffectiveGasPrice *string    `gorm:"type:numeric(78,0)" json:"effective_gas_price,omitempty"`
+	ConfirmedAt       *time.Time `                          json:"confirmed_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `             json:"updated_at"`
+}
@@ -6,6 +6,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// 用户角色
+const (
+	RoleUser     = "user"     // 普通用户，只能访问自己的资源
+	RoleOperator = "operator" // 运维人员：查看用户与钱包、触发维护任务
+	RoleAdmin    = "admin"    // 管理员：全部管理接口
+	RoleAuditor  = "auditor"  // 审计人员：只读查看用户与钱包
+)
+
+// Roles 是全部合法的角色
+var Roles = map[string]bool{
+	RoleUser:     true,
+	RoleOperator: true,
+	RoleAdmin:    true,
+	RoleAuditor:  true,
+}
+
 // User 代表应用用户实体。严格对应 'users' 数据库表。
 type User struct {
 	// ID 是主键，GORM 会自动设置为 SERIAL PRIMARY KEY
@@ -14,9 +30,18 @@ type User struct {
 	// 用户名用于登录，要求唯一且非空
 	Username string `gorm:"unique;not null;type:varchar(50)" json:"username"`
 
-	// PasswordHash 存储 Bcrypt 哈希后的密码
+	// PasswordHash 存储 Bcrypt 哈希后的密码，通过 SIWE 创建的用户为空（无法使用密码登录）
 	PasswordHash string `gorm:"not null" json:"-"`
 
+	// EthereumAddress 是通过 Sign-In with Ethereum 关联的地址 (EIP-55 格式)，未关联时为 NULL
+	EthereumAddress *string `gorm:"size:42;uniqueIndex" json:"ethereum_address,omitempty"`
+
+	// Role 是用户角色，见 Role*；变更后需重新登录才能生效
+	Role string `gorm:"size:20;not null;default:'user'" json:"role"`
+
+	// DisabledAt 是账户被管理员停用的时间，为空表示账户正常
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+
 	// GORM 自动维护时间戳
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -6,6 +6,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// 钱包来源
+const (
+	WalletSourceGenerated  = "generated"            // 由系统生成的助记词派生
+	WalletSourceMnemonic   = "imported_mnemonic"    // 导入的助记词派生
+	WalletSourcePrivateKey = "imported_private_key" // 导入的私钥
+	WalletSourceKeystore   = "imported_keystore"    // 导入的 V3 Keystore
+	WalletSourceRemote     = "remote_signer"        // 私钥由远程签名进程持有
+	WalletSourceShamir     = "recovered_shamir"     // 由 Shamir 份额重建的助记词派生
+)
+
+// 助记词来源
+const (
+	MnemonicSourceGenerated = "generated"
+	MnemonicSourceImported  = "imported"
+)
+
 // Wallet 代表一个链上钱包实体。严格对应 'wallets' 数据库表。
 type Wallet struct {
 	ID uint `gorm:"primaryKey" json:"id"`
@@ -16,13 +32,15 @@ type Wallet struct {
 	Name    string `gorm:"size:100;not null"`
 	Address string `gorm:"size:42;uniqueIndex;not null"` // 钱包地址
 
-	// 关联到助记词表
-	MnemonicID   uint         `gorm:"not null;index"`
+	// 关联到助记词表；导入私钥或 Keystore 的钱包没有助记词，为 NULL
+	MnemonicID   *uint        `gorm:"index"`
 	MnemonicSeed MnemonicSeed `gorm:"foreignKey:MnemonicID"` // GORM 关系定义
 
 	// 安全信息
-	EncryptedKey   string `gorm:"type:text;not null"` // Keystore JSON
-	DerivationPath string `gorm:"size:255;not null"`  // BIP-44 路径
+	EncryptedKey   string `gorm:"type:text;not null"`                   // Keystore JSON，远程签名的钱包为空
+	DerivationPath string `gorm:"size:255;not null"`                    // BIP-44 路径，非助记词钱包为空
+	Source         string `gorm:"size:32;not null;default:'generated'"` // 钱包来源，见 WalletSource*
+	SignerType     string `gorm:"size:20;not null;default:'keystore'"`  // 签名方式：keystore / remote
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -33,6 +51,12 @@ type Wallet struct {
 type MnemonicSeed struct {
 	ID            uint   `gorm:"primarykey"`
 	UserID        uint   `gorm:"index;not null"`
-	EncryptedSeed string `gorm:"type:text;not null;comment:加密后的BIP39助记词"` // 使用不同的密钥或方法加密
+	EncryptedSeed string `gorm:"type:text;not null;comment:加密后的BIP39助记词"` // 钱包密码加密后，再经数据密钥信封加密
+	Source        string `gorm:"size:32;not null;default:'generated'"`    // 助记词来源，见 MnemonicSource*
 	CreatedAt     time.Time
+
+	// 信封加密：WrappedDataKey 为主密钥 MasterKeyID 包装后的数据密钥。
+	// MasterKeyID 为空表示未启用信封加密前写入的记录，EncryptedSeed 仅由钱包密码加密。
+	WrappedDataKey string `gorm:"type:text"`
+	MasterKeyID    string `gorm:"size:64;index"`
 }
@@ -2,46 +2,60 @@ package conversion
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/shopspring/decimal"
 )
 
-// 以太坊单位：1 Ether = 10^18 Wei
-var ethPrecision = big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil)
+// 以太坊单位：1 Gwei = 10^9 Wei
+var gweiPrecision = big.NewInt(0).Exp(big.NewInt(10), big.NewInt(9), nil)
 
-// WeiToEther 将 Wei (大整数) 转换为 Ether (人类可读的字符串，使用 decimal 类型保持精度)
-// Wei is a *big.Int, Ether is a string representation of decimal.
-func WeiToEther(wei *big.Int) decimal.Decimal {
-	// 将 Wei 转换为 decimal
-	weiDecimal := decimal.NewFromBigInt(wei, 0)
+// EtherDecimals 以太坊原生币的小数位数
+const EtherDecimals = 18
+
+// FromBaseUnits 将链上最小单位的整数金额按 decimals 换算为人类可读的 decimal
+// 例如 ERC-20 USDC (decimals=6) 的 1500000 换算为 1.5
+func FromBaseUnits(amount *big.Int, decimals uint8) decimal.Decimal {
+	return decimal.NewFromBigInt(amount, -int32(decimals))
+}
 
-	// 除以 10^18 (ethPrecision)，得到 Ether
-	// 我们使用 1e18 的 decimal 表示来除，避免 *big.Int 和 decimal 混合计算
-	ethPrecisionDecimal := decimal.NewFromBigInt(ethPrecision, 0)
+// ToBaseUnits 将人类可读的金额按 decimals 换算为链上最小单位的整数金额，
+// 小数位数超过 decimals 时返回错误（避免静默截断）
+func ToBaseUnits(amountDecimal decimal.Decimal, decimals uint8) (*big.Int, error) {
+	scaled := amountDecimal.Shift(int32(decimals))
 
-	// 计算结果
-	ether := weiDecimal.Div(ethPrecisionDecimal)
+	if !scaled.Equal(scaled.Floor()) {
+		return nil, fmt.Errorf("amount has more than %d decimal places", decimals)
+	}
 
-	// 返回结果，通常保留 18 位精度或更多
-	return ether
+	return scaled.BigInt(), nil
+}
+
+// WeiToEther 将 Wei (大整数) 转换为 Ether (人类可读的字符串，使用 decimal 类型保持精度)
+// Wei is a *big.Int, Ether is a string representation of decimal.
+func WeiToEther(wei *big.Int) decimal.Decimal {
+	return FromBaseUnits(wei, EtherDecimals)
 }
 
 // ToWei 将 Ether (字符串或 decimal) 转换为 Wei (*big.Int)
 // amountDecimal is the amount in Ether (e.g., "1.5"), Wei is a *big.Int
 func ToWei(amountDecimal decimal.Decimal) (*big.Int, error) {
+	return ToBaseUnits(amountDecimal, EtherDecimals)
+}
 
-	// 乘以 10^18 (ethPrecision)
-	weiDecimal := amountDecimal.Mul(decimal.NewFromBigInt(ethPrecision, 0))
+// WeiToGwei 将 Wei 转换为 Gwei，常用于展示手续费
+func WeiToGwei(wei *big.Int) decimal.Decimal {
+	return decimal.NewFromBigInt(wei, 0).Div(decimal.NewFromBigInt(gweiPrecision, 0))
+}
 
-	// 检查是否有小数部分（即 Wei 应该是一个整数）
-	if weiDecimal.String() != weiDecimal.Floor().String() {
-		// 这是理论上不应该发生的，除非输入的 Ether 小数位数过多导致精度溢出
-		return nil, errors.New("wei conversion resulted in non-integer value")
-	}
+// GweiToWei 将 Gwei 转换为 Wei，小数位超过 9 位时返回错误
+func GweiToWei(amountDecimal decimal.Decimal) (*big.Int, error) {
+	weiDecimal := amountDecimal.Mul(decimal.NewFromBigInt(gweiPrecision, 0))
 
-	// 转换为 *big.Int
-	wei := weiDecimal.BigInt()
+	if !weiDecimal.Equal(weiDecimal.Floor()) {
+		return nil, errors.New("gwei amount has more than 9 decimal places")
+	}
 
-	return wei, nil
+	return weiDecimal.BigInt(), nil
 }
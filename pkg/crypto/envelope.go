@@ -0,0 +1,203 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// dataKeySize 是每条记录独立生成的数据密钥 (DEK) 长度，对应 AES-256
+const dataKeySize = 32
+
+var (
+	// ErrMasterKeyNotFound 表示密文引用的主密钥 ID 未在 MasterKeyProvider 中配置
+	ErrMasterKeyNotFound = errors.New("master key not found")
+
+	// ErrEnvelopeCorrupted 表示信封密文或被包装的数据密钥无法通过完整性校验
+	ErrEnvelopeCorrupted = errors.New("envelope ciphertext corrupted or tampered")
+)
+
+// MasterKeyProvider 定义了信封加密中主密钥 (KEK) 的提供者接口。
+// 主密钥只用于包装 / 解包数据密钥，永远不直接加密业务数据。
+type MasterKeyProvider interface {
+	// ActiveKeyID 返回当前用于包装新数据密钥的主密钥 ID
+	ActiveKeyID() string
+
+	// WrapKey 使用当前主密钥包装数据密钥，返回包装结果及所用主密钥 ID
+	WrapKey(ctx context.Context, dataKey []byte) (wrappedKey []byte, keyID string, err error)
+
+	// UnwrapKey 使用 keyID 对应的主密钥解包数据密钥，主密钥不存在时返回 ErrMasterKeyNotFound
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) (dataKey []byte, err error)
+}
+
+// Envelope 是一条信封加密记录：数据由 DEK 加密，DEK 由 KeyID 对应的主密钥包装
+type Envelope struct {
+	Ciphertext string // base64(nonce || AES-256-GCM 密文)
+	WrappedKey string // base64(被包装的 DEK)
+	KeyID      string // 包装 DEK 的主密钥 ID
+}
+
+// EnvelopeCipher 定义了信封加密的接口
+type EnvelopeCipher interface {
+	// Seal 生成新的数据密钥加密 plaintext，并用当前主密钥包装数据密钥
+	Seal(ctx context.Context, plaintext []byte) (*Envelope, error)
+
+	// Open 解包数据密钥并解密信封
+	Open(ctx context.Context, envelope *Envelope) ([]byte, error)
+
+	// Rewrap 使用当前主密钥重新包装信封的数据密钥，Ciphertext 保持不变
+	Rewrap(ctx context.Context, envelope *Envelope) (*Envelope, error)
+
+	// ActiveKeyID 返回当前主密钥 ID
+	ActiveKeyID() string
+}
+
+// envelopeCipher 基于 MasterKeyProvider 实现 EnvelopeCipher
+type envelopeCipher struct {
+	provider MasterKeyProvider
+}
+
+var _ EnvelopeCipher = (*envelopeCipher)(nil)
+
+// NewEnvelopeCipher 创建基于 provider 的信封加密器
+func NewEnvelopeCipher(provider MasterKeyProvider) EnvelopeCipher {
+	return &envelopeCipher{provider: provider}
+}
+
+// ActiveKeyID 返回当前主密钥 ID
+func (e *envelopeCipher) ActiveKeyID() string {
+	return e.provider.ActiveKeyID()
+}
+
+// Seal 生成新的数据密钥加密 plaintext
+func (e *envelopeCipher) Seal(ctx context.Context, plaintext []byte) (*Envelope, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	defer zeroBytes(dataKey)
+
+	ciphertext, err := aesGCMSeal(dataKey, plaintext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with data key: %w", err)
+	}
+
+	wrappedKey, keyID, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return &Envelope{
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+		WrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		KeyID:      keyID,
+	}, nil
+}
+
+// Open 解包数据密钥并解密信封
+func (e *envelopeCipher) Open(ctx context.Context, envelope *Envelope) ([]byte, error) {
+	dataKey, err := e.unwrap(ctx, envelope)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(dataKey)
+
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ciphertext encoding", ErrEnvelopeCorrupted)
+	}
+
+	return aesGCMOpen(dataKey, ciphertext, nil)
+}
+
+// Rewrap 使用当前主密钥重新包装数据密钥，不解密数据本身
+func (e *envelopeCipher) Rewrap(ctx context.Context, envelope *Envelope) (*Envelope, error) {
+	dataKey, err := e.unwrap(ctx, envelope)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(dataKey)
+
+	wrappedKey, keyID, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return &Envelope{
+		Ciphertext: envelope.Ciphertext,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		KeyID:      keyID,
+	}, nil
+}
+
+// unwrap 解包信封中的数据密钥
+func (e *envelopeCipher) unwrap(ctx context.Context, envelope *Envelope) ([]byte, error) {
+	wrappedKey, err := base64.StdEncoding.DecodeString(envelope.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid wrapped key encoding", ErrEnvelopeCorrupted)
+	}
+
+	dataKey, err := e.provider.UnwrapKey(ctx, envelope.KeyID, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with master key %q: %w", envelope.KeyID, err)
+	}
+	if len(dataKey) != dataKeySize {
+		return nil, fmt.Errorf("%w: unexpected data key length %d", ErrEnvelopeCorrupted, len(dataKey))
+	}
+
+	return dataKey, nil
+}
+
+// aesGCMSeal 使用 AES-GCM 加密，输出格式为 nonce || ciphertext
+func aesGCMSeal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// aesGCMOpen 解密 aesGCMSeal 的输出
+func aesGCMOpen(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrEnvelopeCorrupted
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrEnvelopeCorrupted
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// zeroBytes 用完后清零内存中的密钥
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
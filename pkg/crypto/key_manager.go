@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -12,12 +13,41 @@ import (
 	"github.com/tyler-smith/go-bip39"
 )
 
+var (
+	// ErrInvalidPassword 表示 Keystore 或助记词密文的解密密码错误
+	ErrInvalidPassword = errors.New("wallet password incorrect")
+
+	// 导入钱包时的输入校验错误
+	ErrInvalidMnemonic       = errors.New("invalid BIP-39 mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrInvalidPrivateKey     = errors.New("invalid secp256k1 private key")
+	ErrInvalidKeystore       = errors.New("invalid V3 keystore JSON")
+)
+
+// BaseDerivationPath 是以太坊 BIP-44 账户的基础派生路径，末位为账户索引
+const BaseDerivationPath = "m/44'/60'/0'/0"
+
+// DerivationPath 返回指定账户索引的完整 BIP-44 派生路径 (m/44'/60'/0'/0/n)
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf("%s/%d", BaseDerivationPath, index)
+}
+
 // KeyManager 定义了密钥管理工具的接口
 type KeyManager interface {
 	GenerateMnemonic() (string, error)
 	DeriveKeyFromMnemonic(mnemonic string, path string) (privateKeyHex string, address string, err error)
+	DeriveKeyFromMnemonicWithPassphrase(
+		mnemonic string,
+		passphrase string,
+		path string,
+	) (privateKeyHex string, address string, err error)
+	ParsePrivateKey(privateKeyHex string) (normalizedHex string, address string, err error)
 	EncryptPrivateKey(privateKeyHex string, password string) (keystoreJSON string, err error)
 	DecryptKeystore(keystoreJSON string, password string) (privateKeyHex string, err error)
+	EncryptMnemonic(mnemonic string, password string) (encryptedJSON string, err error)
+	DecryptMnemonic(encryptedJSON string, password string) (mnemonic string, err error)
+	SplitMnemonic(mnemonic string, shares int, threshold int) ([]string, error)
+	CombineMnemonic(shares []string) (mnemonic string, err error)
 }
 
 // keyManager 是 KeyManager 接口的实际实现结构体
@@ -47,15 +77,31 @@ func (m *keyManager) DeriveKeyFromMnemonic(
 	mnemonic string,
 	path string,
 ) (privateKeyHex string, address string, err error) {
+	return m.DeriveKeyFromMnemonicWithPassphrase(mnemonic, "", path)
+}
+
+// DeriveKeyFromMnemonicWithPassphrase 根据助记词、BIP-39 passphrase（"第 25 个单词"）和派生路径生成私钥和地址
+// 输入来自用户导入时，助记词或路径非法分别返回 ErrInvalidMnemonic / ErrInvalidDerivationPath。
+func (m *keyManager) DeriveKeyFromMnemonicWithPassphrase(
+	mnemonic string,
+	passphrase string,
+	path string,
+) (privateKeyHex string, address string, err error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return "", "", ErrInvalidMnemonic
+	}
 
 	// 1. 创建 HD 钱包对象
-	wallet, err := hdwallet.NewFromMnemonic(mnemonic)
+	wallet, err := hdwallet.NewFromMnemonic(mnemonic, passphrase)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create HD wallet from mnemonic: %w", err)
 	}
 
 	// 2. 派生路径
-	derivationPath := hdwallet.MustParseDerivationPath(path)
+	derivationPath, err := hdwallet.ParseDerivationPath(path)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidDerivationPath, path)
+	}
 
 	// 3. 派生账户
 	account, err := wallet.Derive(derivationPath, true) // true表示使用硬化派生
@@ -75,6 +121,19 @@ func (m *keyManager) DeriveKeyFromMnemonic(
 	return privateKeyHex, address, nil
 }
 
+// ParsePrivateKey 校验十六进制私钥（可带 0x 前缀），返回规范化的私钥和对应地址
+func (m *keyManager) ParsePrivateKey(privateKeyHex string) (normalizedHex string, address string, err error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return "", "", ErrInvalidPrivateKey
+	}
+
+	normalizedHex = hexutil.Encode(crypto.FromECDSA(privateKey))
+	address = crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+
+	return normalizedHex, address, nil
+}
+
 // EncryptPrivateKey 用密码将私钥加密为Keystore JSON
 func (m *keyManager) EncryptPrivateKey(privateKeyHex string, password string) (keystoreJSON string, err error) {
 
@@ -119,10 +178,11 @@ func (m *keyManager) DecryptKeystore(keystoreJSON string, password string) (priv
 	// 1. 解密
 	key, err := keystore.DecryptKey([]byte(keystoreJSON), password)
 	if err != nil {
-		if strings.Contains(err.Error(), "authentication failed") {
-			return "", errors.New("wallet password incorrect") // Service 层将判断并返回 ErrPasswordIncorrect
+		if errors.Is(err, keystore.ErrDecrypt) {
+			return "", ErrInvalidPassword // Service 层将判断并返回 ErrPasswordIncorrect
 		}
-		return "", fmt.Errorf("keystore decryption failed: %w", err)
+		// 其余错误均为 JSON 格式、版本或加密参数不受支持
+		return "", fmt.Errorf("%w: %w", ErrInvalidKeystore, err)
 	}
 
 	// 2. 转换为 Hex 字符串
@@ -130,3 +190,94 @@ func (m *keyManager) DecryptKeystore(keystoreJSON string, password string) (priv
 
 	return privateKeyHex, nil
 }
+
+// EncryptMnemonic 用密码将助记词加密为 Web3 Secret Storage (V3) 格式的 crypto JSON
+// 与 Keystore 使用相同的 Scrypt KDF + AES-128-CTR 方案，便于统一审计。
+func (m *keyManager) EncryptMnemonic(mnemonic string, password string) (encryptedJSON string, err error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return "", ErrInvalidMnemonic
+	}
+
+	cryptoJSON, err := keystore.EncryptDataV3(
+		[]byte(mnemonic),
+		[]byte(password),
+		keystore.StandardScryptN,
+		keystore.StandardScryptP,
+	)
+	if err != nil {
+		return "", fmt.Errorf("mnemonic encryption failed: %w", err)
+	}
+
+	data, err := json.Marshal(cryptoJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal encrypted mnemonic: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// DecryptMnemonic 用密码解密 EncryptMnemonic 生成的 crypto JSON，返回助记词明文
+func (m *keyManager) DecryptMnemonic(encryptedJSON string, password string) (mnemonic string, err error) {
+	var cryptoJSON keystore.CryptoJSON
+	if err := json.Unmarshal([]byte(encryptedJSON), &cryptoJSON); err != nil {
+		return "", fmt.Errorf("invalid encrypted mnemonic format: %w", err)
+	}
+
+	plain, err := keystore.DecryptDataV3(cryptoJSON, password)
+	if err != nil {
+		if errors.Is(err, keystore.ErrDecrypt) {
+			return "", ErrInvalidPassword
+		}
+		return "", fmt.Errorf("mnemonic decryption failed: %w", err)
+	}
+
+	return string(plain), nil
+}
+
+// SplitMnemonic 将助记词的熵按 Shamir 方案分割为 shares 份，任意 threshold 份可恢复。
+// 返回 EncodeShare 编码的份额字符串，单个份额不泄露助记词的任何信息。
+func (m *keyManager) SplitMnemonic(mnemonic string, shares int, threshold int) ([]string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	defer zeroBytes(entropy)
+
+	split, err := SplitSecret(entropy, shares, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := make([]string, len(split))
+	for i, share := range split {
+		encoded[i] = EncodeShare(share)
+	}
+
+	return encoded, nil
+}
+
+// CombineMnemonic 由不少于 threshold 份的份额恢复助记词
+func (m *keyManager) CombineMnemonic(shares []string) (string, error) {
+	decoded := make([]ShamirShare, 0, len(shares))
+	for _, encoded := range shares {
+		share, err := DecodeShare(encoded)
+		if err != nil {
+			return "", err
+		}
+		decoded = append(decoded, share)
+	}
+
+	entropy, err := CombineShares(decoded)
+	if err != nil {
+		return "", err
+	}
+	defer zeroBytes(entropy)
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		// 熵长度非法，说明份额本身被篡改或不是由助记词分割得到
+		return "", fmt.Errorf("%w: %w", ErrInvalidShare, err)
+	}
+
+	return mnemonic, nil
+}
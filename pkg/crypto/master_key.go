@@ -0,0 +1,184 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+)
+
+// 支持的主密钥提供者类型
+const (
+	MasterKeyProviderLocal = "local"
+	MasterKeyProviderKMS   = "kms"
+)
+
+// masterKeySize 是本地主密钥的长度，对应 AES-256
+const masterKeySize = 32
+
+// ErrEnvelopeDisabled 表示未配置 key_management，信封加密未启用
+var ErrEnvelopeDisabled = errors.New("envelope encryption is not configured")
+
+// localMasterKeyProvider 使用从环境变量或文件加载的本地主密钥，以 AES-256-GCM 包装数据密钥。
+// 可同时加载多把主密钥：只有 activeKeyID 用于包装，其余仅用于解包轮换前的记录。
+type localMasterKeyProvider struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+var _ MasterKeyProvider = (*localMasterKeyProvider)(nil)
+
+// NewLocalMasterKeyProvider 根据主密钥集合创建本地 MasterKeyProvider，每把主密钥必须为 32 字节
+func NewLocalMasterKeyProvider(activeKeyID string, keys map[string][]byte) (MasterKeyProvider, error) {
+	for id, key := range keys {
+		if len(key) != masterKeySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes, got %d", id, masterKeySize, len(key))
+		}
+	}
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrMasterKeyNotFound, activeKeyID)
+	}
+
+	return &localMasterKeyProvider{
+		activeKeyID: activeKeyID,
+		keys:        keys,
+	}, nil
+}
+
+// ActiveKeyID 返回当前主密钥 ID
+func (p *localMasterKeyProvider) ActiveKeyID() string {
+	return p.activeKeyID
+}
+
+// WrapKey 使用当前主密钥包装数据密钥，主密钥 ID 作为附加认证数据防止密文被挪用
+func (p *localMasterKeyProvider) WrapKey(_ context.Context, dataKey []byte) ([]byte, string, error) {
+	wrapped, err := aesGCMSeal(p.keys[p.activeKeyID], dataKey, []byte(p.activeKeyID))
+	if err != nil {
+		return nil, "", err
+	}
+	return wrapped, p.activeKeyID, nil
+}
+
+// UnwrapKey 使用 keyID 对应的主密钥解包数据密钥
+func (p *localMasterKeyProvider) UnwrapKey(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMasterKeyNotFound, keyID)
+	}
+	return aesGCMOpen(key, wrappedKey, []byte(keyID))
+}
+
+// KMSClient 是外部密钥管理服务 (如 AWS KMS、GCP KMS、Vault Transit) 的最小适配接口。
+// 主密钥始终保存在 KMS 内部，本服务只提交数据密钥进行加密 / 解密。
+type KMSClient interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) (ciphertext []byte, err error)
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) (plaintext []byte, err error)
+}
+
+// kmsMasterKeyProvider 将数据密钥的包装委托给 KMSClient
+type kmsMasterKeyProvider struct {
+	client      KMSClient
+	activeKeyID string
+}
+
+var _ MasterKeyProvider = (*kmsMasterKeyProvider)(nil)
+
+// NewKMSMasterKeyProvider 创建基于 KMS 的 MasterKeyProvider，activeKeyID 为 KMS 中的密钥 ID / ARN
+func NewKMSMasterKeyProvider(client KMSClient, activeKeyID string) MasterKeyProvider {
+	return &kmsMasterKeyProvider{
+		client:      client,
+		activeKeyID: activeKeyID,
+	}
+}
+
+// ActiveKeyID 返回当前 KMS 密钥 ID
+func (p *kmsMasterKeyProvider) ActiveKeyID() string {
+	return p.activeKeyID
+}
+
+// WrapKey 调用 KMS Encrypt 包装数据密钥
+func (p *kmsMasterKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	wrapped, err := p.client.Encrypt(ctx, p.activeKeyID, dataKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("kms encrypt failed: %w", err)
+	}
+	return wrapped, p.activeKeyID, nil
+}
+
+// UnwrapKey 调用 KMS Decrypt 解包数据密钥
+func (p *kmsMasterKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	dataKey, err := p.client.Decrypt(ctx, keyID, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt failed: %w", err)
+	}
+	return dataKey, nil
+}
+
+// NewMasterKeyProvider 根据 key_management 配置创建 MasterKeyProvider。
+// 未配置 provider 时返回 ErrEnvelopeDisabled；kms 需要注入具体的 KMSClient，
+// 应直接使用 NewKMSMasterKeyProvider。
+func NewMasterKeyProvider(cfg config.KeyManagementConfig) (MasterKeyProvider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, ErrEnvelopeDisabled
+
+	case MasterKeyProviderLocal:
+		keys := make(map[string][]byte, len(cfg.MasterKeys))
+		for _, keyCfg := range cfg.MasterKeys {
+			if keyCfg.ID == "" {
+				return nil, errors.New("master key id must not be empty")
+			}
+			if _, dup := keys[keyCfg.ID]; dup {
+				return nil, fmt.Errorf("duplicate master key id %q", keyCfg.ID)
+			}
+
+			key, err := loadMasterKey(keyCfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load master key %q: %w", keyCfg.ID, err)
+			}
+			keys[keyCfg.ID] = key
+		}
+		return NewLocalMasterKeyProvider(cfg.ActiveKeyID, keys)
+
+	case MasterKeyProviderKMS:
+		return nil, fmt.Errorf("key_management provider %q requires a KMS client to be injected", cfg.Provider)
+
+	default:
+		return nil, fmt.Errorf("unsupported key_management provider %q", cfg.Provider)
+	}
+}
+
+// loadMasterKey 从环境变量或文件读取 base64 编码的主密钥，环境变量优先
+func loadMasterKey(keyCfg config.MasterKeyConfig) ([]byte, error) {
+	var encoded string
+
+	switch {
+	case keyCfg.Env != "":
+		value, ok := os.LookupEnv(keyCfg.Env)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", keyCfg.Env)
+		}
+		encoded = value
+
+	case keyCfg.File != "":
+		data, err := os.ReadFile(keyCfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		encoded = string(data)
+
+	default:
+		return nil, errors.New("either env or file must be set")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not valid base64: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// 消息签名类型
+const (
+	MessageTypePersonal  = "personal"   // EIP-191 personal_sign
+	MessageTypeTypedData = "typed_data" // EIP-712 eth_signTypedData_v4
+)
+
+// eip712DomainType 是 EIP-712 域的类型名
+const eip712DomainType = "EIP712Domain"
+
+var (
+	// ErrInvalidSignature 表示签名不是合法的 65 字节 secp256k1 签名
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrInvalidTypedData 表示 EIP-712 结构化数据无法编码
+	ErrInvalidTypedData = errors.New("invalid EIP-712 typed data")
+)
+
+// PersonalMessageHash 计算 EIP-191 personal_sign 的消息哈希：
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+func PersonalMessageHash(message []byte) []byte {
+	return accounts.TextHash(message)
+}
+
+// TypedDataHash 计算 EIP-712 结构化数据的签名哈希：keccak256("\x19\x01" || domainSeparator || hashStruct(message))。
+// types 中未声明 EIP712Domain 时，按 domain 中出现的字段补全。
+func TypedDataHash(typedData *apitypes.TypedData) ([]byte, error) {
+	if typedData == nil || typedData.PrimaryType == "" {
+		return nil, fmt.Errorf("%w: primaryType is required", ErrInvalidTypedData)
+	}
+
+	data := *typedData
+	if _, ok := data.Types[eip712DomainType]; !ok {
+		types := make(apitypes.Types, len(data.Types)+1)
+		for name, fields := range data.Types {
+			types[name] = fields
+		}
+		types[eip712DomainType] = domainTypeFields(&data.Domain)
+		data.Types = types
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTypedData, err)
+	}
+
+	return hash, nil
+}
+
+// domainTypeFields 按 EIP-712 规定的字段顺序，返回 domain 中已填写字段的类型声明
+func domainTypeFields(domain *apitypes.TypedDataDomain) []apitypes.Type {
+	var fields []apitypes.Type
+
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+
+	return fields
+}
+
+// RecoverAddress 从 32 字节哈希及其 [R || S || V] 签名恢复签名者地址，V 可以为 0/1 或 27/28
+func RecoverAddress(hash []byte, signature []byte) (common.Address, error) {
+	if len(hash) != common.HashLength {
+		return common.Address{}, ErrInvalidHashLength
+	}
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
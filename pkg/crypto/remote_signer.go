@@ -0,0 +1,207 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 远程签名 HTTP/JSON 协议的路径，请求需携带 Authorization: Bearer <token>
+const (
+	RemoteSignerPathAccounts = "/v1/accounts"
+	RemoteSignerPathSignTx   = "/v1/sign/tx"
+	RemoteSignerPathSignHash = "/v1/sign/hash"
+)
+
+// defaultRemoteSignerTimeout 是远程签名请求的默认超时时间
+const defaultRemoteSignerTimeout = 10 * time.Second
+
+// SignTxRequest 是 POST /v1/sign/tx 的请求体
+type SignTxRequest struct {
+	ChainID *hexutil.Big   `json:"chain_id"`
+	From    common.Address `json:"from"`
+	Tx      hexutil.Bytes  `json:"tx"` // 未签名交易的 MarshalBinary 编码
+}
+
+// SignTxResponse 是 POST /v1/sign/tx 的响应体
+type SignTxResponse struct {
+	SignedTx hexutil.Bytes `json:"signed_tx"` // 已签名交易的 MarshalBinary 编码
+}
+
+// SignHashRequest 是 POST /v1/sign/hash 的请求体
+type SignHashRequest struct {
+	From common.Address `json:"from"`
+	Hash hexutil.Bytes  `json:"hash"`
+}
+
+// SignHashResponse 是 POST /v1/sign/hash 的响应体
+type SignHashResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// AccountsResponse 是 GET /v1/accounts 的响应体
+type AccountsResponse struct {
+	Accounts []common.Address `json:"accounts"`
+}
+
+// RemoteSignerError 是远程签名服务的错误响应体
+type RemoteSignerError struct {
+	Error string `json:"error"`
+}
+
+// AccountSigner 是持有多个账户的 Signer，可查询是否持有某地址
+type AccountSigner interface {
+	Signer
+
+	// HasAccount 检查签名者是否持有 address
+	HasAccount(ctx context.Context, address common.Address) (bool, error)
+}
+
+// RemoteSigner 通过 HTTP/JSON 协议请求独立的签名进程完成签名，私钥不离开签名进程
+type RemoteSigner struct {
+	baseURL   string
+	authToken string
+	client    *http.Client
+}
+
+var _ AccountSigner = (*RemoteSigner)(nil)
+
+// NewRemoteSigner 创建远程签名客户端，timeout <= 0 时使用默认超时
+func NewRemoteSigner(baseURL string, authToken string, timeout time.Duration) *RemoteSigner {
+	if timeout <= 0 {
+		timeout = defaultRemoteSignerTimeout
+	}
+
+	return &RemoteSigner{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		authToken: authToken,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Accounts 返回远程签名进程持有的全部地址
+func (s *RemoteSigner) Accounts(ctx context.Context) ([]common.Address, error) {
+	var resp AccountsResponse
+	if err := s.do(ctx, http.MethodGet, RemoteSignerPathAccounts, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Accounts, nil
+}
+
+// HasAccount 检查远程签名进程是否持有 address
+func (s *RemoteSigner) HasAccount(ctx context.Context, address common.Address) (bool, error) {
+	accounts, err := s.Accounts(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, account := range accounts {
+		if account == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SignTx 请求远程签名进程为交易签名，并校验返回交易的签名者与内容
+func (s *RemoteSigner) SignTx(
+	ctx context.Context,
+	chainID *big.Int,
+	from common.Address,
+	tx *types.Transaction,
+) (*types.Transaction, error) {
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	var resp SignTxResponse
+	req := &SignTxRequest{ChainID: (*hexutil.Big)(chainID), From: from, Tx: unsigned}
+	if err := s.do(ctx, http.MethodPost, RemoteSignerPathSignTx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(resp.SignedTx); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+
+	// 不信任远程返回的内容：签名者必须为 from，且签名哈希与请求的交易一致
+	signer := types.NewLondonSigner(chainID)
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if sender != from || signer.Hash(signedTx) != signer.Hash(tx) {
+		return nil, fmt.Errorf("%w: remote signer returned a transaction for %s", ErrSignerAccountMismatch, sender.Hex())
+	}
+
+	return signedTx, nil
+}
+
+// SignHash 请求远程签名进程对 32 字节哈希签名
+func (s *RemoteSigner) SignHash(ctx context.Context, from common.Address, hash []byte) ([]byte, error) {
+	if len(hash) != common.HashLength {
+		return nil, ErrInvalidHashLength
+	}
+
+	var resp SignHashResponse
+	if err := s.do(ctx, http.MethodPost, RemoteSignerPathSignHash, &SignHashRequest{From: from, Hash: hash}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Signature) != 65 {
+		return nil, fmt.Errorf("remote signer returned a %d-byte signature", len(resp.Signature))
+	}
+
+	return resp.Signature, nil
+}
+
+// do 发送一次 JSON 请求并解码响应
+func (s *RemoteSigner) do(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode remote signer request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build remote signer request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.authToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var remoteErr RemoteSignerError
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&remoteErr)
+
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", ErrSignerAccountMismatch, remoteErr.Error)
+		}
+		return fmt.Errorf("remote signer returned status %d: %s", resp.StatusCode, remoteErr.Error)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode remote signer response: %w", err)
+	}
+
+	return nil
+}
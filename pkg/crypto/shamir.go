@@ -0,0 +1,224 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Shamir 秘密分享 (GF(2^8)，约简多项式 x^8 + x^4 + x^3 + x + 1，与 AES / SLIP-39 相同的有限域)。
+// 对助记词的熵而非助记词文本进行分割，恢复后再编码为 BIP-39 助记词。
+//
+// 份额编码为带前缀的十六进制字符串 "wss1-<hex>"，二进制布局为：
+//
+//	version(1) | threshold(1) | index(1) | id(2) | value(len(secret)) | checksum(4)
+//
+// id 为同一次分割的随机标识，用于拒绝混用不同分割的份额；checksum 为前面所有字节的 SHA-256 前 4 字节。
+const (
+	shamirSharePrefix  = "wss1-"
+	shamirShareVersion = 1
+	shamirHeaderSize   = 5
+	shamirChecksumSize = 4
+
+	// ShamirMaxShares 是单次分割允许的最大份额数
+	ShamirMaxShares = 16
+)
+
+var (
+	ErrInvalidShamirParams = errors.New("shamir threshold must be between 2 and the number of shares")
+	ErrInvalidShare        = errors.New("invalid shamir share")
+	ErrInsufficientShares  = errors.New("not enough shamir shares to reconstruct the secret")
+	ErrShareMismatch       = errors.New("shamir shares belong to different splits")
+)
+
+// gf256Exp / gf256Log 是以 3 为生成元的指数表与对数表
+var (
+	gf256Exp [510]byte
+	gf256Log [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gf256Exp[i] = x
+		gf256Exp[i+255] = x
+		gf256Log[x] = byte(i)
+
+		// x *= 3，即 x ^ (x * 2)
+		hi := x & 0x80
+		doubled := x << 1
+		if hi != 0 {
+			doubled ^= 0x1b
+		}
+		x ^= doubled
+	}
+}
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+// ShamirShare 是解码后的单个份额
+type ShamirShare struct {
+	Threshold byte
+	Index     byte // 多项式求值点 x，取值 1..255
+	ID        [2]byte
+	Value     []byte
+}
+
+// SplitSecret 将 secret 分割为 shares 份，任意 threshold 份即可恢复
+func SplitSecret(secret []byte, shares, threshold int) ([]ShamirShare, error) {
+	if threshold < 2 || threshold > shares || shares > ShamirMaxShares {
+		return nil, ErrInvalidShamirParams
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("secret must not be empty")
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate split id: %w", err)
+	}
+
+	// 每个字节一个 threshold-1 次随机多项式，常数项为 secret 字节
+	coefficients := make([]byte, (threshold-1)*len(secret))
+	if _, err := rand.Read(coefficients); err != nil {
+		return nil, fmt.Errorf("failed to generate polynomial coefficients: %w", err)
+	}
+	defer zeroBytes(coefficients)
+
+	return splitWithCoefficients(secret, shares, threshold, id, coefficients), nil
+}
+
+// splitWithCoefficients 使用给定的 id 与多项式系数分割 secret。
+// coefficients[k*len(secret)+j] 为第 j 个字节多项式的 k+1 次项系数；testdata 中的测试向量即由此生成。
+func splitWithCoefficients(secret []byte, shares, threshold int, id [2]byte, coefficients []byte) []ShamirShare {
+	result := make([]ShamirShare, shares)
+	for i := range result {
+		x := byte(i + 1)
+		value := make([]byte, len(secret))
+
+		for j, s := range secret {
+			// Horner 法求值：从最高次项开始
+			var y byte
+			for k := threshold - 2; k >= 0; k-- {
+				y = gf256Mul(y, x) ^ coefficients[k*len(secret)+j]
+			}
+			value[j] = gf256Mul(y, x) ^ s
+		}
+
+		result[i] = ShamirShare{Threshold: byte(threshold), Index: x, ID: id, Value: value}
+	}
+
+	return result
+}
+
+// CombineShares 使用拉格朗日插值在 x=0 处恢复 secret，份额需来自同一次分割且数量不少于 threshold
+func CombineShares(shares []ShamirShare) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrInsufficientShares
+	}
+
+	first := shares[0]
+	if len(shares) < int(first.Threshold) {
+		return nil, fmt.Errorf("%w: need %d, got %d", ErrInsufficientShares, first.Threshold, len(shares))
+	}
+
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if share.ID != first.ID || share.Threshold != first.Threshold || len(share.Value) != len(first.Value) {
+			return nil, ErrShareMismatch
+		}
+		if share.Index == 0 || seen[share.Index] {
+			return nil, fmt.Errorf("%w: duplicate share index %d", ErrInvalidShare, share.Index)
+		}
+		seen[share.Index] = true
+	}
+
+	// 只需 threshold 份参与插值
+	shares = shares[:first.Threshold]
+
+	secret := make([]byte, len(first.Value))
+	for i, share := range shares {
+		// 拉格朗日基多项式在 0 处的值：prod(x_j / (x_j - x_i))，GF(2^8) 中减法即异或
+		basis := byte(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			basis = gf256Mul(basis, gf256Div(other.Index, other.Index^share.Index))
+		}
+
+		for k, y := range share.Value {
+			secret[k] ^= gf256Mul(y, basis)
+		}
+	}
+
+	return secret, nil
+}
+
+// EncodeShare 将份额编码为 "wss1-<hex>" 字符串
+func EncodeShare(share ShamirShare) string {
+	var buf bytes.Buffer
+	buf.WriteByte(shamirShareVersion)
+	buf.WriteByte(share.Threshold)
+	buf.WriteByte(share.Index)
+	buf.Write(share.ID[:])
+	buf.Write(share.Value)
+
+	checksum := sha256.Sum256(buf.Bytes())
+	buf.Write(checksum[:shamirChecksumSize])
+
+	return shamirSharePrefix + hex.EncodeToString(buf.Bytes())
+}
+
+// DecodeShare 解析 EncodeShare 生成的字符串并校验版本与校验和
+func DecodeShare(encoded string) (ShamirShare, error) {
+	encoded = strings.ToLower(strings.TrimSpace(encoded))
+
+	raw, ok := strings.CutPrefix(encoded, shamirSharePrefix)
+	if !ok {
+		return ShamirShare{}, fmt.Errorf("%w: missing %q prefix", ErrInvalidShare, shamirSharePrefix)
+	}
+
+	data, err := hex.DecodeString(raw)
+	if err != nil || len(data) <= shamirHeaderSize+shamirChecksumSize {
+		return ShamirShare{}, fmt.Errorf("%w: malformed encoding", ErrInvalidShare)
+	}
+
+	body, checksum := data[:len(data)-shamirChecksumSize], data[len(data)-shamirChecksumSize:]
+	expected := sha256.Sum256(body)
+	if !bytes.Equal(checksum, expected[:shamirChecksumSize]) {
+		return ShamirShare{}, fmt.Errorf("%w: checksum mismatch", ErrInvalidShare)
+	}
+	if body[0] != shamirShareVersion {
+		return ShamirShare{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidShare, body[0])
+	}
+
+	share := ShamirShare{
+		Threshold: body[1],
+		Index:     body[2],
+		Value:     body[shamirHeaderSize:],
+	}
+	copy(share.ID[:], body[3:5])
+
+	if share.Threshold < 2 || share.Index == 0 {
+		return ShamirShare{}, fmt.Errorf("%w: invalid header", ErrInvalidShare)
+	}
+
+	return share, nil
+}
@@ -0,0 +1,229 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// shamirVector 对应 testdata/shamir_vectors.json 中的一组测试向量
+type shamirVector struct {
+	Description  string   `json:"description"`
+	Mnemonic     string   `json:"mnemonic"`
+	Entropy      string   `json:"entropy"`
+	Threshold    int      `json:"threshold"`
+	ID           string   `json:"id"`
+	Coefficients string   `json:"coefficients"`
+	Shares       []string `json:"shares"`
+}
+
+func loadShamirVectors(t *testing.T) []shamirVector {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/shamir_vectors.json")
+	if err != nil {
+		t.Fatalf("read vectors: %v", err)
+	}
+
+	var file struct {
+		Vectors []shamirVector `json:"vectors"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("parse vectors: %v", err)
+	}
+	if len(file.Vectors) == 0 {
+		t.Fatal("no vectors in testdata/shamir_vectors.json")
+	}
+
+	return file.Vectors
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode hex %q: %v", s, err)
+	}
+	return b
+}
+
+// subsets 返回 0..n-1 中全部大小为 k 的组合
+func subsets(n, k int) [][]int {
+	var result [][]int
+	var walk func(start int, current []int)
+	walk = func(start int, current []int) {
+		if len(current) == k {
+			result = append(result, append([]int(nil), current...))
+			return
+		}
+		for i := start; i < n; i++ {
+			walk(i+1, append(current, i))
+		}
+	}
+	walk(0, nil)
+	return result
+}
+
+func TestSplitWithCoefficientsVectors(t *testing.T) {
+	for _, v := range loadShamirVectors(t) {
+		t.Run(v.Description, func(t *testing.T) {
+			entropy := mustDecodeHex(t, v.Entropy)
+			coefficients := mustDecodeHex(t, v.Coefficients)
+
+			var id [2]byte
+			copy(id[:], mustDecodeHex(t, v.ID))
+
+			shares := splitWithCoefficients(entropy, len(v.Shares), v.Threshold, id, coefficients)
+			if len(shares) != len(v.Shares) {
+				t.Fatalf("got %d shares, want %d", len(shares), len(v.Shares))
+			}
+			for i, share := range shares {
+				if got := EncodeShare(share); got != v.Shares[i] {
+					t.Errorf("share %d = %s, want %s", i+1, got, v.Shares[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCombineVectorSubsets(t *testing.T) {
+	km := NewKeyManager()
+
+	for _, v := range loadShamirVectors(t) {
+		t.Run(v.Description, func(t *testing.T) {
+			entropy := mustDecodeHex(t, v.Entropy)
+
+			for _, subset := range subsets(len(v.Shares), v.Threshold) {
+				encoded := make([]string, 0, len(subset))
+				decoded := make([]ShamirShare, 0, len(subset))
+				for _, i := range subset {
+					share, err := DecodeShare(v.Shares[i])
+					if err != nil {
+						t.Fatalf("decode share %d: %v", i+1, err)
+					}
+					encoded = append(encoded, v.Shares[i])
+					decoded = append(decoded, share)
+				}
+
+				secret, err := CombineShares(decoded)
+				if err != nil {
+					t.Fatalf("combine %v: %v", subset, err)
+				}
+				if !bytes.Equal(secret, entropy) {
+					t.Errorf("combine %v = %x, want %x", subset, secret, entropy)
+				}
+
+				mnemonic, err := km.CombineMnemonic(encoded)
+				if err != nil {
+					t.Fatalf("combine mnemonic %v: %v", subset, err)
+				}
+				if mnemonic != v.Mnemonic {
+					t.Errorf("combine mnemonic %v = %q, want %q", subset, mnemonic, v.Mnemonic)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitMnemonicRoundTrip(t *testing.T) {
+	km := NewKeyManager()
+	mnemonic := loadShamirVectors(t)[1].Mnemonic
+
+	shares, err := km.SplitMnemonic(mnemonic, 5, 3)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+
+	got, err := km.CombineMnemonic([]string{shares[4], shares[0], shares[2]})
+	if err != nil {
+		t.Fatalf("combine: %v", err)
+	}
+	if got != mnemonic {
+		t.Errorf("combine = %q, want %q", got, mnemonic)
+	}
+}
+
+func TestDecodeShareErrors(t *testing.T) {
+	valid := loadShamirVectors(t)[0].Shares[0]
+
+	// 翻转值中的一个十六进制字符，校验和不再匹配
+	tampered := []byte(valid)
+	pos := len(shamirSharePrefix) + 2*shamirHeaderSize
+	if tampered[pos] == '0' {
+		tampered[pos] = '1'
+	} else {
+		tampered[pos] = '0'
+	}
+
+	tests := []struct {
+		name  string
+		share string
+	}{
+		{"missing prefix", strings.TrimPrefix(valid, shamirSharePrefix)},
+		{"not hex", shamirSharePrefix + "zz"},
+		{"too short", shamirSharePrefix + "0102011234"},
+		{"checksum mismatch", string(tampered)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeShare(tt.share); !errors.Is(err, ErrInvalidShare) {
+				t.Errorf("DecodeShare error = %v, want ErrInvalidShare", err)
+			}
+		})
+	}
+
+	if _, err := DecodeShare("  " + strings.ToUpper(valid) + "\n"); err != nil {
+		t.Errorf("DecodeShare should accept surrounding whitespace and upper case: %v", err)
+	}
+}
+
+func TestCombineSharesErrors(t *testing.T) {
+	vectors := loadShamirVectors(t)
+
+	decode := func(encoded string) ShamirShare {
+		share, err := DecodeShare(encoded)
+		if err != nil {
+			t.Fatalf("decode share: %v", err)
+		}
+		return share
+	}
+
+	// vectors[1] 与 vectors[2] 均为 3-of-5，但来自不同的分割
+	a := vectors[1].Shares
+	b := vectors[2].Shares
+
+	tests := []struct {
+		name   string
+		shares []ShamirShare
+		want   error
+	}{
+		{"empty", nil, ErrInsufficientShares},
+		{"below threshold", []ShamirShare{decode(a[0]), decode(a[1])}, ErrInsufficientShares},
+		{"different splits", []ShamirShare{decode(a[0]), decode(a[1]), decode(b[2])}, ErrShareMismatch},
+		{"duplicate index", []ShamirShare{decode(a[0]), decode(a[1]), decode(a[1])}, ErrInvalidShare},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CombineShares(tt.shares); !errors.Is(err, tt.want) {
+				t.Errorf("CombineShares error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSecretParams(t *testing.T) {
+	secret := []byte{1, 2, 3}
+
+	for _, p := range []struct{ shares, threshold int }{{3, 1}, {2, 3}, {ShamirMaxShares + 1, 2}} {
+		if _, err := SplitSecret(secret, p.shares, p.threshold); !errors.Is(err, ErrInvalidShamirParams) {
+			t.Errorf("SplitSecret(%d, %d) error = %v, want ErrInvalidShamirParams", p.shares, p.threshold, err)
+		}
+	}
+}
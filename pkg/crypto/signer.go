@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 钱包的签名方式
+const (
+	SignerTypeKeystore = "keystore" // 服务端使用钱包密码解密 Keystore 签名
+	SignerTypeRemote   = "remote"   // 私钥保存在独立的远程签名进程中
+)
+
+var (
+	// ErrSignerAccountMismatch 表示签名者不持有请求的 from 地址
+	ErrSignerAccountMismatch = errors.New("signer does not hold the requested account")
+
+	// ErrInvalidHashLength 表示待签名的哈希不是 32 字节
+	ErrInvalidHashLength = errors.New("hash to sign must be 32 bytes")
+)
+
+// Signer 定义了交易与消息哈希的签名接口，使业务层不再直接接触私钥
+type Signer interface {
+	// SignTx 使用 from 的私钥为交易签名（London signer，同时覆盖 EIP-155 legacy 与 EIP-1559 交易）
+	SignTx(ctx context.Context, chainID *big.Int, from common.Address, tx *types.Transaction) (*types.Transaction, error)
+
+	// SignHash 使用 from 的私钥对 32 字节哈希签名，返回 [R || S || V] 格式的 65 字节签名，V 为 0 或 1
+	SignHash(ctx context.Context, from common.Address, hash []byte) ([]byte, error)
+}
+
+// keystoreSigner 持有由 Keystore 解密得到的单个私钥，仅在一次请求内使用
+type keystoreSigner struct {
+	address    common.Address
+	privateKey *ecdsa.PrivateKey
+}
+
+var _ Signer = (*keystoreSigner)(nil)
+
+// UnlockKeystoreSigner 使用 KeyManager 解密 Keystore，返回只能为该地址签名的 Signer。
+// 密码错误时返回 ErrInvalidPassword。
+func UnlockKeystoreSigner(keyManager KeyManager, keystoreJSON string, password string) (Signer, error) {
+	privateKeyHex, err := keyManager.DecryptKeystore(keystoreJSON, password)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return &keystoreSigner{
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		privateKey: privateKey,
+	}, nil
+}
+
+// SignTx 为交易签名
+func (s *keystoreSigner) SignTx(
+	_ context.Context,
+	chainID *big.Int,
+	from common.Address,
+	tx *types.Transaction,
+) (*types.Transaction, error) {
+	if from != s.address {
+		return nil, fmt.Errorf("%w: %s", ErrSignerAccountMismatch, from.Hex())
+	}
+
+	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return signedTx, nil
+}
+
+// SignHash 对 32 字节哈希签名
+func (s *keystoreSigner) SignHash(_ context.Context, from common.Address, hash []byte) ([]byte, error) {
+	if from != s.address {
+		return nil, fmt.Errorf("%w: %s", ErrSignerAccountMismatch, from.Hex())
+	}
+	if len(hash) != common.HashLength {
+		return nil, ErrInvalidHashLength
+	}
+
+	return crypto.Sign(hash, s.privateKey)
+}
@@ -31,6 +31,8 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 
 	gormConfig := &gorm.Config{
 		SkipDefaultTransaction: true,
+		// 将驱动错误转换为 gorm.ErrDuplicatedKey 等通用错误，便于 store 层识别唯一索引冲突
+		TranslateError: true,
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: false,
 		},
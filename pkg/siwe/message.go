@@ -0,0 +1,275 @@
+// Package siwe 实现 Sign-In with Ethereum (EIP-4361) 消息的解析与签名校验。
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+)
+
+// headerSuffix 是 EIP-4361 消息首行 domain 之后的固定文本
+const headerSuffix = " wants you to sign in with your Ethereum account:"
+
+// minNonceLength 是 EIP-4361 规定的 nonce 最小长度
+const minNonceLength = 8
+
+// 消息字段标签，按 EIP-4361 规定的顺序排列
+const (
+	tagURI            = "URI: "
+	tagVersion        = "Version: "
+	tagChainID        = "Chain ID: "
+	tagNonce          = "Nonce: "
+	tagIssuedAt       = "Issued At: "
+	tagExpirationTime = "Expiration Time: "
+	tagNotBefore      = "Not Before: "
+	tagRequestID      = "Request ID: "
+	tagResources      = "Resources:"
+)
+
+var (
+	ErrInvalidMessage     = errors.New("malformed EIP-4361 message")
+	ErrInvalidSignature   = errors.New("invalid EIP-4361 signature")
+	ErrMessageExpired     = errors.New("EIP-4361 message has expired")
+	ErrMessageNotYetValid = errors.New("EIP-4361 message is not yet valid")
+)
+
+// Message 是解析后的 EIP-4361 消息
+type Message struct {
+	Scheme    string // 可选，如 https
+	Domain    string
+	Address   common.Address
+	Statement string // 可选
+
+	URI            string
+	Version        string
+	ChainID        uint
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time // 可选
+	NotBefore      *time.Time // 可选
+	RequestID      string     // 可选
+	Resources      []string   // 可选
+}
+
+// ParseMessage 按 EIP-4361 的 ABNF 解析消息文本。
+// 地址必须为 EIP-55 校验和格式，时间字段必须为 RFC 3339 格式。
+func ParseMessage(text string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	p := &lineParser{lines: lines}
+
+	msg := &Message{}
+
+	// 1. 首行：[scheme "://"] domain " wants you to sign in with your Ethereum account:"
+	header, ok := p.next()
+	if !ok || !strings.HasSuffix(header, headerSuffix) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidMessage)
+	}
+	authority := strings.TrimSuffix(header, headerSuffix)
+	if scheme, domain, found := strings.Cut(authority, "://"); found {
+		msg.Scheme, authority = scheme, domain
+	}
+	if authority == "" || strings.ContainsAny(authority, " /") {
+		return nil, fmt.Errorf("%w: invalid domain", ErrInvalidMessage)
+	}
+	msg.Domain = authority
+
+	// 2. 地址行，要求 EIP-55 校验和格式
+	addressLine, ok := p.next()
+	if !ok || !common.IsHexAddress(addressLine) || common.HexToAddress(addressLine).Hex() != addressLine {
+		return nil, fmt.Errorf("%w: address must be an EIP-55 checksummed address", ErrInvalidMessage)
+	}
+	msg.Address = common.HexToAddress(addressLine)
+
+	// 3. 空行 + 可选 statement + 空行
+	if line, ok := p.next(); !ok || line != "" {
+		return nil, fmt.Errorf("%w: expected empty line after address", ErrInvalidMessage)
+	}
+	if line, ok := p.peek(); ok && line != "" && !strings.HasPrefix(line, tagURI) {
+		msg.Statement = line
+		p.next()
+	}
+	if line, ok := p.peek(); ok && line == "" {
+		p.next()
+	}
+
+	// 4. 必填字段
+	var err error
+	if msg.URI, err = p.field(tagURI, true); err != nil {
+		return nil, err
+	}
+	if msg.Version, err = p.field(tagVersion, true); err != nil {
+		return nil, err
+	}
+	if msg.Version != "1" {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidMessage, msg.Version)
+	}
+
+	chainID, err := p.field(tagChainID, true)
+	if err != nil {
+		return nil, err
+	}
+	parsedChainID, err := strconv.ParseUint(chainID, 10, 64)
+	if err != nil || parsedChainID == 0 {
+		return nil, fmt.Errorf("%w: invalid chain id", ErrInvalidMessage)
+	}
+	msg.ChainID = uint(parsedChainID)
+
+	if msg.Nonce, err = p.field(tagNonce, true); err != nil {
+		return nil, err
+	}
+	if !isValidNonce(msg.Nonce) {
+		return nil, fmt.Errorf("%w: nonce must be at least %d alphanumeric characters", ErrInvalidMessage, minNonceLength)
+	}
+
+	issuedAt, err := p.field(tagIssuedAt, true)
+	if err != nil {
+		return nil, err
+	}
+	if msg.IssuedAt, err = parseTimestamp(issuedAt); err != nil {
+		return nil, err
+	}
+
+	// 5. 可选字段
+	if value, err := p.field(tagExpirationTime, false); err != nil {
+		return nil, err
+	} else if value != "" {
+		expiration, err := parseTimestamp(value)
+		if err != nil {
+			return nil, err
+		}
+		msg.ExpirationTime = &expiration
+	}
+
+	if value, err := p.field(tagNotBefore, false); err != nil {
+		return nil, err
+	} else if value != "" {
+		notBefore, err := parseTimestamp(value)
+		if err != nil {
+			return nil, err
+		}
+		msg.NotBefore = &notBefore
+	}
+
+	if msg.RequestID, err = p.field(tagRequestID, false); err != nil {
+		return nil, err
+	}
+
+	if line, ok := p.peek(); ok && line == tagResources {
+		p.next()
+		for {
+			line, ok := p.peek()
+			if !ok || !strings.HasPrefix(line, "- ") {
+				break
+			}
+			msg.Resources = append(msg.Resources, strings.TrimPrefix(line, "- "))
+			p.next()
+		}
+	}
+
+	// 6. 允许末尾换行，不允许其他多余内容
+	for {
+		line, ok := p.next()
+		if !ok {
+			break
+		}
+		if line != "" {
+			return nil, fmt.Errorf("%w: unexpected line %q", ErrInvalidMessage, line)
+		}
+	}
+
+	return msg, nil
+}
+
+// CheckTime 校验消息在 now 时刻是否处于 Not Before 与 Expiration Time 之间
+func (m *Message) CheckTime(now time.Time) error {
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return ErrMessageExpired
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return ErrMessageNotYetValid
+	}
+	return nil
+}
+
+// RecoverAddress 按 EIP-191 (personal_sign) 恢复消息签名者地址，signature 为 0x 前缀的 65 字节十六进制
+func RecoverAddress(text string, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	address, err := crypto.RecoverAddress(crypto.PersonalMessageHash([]byte(text)), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	return address, nil
+}
+
+// lineParser 按行顺序读取消息
+type lineParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *lineParser) peek() (string, bool) {
+	if p.pos >= len(p.lines) {
+		return "", false
+	}
+	return p.lines[p.pos], true
+}
+
+func (p *lineParser) next() (string, bool) {
+	line, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return line, ok
+}
+
+// field 读取以 tag 开头的字段值，可选字段不存在时返回空字符串
+func (p *lineParser) field(tag string, required bool) (string, error) {
+	line, ok := p.peek()
+	if !ok || !strings.HasPrefix(line, tag) {
+		if required {
+			return "", fmt.Errorf("%w: missing %q", ErrInvalidMessage, strings.TrimSpace(tag))
+		}
+		return "", nil
+	}
+	p.next()
+
+	value := strings.TrimPrefix(line, tag)
+	if value == "" {
+		return "", fmt.Errorf("%w: empty %q", ErrInvalidMessage, strings.TrimSpace(tag))
+	}
+	return value, nil
+}
+
+// parseTimestamp 解析 RFC 3339 时间
+func parseTimestamp(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid timestamp %q", ErrInvalidMessage, value)
+	}
+	return t, nil
+}
+
+// isValidNonce 校验 nonce 为至少 8 位的字母数字
+func isValidNonce(nonce string) bool {
+	if len(nonce) < minNonceLength {
+		return false
+	}
+	for _, r := range nonce {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package siwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/crypto"
+)
+
+const testAddress = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+
+// fullMessage 含全部可选字段
+const fullMessage = `https://example.com wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+Sign in to the wallet backend.
+
+URI: https://example.com/login
+Version: 1
+Chain ID: 11155111
+Nonce: abcDEF123456
+Issued At: 2026-10-17T08:00:00Z
+Expiration Time: 2026-10-17T08:10:00.5Z
+Not Before: 2026-10-17T07:59:00+08:00
+Request ID: req-42
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq
+- https://example.com/terms`
+
+// minimalMessage 不含 statement 及任何可选字段
+const minimalMessage = `example.com wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+URI: https://example.com
+Version: 1
+Chain ID: 1
+Nonce: 12345678
+Issued At: 2026-10-17T08:00:00Z`
+
+func TestParseMessageFull(t *testing.T) {
+	msg, err := ParseMessage(fullMessage)
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+
+	if msg.Scheme != "https" || msg.Domain != "example.com" {
+		t.Errorf("scheme/domain = %q/%q", msg.Scheme, msg.Domain)
+	}
+	if msg.Address != common.HexToAddress(testAddress) {
+		t.Errorf("address = %s", msg.Address.Hex())
+	}
+	if msg.Statement != "Sign in to the wallet backend." {
+		t.Errorf("statement = %q", msg.Statement)
+	}
+	if msg.URI != "https://example.com/login" || msg.Version != "1" || msg.ChainID != 11155111 || msg.Nonce != "abcDEF123456" {
+		t.Errorf("uri/version/chain/nonce = %q/%q/%d/%q", msg.URI, msg.Version, msg.ChainID, msg.Nonce)
+	}
+	if !msg.IssuedAt.Equal(time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("issued at = %s", msg.IssuedAt)
+	}
+	if msg.ExpirationTime == nil || !msg.ExpirationTime.Equal(time.Date(2026, 10, 17, 8, 10, 0, 5e8, time.UTC)) {
+		t.Errorf("expiration time = %v", msg.ExpirationTime)
+	}
+	if msg.NotBefore == nil || !msg.NotBefore.Equal(time.Date(2026, 10, 16, 23, 59, 0, 0, time.UTC)) {
+		t.Errorf("not before = %v", msg.NotBefore)
+	}
+	if msg.RequestID != "req-42" {
+		t.Errorf("request id = %q", msg.RequestID)
+	}
+	if len(msg.Resources) != 2 || msg.Resources[1] != "https://example.com/terms" {
+		t.Errorf("resources = %v", msg.Resources)
+	}
+}
+
+func TestParseMessageMinimal(t *testing.T) {
+	for name, text := range map[string]string{
+		"lf":               minimalMessage,
+		"crlf":             strings.ReplaceAll(minimalMessage, "\n", "\r\n"),
+		"trailing newline": minimalMessage + "\n\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			msg, err := ParseMessage(text)
+			if err != nil {
+				t.Fatalf("ParseMessage: %v", err)
+			}
+			if msg.Scheme != "" || msg.Domain != "example.com" || msg.Statement != "" {
+				t.Errorf("scheme/domain/statement = %q/%q/%q", msg.Scheme, msg.Domain, msg.Statement)
+			}
+			if msg.ExpirationTime != nil || msg.NotBefore != nil || msg.RequestID != "" || msg.Resources != nil {
+				t.Errorf("unexpected optional fields: %+v", msg)
+			}
+		})
+	}
+}
+
+func TestParseMessageStatementWithoutResources(t *testing.T) {
+	text := strings.Replace(minimalMessage, "\n\nURI:", "\n\nHello.\n\nURI:", 1)
+
+	msg, err := ParseMessage(text)
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	if msg.Statement != "Hello." || msg.Resources != nil {
+		t.Errorf("statement/resources = %q/%v", msg.Statement, msg.Resources)
+	}
+}
+
+func TestParseMessageErrors(t *testing.T) {
+	lower := strings.ToLower(testAddress)
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"missing header", strings.Replace(minimalMessage, headerSuffix, " wants you to sign in:", 1)},
+		{"domain with path", strings.Replace(minimalMessage, "example.com wants", "example.com/x wants", 1)},
+		{"lower case address", strings.Replace(minimalMessage, testAddress, lower, 1)},
+		{"upper case address", strings.Replace(minimalMessage, testAddress, "0x"+strings.ToUpper(lower[2:]), 1)},
+		{"invalid address", strings.Replace(minimalMessage, testAddress, "0x1234", 1)},
+		{"missing empty line", strings.Replace(minimalMessage, testAddress+"\n\n", testAddress+"\n", 1)},
+		{"missing uri", strings.Replace(minimalMessage, "URI: https://example.com\n", "", 1)},
+		{"unsupported version", strings.Replace(minimalMessage, "Version: 1", "Version: 2", 1)},
+		{"zero chain id", strings.Replace(minimalMessage, "Chain ID: 1", "Chain ID: 0", 1)},
+		{"non-numeric chain id", strings.Replace(minimalMessage, "Chain ID: 1", "Chain ID: one", 1)},
+		{"short nonce", strings.Replace(minimalMessage, "Nonce: 12345678", "Nonce: 1234567", 1)},
+		{"non-alphanumeric nonce", strings.Replace(minimalMessage, "Nonce: 12345678", "Nonce: 1234-5678", 1)},
+		{"bad issued at", strings.Replace(minimalMessage, "2026-10-17T08:00:00Z", "2026-10-17 08:00:00", 1)},
+		{"issued at without zone", strings.Replace(minimalMessage, "2026-10-17T08:00:00Z", "2026-10-17T08:00:00", 1)},
+		{"bad expiration time", minimalMessage + "\nExpiration Time: tomorrow"},
+		{"empty request id", minimalMessage + "\nRequest ID: "},
+		{"fields out of order", strings.Replace(minimalMessage, "Version: 1\nChain ID: 1", "Chain ID: 1\nVersion: 1", 1)},
+		{"trailing junk", minimalMessage + "\nextra"},
+		{"junk after resources", minimalMessage + "\nResources:\n- https://example.com\nextra"},
+		{"junk after blank line", minimalMessage + "\n\nextra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMessage(tt.text); !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("ParseMessage error = %v, want ErrInvalidMessage", err)
+			}
+		})
+	}
+}
+
+func TestCheckTime(t *testing.T) {
+	msg, err := ParseMessage(fullMessage)
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	notBefore, expiration := *msg.NotBefore, *msg.ExpirationTime
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		{"before not before", notBefore.Add(-time.Nanosecond), ErrMessageNotYetValid},
+		{"at not before", notBefore, nil},
+		{"within window", notBefore.Add(time.Minute), nil},
+		{"just before expiration", expiration.Add(-time.Nanosecond), nil},
+		{"at expiration", expiration, ErrMessageExpired},
+		{"after expiration", expiration.Add(time.Hour), ErrMessageExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := msg.CheckTime(tt.now); !errors.Is(err, tt.want) {
+				t.Errorf("CheckTime error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	minimal, err := ParseMessage(minimalMessage)
+	if err != nil {
+		t.Fatalf("ParseMessage: %v", err)
+	}
+	if err := minimal.CheckTime(time.Unix(0, 0)); err != nil {
+		t.Errorf("CheckTime without time bounds = %v, want nil", err)
+	}
+}
+
+func TestRecoverAddress(t *testing.T) {
+	key, err := gethcrypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatalf("HexToECDSA: %v", err)
+	}
+	signer := gethcrypto.PubkeyToAddress(key.PublicKey)
+
+	sig, err := gethcrypto.Sign(crypto.PersonalMessageHash([]byte(minimalMessage)), key)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	sig[64] += 27 // 钱包返回的 V 为 27 / 28
+
+	recovered, err := RecoverAddress(minimalMessage, hexutil.Encode(sig))
+	if err != nil {
+		t.Fatalf("RecoverAddress: %v", err)
+	}
+	if recovered != signer {
+		t.Errorf("RecoverAddress = %s, want %s", recovered.Hex(), signer.Hex())
+	}
+
+	if recovered, err := RecoverAddress(minimalMessage+" ", hexutil.Encode(sig)); err == nil && recovered == signer {
+		t.Error("RecoverAddress matched the signer for a different message")
+	}
+	for _, bad := range []string{"", "0x1234", "not-hex"} {
+		if _, err := RecoverAddress(minimalMessage, bad); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("RecoverAddress(%q) error = %v, want ErrInvalidSignature", bad, err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package totp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret 是 RFC 6238 附录 B SHA-1 测试使用的 ASCII 密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// RFC 6238 附录 B 的 8 位验证码取后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(T=%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(T=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeSecretNormalization(t *testing.T) {
+	spaced := strings.ToLower(rfcSecret[:8]) + " " + rfcSecret[8:] + "===="
+
+	got, err := Code(spaced, 1)
+	if err != nil {
+		t.Fatalf("Code with lower case, spaces and padding: %v", err)
+	}
+	want, _ := Code(rfcSecret, 1)
+	if got != want {
+		t.Errorf("Code = %s, want %s", got, want)
+	}
+
+	for _, secret := range []string{"", "!!!!", "1"} {
+		if _, err := Code(secret, 1); !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("Code(%q) error = %v, want ErrInvalidSecret", secret, err)
+		}
+	}
+}
+
+func TestValidateSkewWindow(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		skew   int
+		ok     bool
+	}{
+		{"current step", 0, 0, true},
+		{"previous step without skew", -1, 0, false},
+		{"previous step within skew", -1, 1, true},
+		{"next step within skew", 1, 1, true},
+		{"two steps behind with skew 1", -2, 1, false},
+		{"two steps ahead with skew 1", 2, 1, false},
+		{"two steps behind with skew 2", -2, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passcode, err := Code(rfcSecret, current+tt.offset)
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+
+			step, ok, err := Validate(rfcSecret, passcode, now, tt.skew)
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if ok != tt.ok {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("Validate step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateReturnsMatchedStepForReplay(t *testing.T) {
+	// Validate 本身无状态，重放由调用方比较返回的时间步与上次使用的时间步来拒绝：
+	// 同一验证码在窗口内再次提交必须返回相同的时间步
+	issued := time.Unix(1111111109, 0)
+	passcode, err := Code(rfcSecret, Step(issued))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	first, ok, err := Validate(rfcSecret, passcode, issued, 1)
+	if err != nil || !ok {
+		t.Fatalf("first Validate = %v, %v", ok, err)
+	}
+
+	replayed, ok, err := Validate(rfcSecret, passcode, issued.Add(Period*time.Second), 1)
+	if err != nil || !ok {
+		t.Fatalf("replayed Validate = %v, %v", ok, err)
+	}
+	if replayed != first {
+		t.Errorf("replayed step = %d, want %d", replayed, first)
+	}
+}
+
+func TestValidateRejectsMalformedPasscode(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	for _, passcode := range []string{"", "28708", "2870820", "abcdef"} {
+		_, ok, err := Validate(rfcSecret, passcode, now, 1)
+		if err != nil {
+			t.Fatalf("Validate(%q): %v", passcode, err)
+		}
+		if ok {
+			t.Errorf("Validate(%q) accepted a malformed passcode", passcode)
+		}
+	}
+
+	if _, _, err := Validate("!!!!", "287082", now, 1); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("Validate with invalid secret error = %v, want ErrInvalidSecret", err)
+	}
+}
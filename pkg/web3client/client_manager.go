@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 
@@ -16,10 +19,43 @@ import (
 	"github.com/bwmspring/go-web3-wallet-backend/pkg/logger"
 )
 
+// ErrChainNotConfigured 表示请求的链 ID 未配置或连接失败
+var ErrChainNotConfigured = errors.New("chain ID not found in configuration or failed to connect")
+
+// rpcTimeout 是单次 RPC 调用的默认超时时间
+const rpcTimeout = 10 * time.Second
+
 // ClientManager 定义了区块链客户端的接口，负责管理与不同链的连接。
 type ClientManager interface {
 	GetClient(chainID uint) (*ethclient.Client, error)
+	// ChainIDs 返回所有已成功连接的链 ID（升序）
+	ChainIDs() []uint
 	GetBalanceByAddress(ctx context.Context, chainID uint, address string) (*big.Int, error)
+
+	// PendingNonceAt 返回地址在 pending 状态下的下一个 nonce
+	PendingNonceAt(ctx context.Context, chainID uint, address common.Address) (uint64, error)
+	// SuggestGasPrice 返回节点建议的 legacy gasPrice
+	SuggestGasPrice(ctx context.Context, chainID uint) (*big.Int, error)
+	// EstimateGas 估算交易所需的 gas limit
+	EstimateGas(ctx context.Context, chainID uint, msg ethereum.CallMsg) (uint64, error)
+	// SendTransaction 广播已签名的交易
+	SendTransaction(ctx context.Context, chainID uint, tx *types.Transaction) error
+
+	// BlockNumber 返回链上最新区块高度
+	BlockNumber(ctx context.Context, chainID uint) (uint64, error)
+	// HeaderByNumber 返回指定高度的区块头，number 为 nil 时返回最新区块头
+	HeaderByNumber(ctx context.Context, chainID uint, number *big.Int) (*types.Header, error)
+	// NonceAt 返回地址在最新区块中已确认的 nonce
+	NonceAt(ctx context.Context, chainID uint, address common.Address) (uint64, error)
+	// TransactionReceipt 返回交易回执，未上链时返回 ethereum.NotFound
+	TransactionReceipt(ctx context.Context, chainID uint, txHash common.Hash) (*types.Receipt, error)
+	// TransactionByHash 查询交易，节点未知时返回 ethereum.NotFound
+	TransactionByHash(ctx context.Context, chainID uint, txHash common.Hash) (*types.Transaction, bool, error)
+
+	// BalanceOf 查询 ERC-20 代币余额（最小单位）
+	BalanceOf(ctx context.Context, chainID uint, token common.Address, owner common.Address) (*big.Int, error)
+	// Decimals 查询 ERC-20 代币的小数位数
+	Decimals(ctx context.Context, chainID uint, token common.Address) (uint8, error)
 }
 
 // clientManager 实现了 ClientManager 接口
@@ -83,11 +119,25 @@ func (m *clientManager) GetClient(chainID uint) (*ethclient.Client, error) {
 
 	client, ok := m.connections[chainID]
 	if !ok {
-		return nil, errors.New("chain ID not found in configuration or failed to connect")
+		return nil, ErrChainNotConfigured
 	}
 	return client, nil
 }
 
+// ChainIDs 返回连接池中所有链 ID（升序）
+func (m *clientManager) ChainIDs() []uint {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	chainIDs := make([]uint, 0, len(m.connections))
+	for chainID := range m.connections {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+
+	return chainIDs
+}
+
 // GetBalanceByAddress 查询指定地址在指定链上的余额
 func (m *clientManager) GetBalanceByAddress(ctx context.Context, chainID uint, address string) (*big.Int, error) {
 	client, err := m.GetClient(chainID)
@@ -96,7 +146,7 @@ func (m *clientManager) GetBalanceByAddress(ctx context.Context, chainID uint, a
 	}
 
 	// 设置查询超时 (保护系统资源)
-	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
 	defer cancel()
 
 	addr := common.HexToAddress(address)
@@ -109,3 +159,229 @@ func (m *clientManager) GetBalanceByAddress(ctx context.Context, chainID uint, a
 
 	return balance, nil
 }
+
+// PendingNonceAt 查询地址在 pending 状态下的下一个可用 nonce
+func (m *clientManager) PendingNonceAt(ctx context.Context, chainID uint, address common.Address) (uint64, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	nonce, err := client.PendingNonceAt(timeoutCtx, address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch pending nonce for address %s: %w", address.Hex(), err)
+	}
+
+	return nonce, nil
+}
+
+// SuggestGasPrice 查询节点建议的 gasPrice
+func (m *clientManager) SuggestGasPrice(ctx context.Context, chainID uint) (*big.Int, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	gasPrice, err := client.SuggestGasPrice(timeoutCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+
+	return gasPrice, nil
+}
+
+// EstimateGas 估算交易的 gas limit
+func (m *clientManager) EstimateGas(ctx context.Context, chainID uint, msg ethereum.CallMsg) (uint64, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	gas, err := client.EstimateGas(timeoutCtx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	return gas, nil
+}
+
+// SendTransaction 将已签名的交易广播到指定链
+func (m *clientManager) SendTransaction(ctx context.Context, chainID uint, tx *types.Transaction) error {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	if err := client.SendTransaction(timeoutCtx, tx); err != nil {
+		return fmt.Errorf("failed to broadcast transaction %s: %w", tx.Hash().Hex(), err)
+	}
+
+	return nil
+}
+
+// BlockNumber 查询链上最新区块高度
+func (m *clientManager) BlockNumber(ctx context.Context, chainID uint) (uint64, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	number, err := client.BlockNumber(timeoutCtx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch block number: %w", err)
+	}
+
+	return number, nil
+}
+
+// HeaderByNumber 查询指定高度的区块头
+func (m *clientManager) HeaderByNumber(ctx context.Context, chainID uint, number *big.Int) (*types.Header, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	header, err := client.HeaderByNumber(timeoutCtx, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch header: %w", err)
+	}
+
+	return header, nil
+}
+
+// NonceAt 查询地址在最新区块中已确认的 nonce
+func (m *clientManager) NonceAt(ctx context.Context, chainID uint, address common.Address) (uint64, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	nonce, err := client.NonceAt(timeoutCtx, address, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch nonce for address %s: %w", address.Hex(), err)
+	}
+
+	return nonce, nil
+}
+
+// TransactionReceipt 查询交易回执，保留 ethereum.NotFound 以便调用方区分"未上链"
+func (m *clientManager) TransactionReceipt(
+	ctx context.Context,
+	chainID uint,
+	txHash common.Hash,
+) (*types.Receipt, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	receipt, err := client.TransactionReceipt(timeoutCtx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receipt for %s: %w", txHash.Hex(), err)
+	}
+
+	return receipt, nil
+}
+
+// TransactionByHash 查询交易及其是否仍处于 pending 状态
+func (m *clientManager) TransactionByHash(
+	ctx context.Context,
+	chainID uint,
+	txHash common.Hash,
+) (*types.Transaction, bool, error) {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	tx, isPending, err := client.TransactionByHash(timeoutCtx, txHash)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch transaction %s: %w", txHash.Hex(), err)
+	}
+
+	return tx, isPending, nil
+}
+
+// BalanceOf 调用 ERC-20 合约的 balanceOf(owner)
+func (m *clientManager) BalanceOf(
+	ctx context.Context,
+	chainID uint,
+	token common.Address,
+	owner common.Address,
+) (*big.Int, error) {
+	var balance *big.Int
+	if err := m.callERC20(ctx, chainID, token, &balance, "balanceOf", owner); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// Decimals 调用 ERC-20 合约的 decimals()
+func (m *clientManager) Decimals(ctx context.Context, chainID uint, token common.Address) (uint8, error) {
+	var decimals uint8
+	if err := m.callERC20(ctx, chainID, token, &decimals, "decimals"); err != nil {
+		return 0, err
+	}
+	return decimals, nil
+}
+
+// callERC20 对 ERC-20 合约发起只读调用，并将单个返回值解码到 out
+func (m *clientManager) callERC20(
+	ctx context.Context,
+	chainID uint,
+	token common.Address,
+	out any,
+	method string,
+	args ...any,
+) error {
+	client, err := m.GetClient(chainID)
+	if err != nil {
+		return fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	data, err := erc20ABI.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	result, err := client.CallContract(timeoutCtx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s on token %s: %w", method, token.Hex(), err)
+	}
+
+	if err := erc20ABI.UnpackIntoInterface(out, method, result); err != nil {
+		return fmt.Errorf("failed to decode %s result from token %s: %w", method, token.Hex(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package web3client
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// erc20ABIJSON 仅包含本系统使用到的 ERC-20 方法
+const erc20ABIJSON = `[
+	{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+	{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}
+]`
+
+// erc20ABI 是解析后的 ERC-20 ABI，包初始化时解析，格式错误属于编程错误
+var erc20ABI = mustParseABI(erc20ABIJSON)
+
+// mustParseABI 解析内置的 ABI 定义
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in ABI: %v", err))
+	}
+	return parsed
+}
+
+// PackERC20Transfer 构建 ERC-20 transfer(to, amount) 调用的 calldata
+func PackERC20Transfer(to common.Address, amount *big.Int) ([]byte, error) {
+	data, err := erc20ABI.Pack("transfer", to, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack ERC-20 transfer: %w", err)
+	}
+	return data, nil
+}
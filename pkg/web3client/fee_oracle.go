@@ -0,0 +1,283 @@
+package web3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+	"github.com/bwmspring/go-web3-wallet-backend/pkg/conversion"
+)
+
+// FeeTier 表示手续费档位
+type FeeTier string
+
+const (
+	FeeTierSlow     FeeTier = "slow"
+	FeeTierStandard FeeTier = "standard"
+	FeeTierFast     FeeTier = "fast"
+)
+
+// ErrInvalidFeeTier 表示无法识别的手续费档位
+var ErrInvalidFeeTier = errors.New("invalid fee tier, expected slow, standard or fast")
+
+// 默认的手续费估算参数，对应 config.GasConfig 中未配置的字段
+const (
+	defaultHistoryBlocks      = 20
+	defaultSlowPercentile     = 10
+	defaultStandardPercentile = 50
+	defaultFastPercentile     = 90
+	defaultBaseFeeMultiplier  = 2
+)
+
+// legacy 链上各档位相对于节点建议 gasPrice 的百分比
+var legacyTierPercent = map[FeeTier]int64{
+	FeeTierSlow:     90,
+	FeeTierStandard: 100,
+	FeeTierFast:     125,
+}
+
+// ParseFeeTier 解析手续费档位，空字符串返回 standard
+func ParseFeeTier(tier string) (FeeTier, error) {
+	switch FeeTier(tier) {
+	case "":
+		return FeeTierStandard, nil
+	case FeeTierSlow, FeeTierStandard, FeeTierFast:
+		return FeeTier(tier), nil
+	default:
+		return "", ErrInvalidFeeTier
+	}
+}
+
+// FeeSuggestion 单个档位的手续费建议（单位均为 Wei）
+type FeeSuggestion struct {
+	// EIP-1559 链使用 MaxFeePerGas 与 MaxPriorityFeePerGas
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+
+	// GasPrice 仅在 legacy 链上有值
+	GasPrice *big.Int
+}
+
+// FeeEstimate 一条链的手续费估算结果
+type FeeEstimate struct {
+	ChainID uint
+	Legacy  bool     // true 表示链不支持 EIP-1559，只能使用 gasPrice
+	BaseFee *big.Int // 下一个区块的预估 baseFee，legacy 链为 nil
+	Tiers   map[FeeTier]FeeSuggestion
+}
+
+// Tier 返回指定档位的建议值，未知档位回退到 standard
+func (e *FeeEstimate) Tier(tier FeeTier) FeeSuggestion {
+	if s, ok := e.Tiers[tier]; ok {
+		return s
+	}
+	return e.Tiers[FeeTierStandard]
+}
+
+// FeeOracle 定义了链上手续费估算的接口
+type FeeOracle interface {
+	SuggestFees(ctx context.Context, chainID uint) (*FeeEstimate, error)
+}
+
+// feeOracle 基于 eth_feeHistory 实现 FeeOracle，不支持 London 的链回退到 eth_gasPrice
+type feeOracle struct {
+	clientManager ClientManager
+	gasConfigs    map[uint]config.GasConfig
+}
+
+// NewFeeOracle 创建 FeeOracle，按链读取 config.GasConfig 中的估算参数
+func NewFeeOracle(clientManager ClientManager, chainConfigs []config.BlockchainConfig) FeeOracle {
+	gasConfigs := make(map[uint]config.GasConfig, len(chainConfigs))
+	for _, chainCfg := range chainConfigs {
+		gasConfigs[chainCfg.ChainID] = withGasDefaults(chainCfg.Gas)
+	}
+
+	return &feeOracle{
+		clientManager: clientManager,
+		gasConfigs:    gasConfigs,
+	}
+}
+
+// withGasDefaults 为未配置的估算参数填充默认值
+func withGasDefaults(cfg config.GasConfig) config.GasConfig {
+	if cfg.HistoryBlocks == 0 {
+		cfg.HistoryBlocks = defaultHistoryBlocks
+	}
+	if cfg.SlowPercentile == 0 {
+		cfg.SlowPercentile = defaultSlowPercentile
+	}
+	if cfg.StandardPercentile == 0 {
+		cfg.StandardPercentile = defaultStandardPercentile
+	}
+	if cfg.FastPercentile == 0 {
+		cfg.FastPercentile = defaultFastPercentile
+	}
+	if cfg.BaseFeeMultiplier <= 0 {
+		cfg.BaseFeeMultiplier = defaultBaseFeeMultiplier
+	}
+	return cfg
+}
+
+// SuggestFees 返回指定链的 slow / standard / fast 三档手续费建议
+func (o *feeOracle) SuggestFees(ctx context.Context, chainID uint) (*FeeEstimate, error) {
+	client, err := o.clientManager.GetClient(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	gasCfg, ok := o.gasConfigs[chainID]
+	if !ok {
+		gasCfg = withGasDefaults(config.GasConfig{})
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	if !gasCfg.Legacy {
+		header, err := client.HeaderByNumber(timeoutCtx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest header: %w", err)
+		}
+
+		// baseFee 为空说明链尚未启用 London，回退到 legacy gasPrice
+		if header.BaseFee != nil {
+			return o.suggestDynamicFees(timeoutCtx, chainID, gasCfg)
+		}
+	}
+
+	return o.suggestLegacyFees(timeoutCtx, chainID, gasCfg)
+}
+
+// suggestDynamicFees 根据 eth_feeHistory 的小费百分位估算 EIP-1559 手续费
+func (o *feeOracle) suggestDynamicFees(
+	ctx context.Context,
+	chainID uint,
+	gasCfg config.GasConfig,
+) (*FeeEstimate, error) {
+	client, err := o.clientManager.GetClient(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	tiers := []FeeTier{FeeTierSlow, FeeTierStandard, FeeTierFast}
+	percentiles := []float64{gasCfg.SlowPercentile, gasCfg.StandardPercentile, gasCfg.FastPercentile}
+
+	history, err := client.FeeHistory(ctx, gasCfg.HistoryBlocks, nil, percentiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("fee history returned no base fee data")
+	}
+
+	// feeHistory 返回的 BaseFee 比请求区块数多一个，最后一个即下一个区块的 baseFee
+	nextBaseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	minTip := gweiToWei(gasCfg.MinPriorityFeeGwei)
+	maxFeeCap := gweiToWei(gasCfg.MaxFeeGwei)
+	multiplierPercent := big.NewInt(int64(gasCfg.BaseFeeMultiplier * 100))
+
+	estimate := &FeeEstimate{
+		ChainID: chainID,
+		BaseFee: nextBaseFee,
+		Tiers:   make(map[FeeTier]FeeSuggestion, len(tiers)),
+	}
+
+	for i, tier := range tiers {
+		tip := medianReward(history.Reward, i)
+		if tip.Cmp(minTip) < 0 {
+			tip = new(big.Int).Set(minTip)
+		}
+
+		maxFee := new(big.Int).Mul(nextBaseFee, multiplierPercent)
+		maxFee.Div(maxFee, big.NewInt(100))
+		maxFee.Add(maxFee, tip)
+
+		if maxFeeCap.Sign() > 0 && maxFee.Cmp(maxFeeCap) > 0 {
+			maxFee = new(big.Int).Set(maxFeeCap)
+		}
+		// 小费不能超过 maxFee，否则交易会被节点拒绝
+		if tip.Cmp(maxFee) > 0 {
+			tip = new(big.Int).Set(maxFee)
+		}
+
+		estimate.Tiers[tier] = FeeSuggestion{
+			MaxFeePerGas:         maxFee,
+			MaxPriorityFeePerGas: tip,
+		}
+	}
+
+	return estimate, nil
+}
+
+// suggestLegacyFees 基于 eth_gasPrice 按固定百分比给出三档 gasPrice
+func (o *feeOracle) suggestLegacyFees(
+	ctx context.Context,
+	chainID uint,
+	gasCfg config.GasConfig,
+) (*FeeEstimate, error) {
+	gasPrice, err := o.clientManager.SuggestGasPrice(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFeeCap := gweiToWei(gasCfg.MaxFeeGwei)
+
+	estimate := &FeeEstimate{
+		ChainID: chainID,
+		Legacy:  true,
+		Tiers:   make(map[FeeTier]FeeSuggestion, len(legacyTierPercent)),
+	}
+
+	for tier, percent := range legacyTierPercent {
+		price := new(big.Int).Mul(gasPrice, big.NewInt(percent))
+		price.Div(price, big.NewInt(100))
+
+		if maxFeeCap.Sign() > 0 && price.Cmp(maxFeeCap) > 0 {
+			price = new(big.Int).Set(maxFeeCap)
+		}
+
+		estimate.Tiers[tier] = FeeSuggestion{GasPrice: price}
+	}
+
+	return estimate, nil
+}
+
+// medianReward 取所有采样区块在第 index 个百分位上的小费中位数，
+// 相比平均值，中位数不易受个别异常区块（如空块或 MEV 块）影响。
+func medianReward(rewards [][]*big.Int, index int) *big.Int {
+	values := make([]*big.Int, 0, len(rewards))
+	for _, blockRewards := range rewards {
+		if index < len(blockRewards) && blockRewards[index] != nil {
+			values = append(values, blockRewards[index])
+		}
+	}
+
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+
+	return new(big.Int).Set(values[len(values)/2])
+}
+
+// gweiToWei 将配置中的 Gwei 浮点数转换为 Wei，非正数返回 0
+func gweiToWei(gwei float64) *big.Int {
+	if gwei <= 0 {
+		return big.NewInt(0)
+	}
+
+	wei, err := conversion.GweiToWei(decimal.NewFromFloat(gwei).Round(9))
+	if err != nil {
+		return big.NewInt(0)
+	}
+	return wei
+}
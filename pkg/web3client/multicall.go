@@ -0,0 +1,209 @@
+package web3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+)
+
+// multicall3ContractKey 是 contract_addresses 中 Multicall3 合约地址的键名
+const multicall3ContractKey = "multicall3"
+
+// multicall3ABIJSON 仅包含 aggregate3 与 getEthBalance
+const multicall3ABIJSON = `[
+	{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"},
+	{"inputs":[{"name":"addr","type":"address"}],"name":"getEthBalance","outputs":[{"name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"}
+]`
+
+var multicall3ABI = mustParseABI(multicall3ABIJSON)
+
+// errMulticallCallFailed 表示 aggregate3 中的单个调用执行失败（如代币合约 revert）
+var errMulticallCallFailed = errors.New("multicall sub-call reverted")
+
+// multicall3Call 对应 aggregate3 的 Call3 结构，字段名需与 ABI 组件名一致
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result 对应 aggregate3 的 Result 结构
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// TokenBalanceResult 单个代币余额的读取结果，Err 非空表示该代币查询失败
+type TokenBalanceResult struct {
+	Token   common.Address
+	Balance *big.Int
+	Err     error
+}
+
+// BalanceSnapshot 一个地址在一条链上的原生币与代币余额
+type BalanceSnapshot struct {
+	Native *big.Int
+	Tokens []TokenBalanceResult // 顺序与请求的 tokens 一致
+}
+
+// BalanceReader 定义了批量读取余额的接口
+type BalanceReader interface {
+	// ReadBalances 读取 owner 在指定链上的原生币余额及 tokens 的代币余额。
+	// 单个代币失败记录在对应的 TokenBalanceResult.Err 中；原生币查询失败时返回 error。
+	ReadBalances(ctx context.Context, chainID uint, owner common.Address, tokens []common.Address) (*BalanceSnapshot, error)
+}
+
+// balanceReader 在配置了 Multicall3 的链上通过一次 aggregate3 读取全部余额，否则逐个调用
+type balanceReader struct {
+	clientManager ClientManager
+	multicalls    map[uint]common.Address
+}
+
+var _ BalanceReader = (*balanceReader)(nil)
+
+// NewBalanceReader 创建 BalanceReader，按链读取 contract_addresses.multicall3
+func NewBalanceReader(clientManager ClientManager, chainConfigs []config.BlockchainConfig) (BalanceReader, error) {
+	multicalls := make(map[uint]common.Address)
+
+	for _, chainCfg := range chainConfigs {
+		address, ok := chainCfg.ContractAddresses[multicall3ContractKey]
+		if !ok || address == "" {
+			continue
+		}
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid multicall3 address %q on chain %d", address, chainCfg.ChainID)
+		}
+		multicalls[chainCfg.ChainID] = common.HexToAddress(address)
+	}
+
+	return &balanceReader{
+		clientManager: clientManager,
+		multicalls:    multicalls,
+	}, nil
+}
+
+// ReadBalances 读取原生币与代币余额
+func (r *balanceReader) ReadBalances(
+	ctx context.Context,
+	chainID uint,
+	owner common.Address,
+	tokens []common.Address,
+) (*BalanceSnapshot, error) {
+	if multicall, ok := r.multicalls[chainID]; ok {
+		return r.readWithMulticall(ctx, chainID, multicall, owner, tokens)
+	}
+	return r.readSequentially(ctx, chainID, owner, tokens)
+}
+
+// readWithMulticall 通过 Multicall3.aggregate3 在一次 eth_call 中读取全部余额，
+// 原生币余额使用 Multicall3.getEthBalance 与代币余额一起批量读取
+func (r *balanceReader) readWithMulticall(
+	ctx context.Context,
+	chainID uint,
+	multicall common.Address,
+	owner common.Address,
+	tokens []common.Address,
+) (*BalanceSnapshot, error) {
+	client, err := r.clientManager.GetClient(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for chain %d: %w", chainID, err)
+	}
+
+	// 1. 组装子调用：第 0 个为原生币余额，其余按 tokens 顺序
+	nativeCall, err := multicall3ABI.Pack("getEthBalance", owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getEthBalance: %w", err)
+	}
+	calls := []multicall3Call{{Target: multicall, AllowFailure: false, CallData: nativeCall}}
+
+	balanceOfCall, err := erc20ABI.Pack("balanceOf", owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack balanceOf: %w", err)
+	}
+	for _, token := range tokens {
+		calls = append(calls, multicall3Call{Target: token, AllowFailure: true, CallData: balanceOfCall})
+	}
+
+	data, err := multicall3ABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack aggregate3: %w", err)
+	}
+
+	// 2. 发起一次 eth_call
+	timeoutCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	output, err := client.CallContract(timeoutCtx, ethereum.CallMsg{To: &multicall, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call multicall3 aggregate3: %w", err)
+	}
+
+	var results []multicall3Result
+	if err := multicall3ABI.UnpackIntoInterface(&results, "aggregate3", output); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate3 result: %w", err)
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(calls))
+	}
+
+	// 3. 解码各子调用结果
+	snapshot := &BalanceSnapshot{Tokens: make([]TokenBalanceResult, len(tokens))}
+
+	snapshot.Native, err = decodeUint256(multicall3ABI, "getEthBalance", results[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode native balance: %w", err)
+	}
+
+	for i, token := range tokens {
+		balance, err := decodeUint256(erc20ABI, "balanceOf", results[i+1])
+		snapshot.Tokens[i] = TokenBalanceResult{Token: token, Balance: balance, Err: err}
+	}
+
+	return snapshot, nil
+}
+
+// readSequentially 在未配置 Multicall3 的链上逐个查询余额
+func (r *balanceReader) readSequentially(
+	ctx context.Context,
+	chainID uint,
+	owner common.Address,
+	tokens []common.Address,
+) (*BalanceSnapshot, error) {
+	native, err := r.clientManager.GetBalanceByAddress(ctx, chainID, owner.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &BalanceSnapshot{
+		Native: native,
+		Tokens: make([]TokenBalanceResult, len(tokens)),
+	}
+
+	for i, token := range tokens {
+		balance, err := r.clientManager.BalanceOf(ctx, chainID, token, owner)
+		snapshot.Tokens[i] = TokenBalanceResult{Token: token, Balance: balance, Err: err}
+	}
+
+	return snapshot, nil
+}
+
+// decodeUint256 解码 aggregate3 子调用返回的单个 uint256
+func decodeUint256(contractABI abi.ABI, method string, result multicall3Result) (*big.Int, error) {
+	if !result.Success {
+		return nil, errMulticallCallFailed
+	}
+
+	var value *big.Int
+	if err := contractABI.UnpackIntoInterface(&value, method, result.ReturnData); err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,168 @@
+package web3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bwmspring/go-web3-wallet-backend/config"
+)
+
+// defaultNativeSymbol 是未配置 native_symbol 时使用的原生币符号
+const defaultNativeSymbol = "ETH"
+
+// ErrTokenNotFound 表示请求的代币未在该链的配置中注册
+var ErrTokenNotFound = errors.New("token not registered on this chain")
+
+// Token 描述一个已注册的 ERC-20 代币
+type Token struct {
+	ChainID  uint
+	Symbol   string
+	Address  common.Address
+	Decimals uint8
+}
+
+// TokenRegistry 定义了按链查询已注册代币的接口
+type TokenRegistry interface {
+	// Lookup 根据代币符号（不区分大小写）或合约地址查找代币，未注册时返回 ErrTokenNotFound
+	Lookup(ctx context.Context, chainID uint, symbolOrAddress string) (*Token, error)
+	// List 返回链上已注册的全部代币
+	List(ctx context.Context, chainID uint) ([]Token, error)
+	// NativeSymbol 返回链的原生币符号
+	NativeSymbol(chainID uint) string
+}
+
+// tokenEntry 是注册表中的一项，decimals 未配置时在首次使用时从合约读取并缓存
+type tokenEntry struct {
+	symbol      string
+	address     common.Address
+	decimals    uint8
+	hasDecimals bool
+}
+
+// tokenRegistry 实现了 TokenRegistry 接口
+type tokenRegistry struct {
+	clientManager ClientManager
+
+	tokens        map[uint][]*tokenEntry // {ChainID: 代币列表}，保持配置中的顺序
+	nativeSymbols map[uint]string
+	mu            sync.RWMutex // 保护 tokenEntry 中懒加载的 decimals
+}
+
+var _ TokenRegistry = (*tokenRegistry)(nil)
+
+// NewTokenRegistry 根据链配置构建代币注册表，配置中的合约地址非法或符号重复时返回错误
+func NewTokenRegistry(clientManager ClientManager, chainConfigs []config.BlockchainConfig) (TokenRegistry, error) {
+	registry := &tokenRegistry{
+		clientManager: clientManager,
+		tokens:        make(map[uint][]*tokenEntry),
+		nativeSymbols: make(map[uint]string),
+	}
+
+	for _, chainCfg := range chainConfigs {
+		if chainCfg.NativeSymbol != "" {
+			registry.nativeSymbols[chainCfg.ChainID] = chainCfg.NativeSymbol
+		}
+
+		seen := make(map[string]bool)
+		for _, tokenCfg := range chainCfg.Tokens {
+			if tokenCfg.Symbol == "" || !common.IsHexAddress(tokenCfg.Address) {
+				return nil, fmt.Errorf("invalid token config on chain %d: symbol=%q address=%q",
+					chainCfg.ChainID, tokenCfg.Symbol, tokenCfg.Address)
+			}
+
+			key := strings.ToUpper(tokenCfg.Symbol)
+			if seen[key] {
+				return nil, fmt.Errorf("duplicate token symbol %s on chain %d", tokenCfg.Symbol, chainCfg.ChainID)
+			}
+			seen[key] = true
+
+			entry := &tokenEntry{
+				symbol:  tokenCfg.Symbol,
+				address: common.HexToAddress(tokenCfg.Address),
+			}
+			if tokenCfg.Decimals != nil {
+				entry.decimals = *tokenCfg.Decimals
+				entry.hasDecimals = true
+			}
+
+			registry.tokens[chainCfg.ChainID] = append(registry.tokens[chainCfg.ChainID], entry)
+		}
+	}
+
+	return registry, nil
+}
+
+// Lookup 根据代币符号或合约地址查找代币
+func (r *tokenRegistry) Lookup(ctx context.Context, chainID uint, symbolOrAddress string) (*Token, error) {
+	var match *tokenEntry
+
+	isAddress := common.IsHexAddress(symbolOrAddress)
+	for _, entry := range r.tokens[chainID] {
+		if isAddress && entry.address == common.HexToAddress(symbolOrAddress) ||
+			!isAddress && strings.EqualFold(entry.symbol, symbolOrAddress) {
+			match = entry
+			break
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, symbolOrAddress)
+	}
+
+	return r.resolve(ctx, chainID, match)
+}
+
+// List 返回链上已注册的全部代币
+func (r *tokenRegistry) List(ctx context.Context, chainID uint) ([]Token, error) {
+	entries := r.tokens[chainID]
+	tokens := make([]Token, 0, len(entries))
+
+	for _, entry := range entries {
+		token, err := r.resolve(ctx, chainID, entry)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, nil
+}
+
+// NativeSymbol 返回链的原生币符号
+func (r *tokenRegistry) NativeSymbol(chainID uint) string {
+	if symbol, ok := r.nativeSymbols[chainID]; ok {
+		return symbol
+	}
+	return defaultNativeSymbol
+}
+
+// resolve 返回注册项对应的 Token，必要时从合约读取 decimals 并缓存
+func (r *tokenRegistry) resolve(ctx context.Context, chainID uint, entry *tokenEntry) (*Token, error) {
+	r.mu.RLock()
+	decimals, ok := entry.decimals, entry.hasDecimals
+	r.mu.RUnlock()
+
+	if !ok {
+		onChain, err := r.clientManager.Decimals(ctx, chainID, entry.address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load decimals for token %s: %w", entry.symbol, err)
+		}
+
+		r.mu.Lock()
+		entry.decimals, entry.hasDecimals = onChain, true
+		r.mu.Unlock()
+
+		decimals = onChain
+	}
+
+	return &Token{
+		ChainID:  chainID,
+		Symbol:   entry.symbol,
+		Address:  entry.address,
+		Decimals: decimals,
+	}, nil
+}